@@ -0,0 +1,56 @@
+package config
+
+import "net/http"
+
+// Token locations of TokenSource.From.
+const (
+	TokenFromHeader = "header" // An HTTP header, e.g. "Authorization", the token optionally after the "Bearer" scheme
+	TokenFromCookie = "cookie" // A cookie, e.g. the one set by TokenCookie
+	TokenFromQuery  = "query"  // A query parameter, for clients that can only configure a URL, e.g. webhooks
+)
+
+// DefaultTokenCookie is the name of the token cookie when none is configured.
+const DefaultTokenCookie = "go_api_token"
+
+// Auth defines where app tokens are read from and how they are handed to browsers.
+type Auth struct {
+	TokenSources []TokenSource `json:"token_sources"` // Locations tried in order, the first one present is used; defaults to the Authorization header. WebSocket upgrades fall back to the "token" query parameter
+	Cookie       TokenCookie   `json:"cookie"`        // Cookie set with the token when one is issued
+}
+
+// TokenSource is a location of the app token in a request.
+type TokenSource struct {
+	From string `json:"from"` // TokenFromHeader, TokenFromCookie or TokenFromQuery
+	Name string `json:"name"` // Name of the header, cookie or query parameter
+}
+
+// TokenCookie defines the HttpOnly cookie the token endpoint sets for browser apps, which then
+// authenticate with a TokenFromCookie source of the same name instead of a header.
+//
+// Browsers attach cookies to requests forged by other sites, so cookie authentication must be
+// protected against CSRF: keep SameSite "strict" or "lax", never serve state changes on GET,
+// and never allow credentials in CORS for untrusted origins. On top of that, the CSRF
+// middleware requires the "X-CSRF-Token" header on unsafe requests authenticated by a cookie.
+type TokenCookie struct {
+	Enable   bool   `json:"enable"`    // Whether the token endpoint sets the cookie
+	Name     string `json:"name"`      // Name of the cookie; defaults to DefaultTokenCookie
+	Domain   string `json:"domain"`    // Domain of the cookie; the host of the request when empty
+	Path     string `json:"path"`      // Path of the cookie; defaults to "/"
+	Secure   bool   `json:"secure"`    // Whether the cookie is only sent over HTTPS; disable it only for local HTTP development
+	SameSite string `json:"same_site"` // "strict", "lax" or "none"; defaults to "strict"
+}
+
+// SameSiteMode returns the SameSite attribute of the cookie; "none" requires Secure in browsers.
+//
+// Returns:
+//   - http.SameSite: The mode of SameSite, http.SameSiteStrictMode when unset or unknown.
+func (c TokenCookie) SameSiteMode() http.SameSite {
+	switch c.SameSite {
+	case "lax":
+		return http.SameSiteLaxMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteStrictMode
+	}
+}
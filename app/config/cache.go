@@ -4,8 +4,15 @@ import "time"
 
 // Cache defines caching configuration options.
 type Cache struct {
-	Driver string `json:"driver"` // Cache driver
-	Prefix string `json:"prefix"` // Cache key prefix
+	Driver   string        `json:"driver"`   // Cache driver
+	Prefix   string        `json:"prefix"`   // Cache key prefix
+	Response ResponseCache `json:"response"` // Response cache middleware configuration
+}
+
+// ResponseCache defines configuration options of the response cache middleware.
+type ResponseCache struct {
+	Enable         bool `json:"enable"`           // Whether routes using the middleware are cached
+	CacheSetCookie bool `json:"cache_set_cookie"` // Also cache responses setting cookies; only safe if the cookies are not per client
 }
 
 // Redis defines Redis configuration options.
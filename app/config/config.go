@@ -24,14 +24,23 @@ var config *Config // Global configuration variable
 
 // Config represents the entire application configuration.
 type Config struct {
-	System    SysConfig  `json:"system"`    // System-wide configuration
-	Log       LogConfig  `json:"log"`       // Logging configuration
-	Databases []Database `json:"databases"` // Database configurations
-	Cache     Cache      `json:"cache"`     // Caching configuration
-	Redis     []Redis    `json:"redis"`     // Redis configurations
-	Kafka     Kafka      `json:"kafka"`     // Kafka configuration
-	Monitor   Monitor    `json:"monitor"`   // Monitoring configuration
-	Notify    Notify     `json:"notify"`    // Notify configuration
+	System     SysConfig        `json:"system"`      // System-wide configuration
+	Log        LogConfig        `json:"log"`         // Logging configuration
+	Databases  []Database       `json:"databases"`   // Database configurations
+	Cache      Cache            `json:"cache"`       // Caching configuration
+	Redis      []Redis          `json:"redis"`       // Redis configurations
+	Kafka      Kafka            `json:"kafka"`       // Kafka configuration
+	Monitor    Monitor          `json:"monitor"`     // Monitoring configuration
+	Notify     Notify           `json:"notify"`      // Notify configuration
+	HTTPClient HTTPClient       `json:"http_client"` // Outbound HTTP client configuration
+	Schedule   Schedule         `json:"schedule"`    // Job scheduler configuration
+	Seeder     Seeder           `json:"seeder"`      // Startup data seeding configuration
+	IPFilter   IPFilter         `json:"ip_filter"`   // Client IP allow and deny lists
+	WebSocket  WebSocket        `json:"websocket"`   // WebSocket hub configuration
+	Limits     map[string]Limit `json:"limits"`      // Row limits of the list endpoints, by resource, e.g. "app"
+	Features   FeatureFlags     `json:"features"`    // Feature flags
+	Docs       Docs             `json:"docs"`        // API documentation page
+	Auth       Auth             `json:"auth"`        // Token locations and token cookie
 }
 
 // LoadConfig loads the application configuration from a JSON file.
@@ -100,12 +109,14 @@ func LoadConfig() (*Config, error) {
 }
 
 // checkConfig performs validation checks on the loaded configuration.
-// Currently, it only checks if the JwtSecret is set.
+// It checks the required system settings, and that a secret grace period has the "go-api"
+// Redis to retire secrets in.
 //
 // Parameters:
 //   - conf: *Config - A pointer to the configuration structure to check.
 //
-// The function will panic if the JwtSecret is empty.
+// Returns:
+//   - error: An error describing the first invalid setting.
 func checkConfig(conf *Config) error {
 	if conf.System.JwtSecret == "" {
 		return fmt.Errorf("jwtSecret cannot be null")
@@ -127,9 +138,25 @@ func checkConfig(conf *Config) error {
 		return fmt.Errorf("TokenExpire cannot be less than or equal to zero")
 	}
 
+	// Rotated secrets are retired in the "go-api" Redis during their grace period
+	if conf.System.AppSecretGrace > 0 && !conf.redisEnabled("go-api") {
+		return fmt.Errorf("app_secret_grace requires the go-api redis to be enabled")
+	}
+
 	return nil
 }
 
+// redisEnabled reports whether the Redis named name is configured and enabled.
+func (c *Config) redisEnabled(name string) bool {
+	for _, r := range c.Redis {
+		if r.Name == name && r.Enable {
+			return true
+		}
+	}
+
+	return false
+}
+
 // Get returns the global configuration object.
 // This function should be called after LoadConfig has been executed.
 //
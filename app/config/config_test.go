@@ -0,0 +1,41 @@
+package config
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCheckConfigAppSecretGrace(t *testing.T) {
+	tests := []struct {
+		name    string
+		grace   time.Duration
+		redis   []Redis
+		wantErr bool
+	}{
+		{name: "no grace without redis", grace: 0},
+		{name: "grace with redis", grace: 3600, redis: []Redis{{Name: "go-api", Enable: true}}},
+		{name: "grace without redis", grace: 3600, wantErr: true},
+		{name: "grace with disabled redis", grace: 3600, redis: []Redis{{Name: "go-api"}}, wantErr: true},
+		{name: "grace with another redis", grace: 3600, redis: []Redis{{Name: "dudu", Enable: true}}, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conf := &Config{
+				System: SysConfig{
+					JwtSecret:      "jwt-s3cr3t",
+					ReadTimeout:    60,
+					WriteTimeout:   60,
+					HTTPPort:       ":8080",
+					TokenExpire:    168,
+					AppSecretGrace: tt.grace,
+				},
+				Redis: tt.redis,
+			}
+
+			if err := checkConfig(conf); (err != nil) != tt.wantErr {
+				t.Errorf("checkConfig() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,6 @@
+package config
+
+// Docs defines configuration options for the API documentation.
+type Docs struct {
+	Enable bool `json:"enable"` // Whether the OpenAPI spec and, in "swaggerui" builds, Swagger UI are served at /go-api/internal/docs to authenticated apps
+}
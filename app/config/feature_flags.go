@@ -0,0 +1,10 @@
+package config
+
+import "time"
+
+// FeatureFlags defines the feature flags and their Redis overrides.
+type FeatureFlags struct {
+	Flags    map[string]bool `json:"flags"`     // Default state of each flag; unknown flags are disabled
+	Redis    string          `json:"redis"`     // Redis name holding the overrides in the "feature_flags" hash; empty disables them
+	CacheTTL time.Duration   `json:"cache_ttl"` // Time the overrides are cached (in seconds); defaults to 10
+}
@@ -0,0 +1,13 @@
+package config
+
+import "time"
+
+// HTTPClient defines configuration options for the shared outbound HTTP client.
+type HTTPClient struct {
+	Timeout          time.Duration `json:"timeout"`             // Request timeout (in seconds)
+	RetryCount       int           `json:"retry_count"`         // Number of retries after a failed request
+	RetryWaitTime    time.Duration `json:"retry_wait_time"`     // Initial wait between retries (in milliseconds)
+	RetryMaxWaitTime time.Duration `json:"retry_max_wait_time"` // Maximum wait between retries (in milliseconds)
+	UserAgent        string        `json:"user_agent"`          // User-Agent header sent with every request
+	Proxy            string        `json:"proxy"`               // Proxy URL, e.g. "http://127.0.0.1:7890"; empty disables it
+}
@@ -0,0 +1,8 @@
+package config
+
+// IPFilter defines configuration options for the client IP allow and deny lists.
+type IPFilter struct {
+	Enable bool     `json:"enable"` // Whether the filter is applied
+	Allow  []string `json:"allow"`  // CIDRs or IPs allowed; empty allows every address not denied
+	Deny   []string `json:"deny"`   // CIDRs or IPs denied; they take precedence over allow
+}
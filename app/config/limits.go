@@ -0,0 +1,10 @@
+package config
+
+// Limit bounds the rows the list endpoints of a resource may fetch.
+//
+// Zero values fall back to the global settings: System.MaxPageSize for MaxPageSize, and the
+// controller default of 20 rows per page.
+type Limit struct {
+	DefaultPageSize int `json:"default_page_size"` // Page size used when the request omits it
+	MaxPageSize     int `json:"max_page_size"`     // Larger page sizes are clamped to it
+}
@@ -2,7 +2,10 @@ package config
 
 // LogConfig defines logging configuration options.
 type LogConfig struct {
-	Driver  string `json:"driver"`   // Log driver: "stdout" or "file"
-	Level   string `json:"level"`    // Log level: "debug", "info", "warn", "error", "fatal"
-	LogPath string `json:"log_path"` // Log file path (only used when Driver is "file")
+	Driver       string         `json:"driver"`        // Log driver: "stdout" or "file"
+	Level        string         `json:"level"`         // Log level: "debug", "info", "warn", "error", "fatal"
+	LogPath      string         `json:"log_path"`      // Log file path (only used when Driver is "file")
+	BodyMaxSize  int            `json:"body_max_size"` // Maximum size in bytes of a logged request body, 0 for no limit
+	RedactFields []string       `json:"redact_fields"` // Body and log fields masked in the logs; empty uses sanitize.DefaultFields
+	Sampling     map[string]int `json:"sampling"`      // Lines kept per level by the access and auth logs, 1 in N, e.g. {"debug": 10}
 }
@@ -2,19 +2,21 @@ package config
 
 // Notify defines notification configuration options.
 type Notify struct {
-	DefaultChannel string `json:"default_channel"`
-	DefaultLevel   string `json:"default_level"`
-	Lark           Lark   `json:"lark"`
+	DefaultChannel string              `json:"default_channel"`
+	DefaultLevel   string              `json:"default_level"` // Also the minimum level of alerts
+	Routes         map[string][]string `json:"routes"`        // Channels per alert level; default_channel when absent
+	Lark           Lark                `json:"lark"`
 }
 
 // Lark defines Lark configuration options.
 type Lark struct {
-	Enable                 bool               `json:"enable"`
-	DefaultSendChannelName string             `json:"default_send_channel_name"`
-	ChannelSize            int                `json:"channel_size"`
-	PoolSize               int                `json:"pool_size"`
-	BotWebhooks            map[string]string  `json:"bot_webhooks"`
-	Larks                  map[string]LarkApp `json:"larks"`
+	Enable                 bool                `json:"enable"`
+	DefaultSendChannelName string              `json:"default_send_channel_name"`
+	ChannelSize            int                 `json:"channel_size"`
+	PoolSize               int                 `json:"pool_size"`
+	BotWebhooks            map[string]string   `json:"bot_webhooks"`
+	Larks                  map[string]LarkApp  `json:"larks"`
+	Cards                  map[string]LarkCard `json:"cards"`
 }
 
 // LarkApp defines Lark application configuration options.
@@ -23,3 +25,13 @@ type LarkApp struct {
 	AppID     string `json:"app_id"`
 	AppSecret string `json:"app_secret"`
 }
+
+// LarkCard defines how an alert type is sent as a Lark interactive card.
+//
+// The map key in Lark.Cards is the alert type, e.g. "panic_report" or "login_alert".
+type LarkCard struct {
+	Enable          bool   `json:"enable"`            // Send a card; false sends the alert as plain text
+	Color           string `json:"color"`             // Header color, overrides the one chosen by the caller
+	SendChannelName string `json:"send_channel_name"` // Bot or app name; empty uses default_send_channel_name
+	SendTo          string `json:"send_to"`           // Receiver user ID, only used by Lark apps
+}
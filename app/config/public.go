@@ -0,0 +1,41 @@
+package config
+
+// Public is the subset of the configuration the frontends may read, served by the public config
+// endpoint. Every field is copied explicitly by Config.Public: never embed a configuration
+// struct here, as a field added to it later would be exposed with it.
+type Public struct {
+	Name        string           `json:"name"`          // Application name
+	Version     string           `json:"version"`       // Application version
+	Env         string           `json:"env"`           // Runtime environment
+	DefaultLang string           `json:"default_lang"`  // Language of the messages when the request sets none
+	MaxPageSize int              `json:"max_page_size"` // Upper bound for page_size, 0 for the default of 200
+	Limits      map[string]Limit `json:"limits"`        // Row limits of the list endpoints, by resource
+	WebSocket   bool             `json:"websocket"`     // Whether the WebSocket notifications endpoint is served
+}
+
+// Public returns the settings of c that are safe to expose to the frontends.
+//
+// Returns:
+//   - Public: The whitelisted settings.
+//
+// Example:
+//
+//	api.GET("config/public", func(c *gin.Context) {
+//	    ctx.I18n.JSON(c, e.SUCCESS, ctx.Config.Public(), nil)
+//	})
+func (c *Config) Public() Public {
+	limits := make(map[string]Limit, len(c.Limits))
+	for resource, limit := range c.Limits {
+		limits[resource] = limit
+	}
+
+	return Public{
+		Name:        c.System.Name,
+		Version:     c.System.Version,
+		Env:         c.System.Env,
+		DefaultLang: c.System.DefaultLang,
+		MaxPageSize: c.System.MaxPageSize,
+		Limits:      limits,
+		WebSocket:   c.WebSocket.Enable,
+	}
+}
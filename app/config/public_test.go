@@ -0,0 +1,65 @@
+package config
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestPublicLeaksNoSecret(t *testing.T) {
+	cfg := &Config{
+		System: SysConfig{
+			Name:      "go-api",
+			Version:   "1.0.0",
+			JwtSecret: "jwt-s3cr3t",
+		},
+		Databases: []Database{{DbPassword: "db-s3cr3t"}},
+		Redis:     []Redis{{Auth: "redis-s3cr3t"}},
+		Limits:    map[string]Limit{"app": {MaxPageSize: 100}},
+	}
+
+	body, err := json.Marshal(cfg.Public())
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+
+	var fields map[string]interface{}
+	if err = json.Unmarshal(body, &fields); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+
+	for _, name := range fieldNames(fields) {
+		for _, word := range []string{"secret", "password", "auth", "key", "token", "credential", "dsn", "host"} {
+			if strings.Contains(strings.ToLower(name), word) {
+				t.Errorf("Public() exposes the field %q", name)
+			}
+		}
+	}
+
+	if strings.Contains(string(body), "s3cr3t") {
+		t.Errorf("Public() = %s, leaks a secret value", body)
+	}
+
+	if fields["name"] != "go-api" || fields["version"] != "1.0.0" {
+		t.Errorf("Public() = %s, want the name and version", body)
+	}
+}
+
+// fieldNames returns the names of the fields of a decoded JSON value, at any depth.
+func fieldNames(value interface{}) []string {
+	var names []string
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, item := range v {
+			names = append(names, name)
+			names = append(names, fieldNames(item)...)
+		}
+	case []interface{}:
+		for _, item := range v {
+			names = append(names, fieldNames(item)...)
+		}
+	}
+
+	return names
+}
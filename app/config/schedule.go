@@ -0,0 +1,9 @@
+package config
+
+// Schedule defines configuration options for the job scheduler.
+type Schedule struct {
+	LockDriver string `json:"lock_driver"` // Backend of the single-server job lock: "redis" (default) or "mongo"
+	LockConn   string `json:"lock_conn"`   // Redis name or MongoDB database name used by the lock; defaults to the application name
+	// AlertInterval is the minimum time in seconds between two failure alerts of the same job; defaults to 600
+	AlertInterval int `json:"alert_interval"`
+}
@@ -0,0 +1,7 @@
+package config
+
+// Seeder defines configuration options for seeding data at startup.
+type Seeder struct {
+	Enable bool   `json:"enable"`  // Allow seeders to run; keep disabled in production
+	DbName string `json:"db_name"` // Name of the MySQL database to seed
+}
@@ -19,5 +19,17 @@ type SysConfig struct {
 	EnvKey       string        `json:"env_key"`       // Environment key for reading runtime environment
 	JwtSecret    string        `json:"jwt_secret"`    // JWT secret for authentication
 	TokenExpire  time.Duration `json:"token_expire"`  // JWT token expiration time (in seconds)
+	JwtIssuer    string        `json:"jwt_issuer"`    // JWT "iss" claim; validated only when set
+	JwtAudience  string        `json:"jwt_audience"`  // JWT "aud" claim; validated only when set
+	JwtLeeway    time.Duration `json:"jwt_leeway"`    // Clock skew tolerated when validating exp/nbf/iat (in seconds)
 	Env          string        `json:"env"`           // Runtime environment
+	MaxPageSize  int           `json:"max_page_size"` // Upper bound for page_size on paginated endpoints
+
+	AppSecretGrace     time.Duration `json:"app_secret_grace"`     // Time a rotated app secret stays valid (in seconds); 0 revokes it at once
+	RequestTimeout     time.Duration `json:"request_timeout"`      // Deadline of the request context (in seconds); 0 disables it
+	RequestTimeoutSkip []string      `json:"request_timeout_skip"` // Route paths without deadline, e.g. long-poll and export routes
+	// TrustedProxies lists the CIDRs or IPs of the proxies whose X-Forwarded-For and X-Real-IP
+	// headers are used to resolve the client IP. When empty the headers are ignored, so clients
+	// can't spoof their IP, and the client IP is the address of the peer.
+	TrustedProxies []string `json:"trusted_proxies"`
 }
@@ -0,0 +1,12 @@
+package config
+
+import "time"
+
+// WebSocket defines configuration options for the WebSocket hub.
+type WebSocket struct {
+	Enable         bool          `json:"enable"`          // Whether the WebSocket endpoints are served and alerts are pushed to them
+	PingInterval   time.Duration `json:"ping_interval"`   // Interval between keepalive pings (in seconds)
+	WriteTimeout   time.Duration `json:"write_timeout"`   // Maximum time to write a message or a ping (in seconds)
+	SendBuffer     int           `json:"send_buffer"`     // Messages queued per connection; a connection falling further behind is closed
+	AllowedOrigins []string      `json:"allowed_origins"` // Origins browsers may connect from, e.g. "https://admin.example.com"; clients sending no Origin are always accepted
+}
@@ -3,10 +3,17 @@ package http
 import (
 	"context"
 	"github.com/gin-gonic/gin"
+	"github.com/go-resty/resty/v2"
 	"github.com/qiniu/qmgo"
 	"github.com/seakee/go-api/app/config"
 	"github.com/seakee/go-api/app/http/middleware"
-	"github.com/sk-pkg/i18n"
+	"github.com/seakee/go-api/app/http/ws"
+	"github.com/seakee/go-api/app/pkg/alert"
+	"github.com/seakee/go-api/app/pkg/featureflag"
+	"github.com/seakee/go-api/app/pkg/health"
+	"github.com/seakee/go-api/app/pkg/identity"
+	"github.com/seakee/go-api/app/pkg/lang"
+	"github.com/seakee/go-api/app/service/audit"
 	"github.com/sk-pkg/kafka"
 	"github.com/sk-pkg/logger"
 	"github.com/sk-pkg/notify"
@@ -18,28 +25,44 @@ import (
 type Context struct {
 	Logger        *logger.Manager
 	Redis         map[string]*redis.Manager
-	I18n          *i18n.Manager
+	I18n          *lang.Manager
 	MysqlDB       map[string]*gorm.DB
 	MongoDB       map[string]*qmgo.Database
+	MongoClient   map[string]*qmgo.Client
 	Middleware    middleware.Middleware
 	KafkaProducer *kafka.Manager
 	Notify        *notify.Manager
+	Alert         *alert.Alerter
 	Config        *config.Config
 	Engine        *gin.Engine
+	HTTPClient    *resty.Client
+	WSHub         *ws.Hub
+	Health        *health.Checker
+	Flags         *featureflag.Flags
 }
 
 // Context creates a new context with the trace ID from the gin.Context.
 //
+// The context derives from the request context, so it carries the request deadline set by
+// the Timeout middleware and is cancelled when the client goes away. The app_id authenticated
+// by CheckAppAuth, if any, is attached as the audit actor; its identity.Identity is carried by
+// the request context already.
+//
 // Parameters:
 //   - c: *gin.Context - The gin context containing the trace ID.
 //
 // Returns:
 //   - context.Context: A new context with the trace ID added.
 func (ctx *Context) Context(c *gin.Context) context.Context {
+	reqCtx := c.Request.Context()
+	if user, ok := identity.CurrentUser(reqCtx); ok {
+		reqCtx = audit.WithActor(reqCtx, user.UserID)
+	}
+
 	traceID, ok := c.Get("trace_id")
 	if !ok {
-		return context.Background()
+		return reqCtx
 	}
 
-	return context.WithValue(context.Background(), logger.TraceIDKey, traceID.(string))
+	return context.WithValue(reqCtx, logger.TraceIDKey, traceID.(string))
 }
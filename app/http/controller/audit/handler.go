@@ -0,0 +1,46 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package audit provides the handlers of the audit trail.
+package audit
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/seakee/go-api/app/http"
+	"github.com/seakee/go-api/app/http/controller"
+	"github.com/seakee/go-api/app/service/audit"
+)
+
+// Handler interface defines the methods that should be implemented by the audit handler.
+type Handler interface {
+	i()
+	List() gin.HandlerFunc
+}
+
+// handler struct implements the Handler interface.
+type handler struct {
+	controller.BaseController
+	service audit.Service
+}
+
+// i is a dummy method to satisfy the Handler interface.
+func (h handler) i() {}
+
+// NewHandler creates and returns a new Handler instance.
+//
+// Parameters:
+//   - appCtx: *http.Context - The application context.
+//
+// Returns:
+//   - Handler: A new Handler instance.
+func NewHandler(appCtx *http.Context) Handler {
+	return &handler{
+		BaseController: controller.BaseController{
+			AppCtx: appCtx,
+			Logger: appCtx.Logger,
+			I18n:   appCtx.I18n,
+		},
+		service: audit.NewService(appCtx.MongoDB["go-api"], appCtx.Logger),
+	}
+}
@@ -0,0 +1,57 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package audit
+
+import (
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seakee/go-api/app/http/controller"
+	"github.com/seakee/go-api/app/model/audit"
+)
+
+// ListReqParams defines the structure for audit record list request parameters.
+//
+// StartTime and EndTime are RFC 3339 times, e.g. 2024-01-02T15:04:05Z.
+type ListReqParams struct {
+	controller.PageParams
+	Actor     string    `json:"actor" form:"actor"`
+	Action    string    `json:"action" form:"action"`
+	Target    string    `json:"target" form:"target"`
+	StartTime time.Time `json:"start_time" form:"start_time"`
+	EndTime   time.Time `json:"end_time" form:"end_time" binding:"omitempty,gtfield=StartTime"`
+}
+
+// List returns a gin.HandlerFunc that lists audit records, newest first.
+//
+// Records can be filtered by actor, action and target, and by a [start_time, end_time) range.
+//
+// Returns:
+//   - gin.HandlerFunc: A function that handles the HTTP request for listing audit records.
+func (h handler) List() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var params ListReqParams
+		if err := c.ShouldBindQuery(&params); err != nil {
+			h.Respond(c, nil, h.BindingError(c, err))
+			return
+		}
+
+		params.NormalizeFor("audit")
+
+		records, total, err := h.service.List(h.Context(c), audit.Filter{
+			Actor:     params.Actor,
+			Action:    params.Action,
+			Target:    params.Target,
+			StartTime: params.StartTime,
+			EndTime:   params.EndTime,
+		}, params.Page, params.PageSize)
+		if err != nil {
+			h.Respond(c, nil, err)
+			return
+		}
+
+		controller.PaginatedJSON(&h.BaseController, c, records, total, params.Page, params.PageSize)
+	}
+}
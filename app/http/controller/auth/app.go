@@ -5,12 +5,22 @@
 package auth
 
 import (
+	"context"
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/seakee/go-api/app/http/controller"
+	"github.com/seakee/go-api/app/http/middleware"
 	"github.com/seakee/go-api/app/model/auth"
 	"github.com/seakee/go-api/app/pkg/e"
-	"github.com/sk-pkg/util"
+	"github.com/seakee/go-api/app/pkg/query"
+	service "github.com/seakee/go-api/app/service/auth"
+	"go.uber.org/zap"
 )
 
+// AppCacheTag tags the cached responses listing apps; they are busted when an app changes.
+const AppCacheTag = "app"
+
 // StoreAppReqParams defines the structure for storing app request parameters.
 type StoreAppReqParams struct {
 	AppName     string `json:"app_name" form:"app_name" binding:"required"`
@@ -27,56 +37,206 @@ type StoreAppRepData struct {
 // Create returns a gin.HandlerFunc that handles the creation of a new app.
 //
 // This function performs the following steps:
-// 1. Binds the JSON request to StoreAppReqParams.
-// 2. Checks if an app with the given name already exists.
-// 3. If the app doesn't exist, creates a new app with generated AppID and AppSecret.
-// 4. Returns the newly created AppID and AppSecret.
+// 1. Binds the JSON request to StoreAppReqParams, responding with field-level details if it is invalid.
+// 2. Creates the app through AppService.CreateApp, which rejects a taken name and audits the creation.
+// 3. Returns the newly created AppID and AppSecret.
 //
 // Returns:
 //   - gin.HandlerFunc: A function that handles the HTTP request for creating an app.
 func (h handler) Create() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var params *StoreAppReqParams
-		var err error
-		var exists bool
-		var data *StoreAppRepData
+		var params StoreAppReqParams
+		if err := c.ShouldBindJSON(&params); err != nil {
+			h.Respond(c, nil, h.BindingError(c, err))
+			return
+		}
+
+		ctx := h.Context(c)
 
-		errCode := e.InvalidParams
+		app, err := h.service.CreateApp(ctx, &service.CreateAppParams{
+			AppName:     params.AppName,
+			Description: params.Description,
+			RedirectUri: params.RedirectUri,
+		})
+		if err != nil {
+			h.Respond(c, nil, err)
+			return
+		}
+
+		h.bustAppCache(ctx)
+
+		h.Respond(c, &StoreAppRepData{AppID: app.AppID, AppSecret: app.AppSecret}, nil)
+	}
+}
+
+// RotateSecretReqParams defines the structure for app secret rotation request parameters.
+type RotateSecretReqParams struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+// RotateSecretRepData defines the structure for the response data when rotating an app secret.
+type RotateSecretRepData struct {
+	AppSecret string `json:"app_secret"`
+}
+
+// RotateSecret returns a gin.HandlerFunc that replaces the secret of an app.
+//
+// The new secret is only returned by this response. The old secret stays valid for the
+// configured grace period (System.AppSecretGrace).
+//
+// Returns:
+//   - gin.HandlerFunc: A function that handles the HTTP request for rotating an app secret.
+func (h handler) RotateSecret() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var params RotateSecretReqParams
+		if err := c.ShouldBindUri(&params); err != nil {
+			h.Respond(c, nil, h.BindingError(c, err))
+			return
+		}
 
 		ctx := h.Context(c)
 
-		// Bind JSON request to StoreAppReqParams
-		if err = c.ShouldBindJSON(&params); err == nil {
-			// Check if app already exists
-			exists, err = h.repo.ExistAppByName(ctx, params.AppName)
-			errCode = e.ServerAppAlreadyExists
-			if !exists {
-				// Create new app
-				app := &auth.App{
-					AppName:     params.AppName,
-					AppID:       "go-api-" + util.RandLowStr(8),
-					AppSecret:   util.RandUpStr(32),
-					RedirectUri: params.RedirectUri,
-					Description: params.Description,
-					Status:      1,
-				}
-
-				// Save app to repository
-				_, err = h.repo.Create(ctx, app)
-				errCode = e.BUSY
-				if err == nil {
-					errCode = e.SUCCESS
-
-					// Prepare response data
-					data = &StoreAppRepData{
-						AppID:     app.AppID,
-						AppSecret: app.AppSecret,
-					}
-				}
-			}
+		secret, err := h.service.RotateSecret(ctx, params.ID)
+		if err != nil {
+			h.Respond(c, nil, err)
+			return
 		}
 
-		// Send JSON response
-		h.I18n.JSON(c, errCode, data, err)
+		h.bustAppCache(ctx)
+
+		h.Respond(c, &RotateSecretRepData{AppSecret: secret}, nil)
+	}
+}
+
+// ListAppReqParams defines the structure for app list request parameters.
+//
+// Sort takes "field[:asc|:desc]" items separated by commas, e.g. "status,created_at:desc",
+// on the fields of appSortFields.
+type ListAppReqParams struct {
+	controller.PageParams
+	AppName string `json:"app_name" form:"app_name"`
+	Status  int8   `json:"status" form:"status" binding:"omitempty,oneof=1 2"`
+	Sort    string `json:"sort" form:"sort"`
+}
+
+// appSortFields maps the fields apps can be sorted by to their columns.
+var appSortFields = map[string]string{
+	"id":         "id",
+	"app_name":   "app_name",
+	"status":     "status",
+	"created_at": "created_at",
+	"updated_at": "updated_at",
+}
+
+// AppRepData defines the structure of an app in responses.
+//
+// It never carries the app secret: the secret is only returned once, by Create and RotateSecret.
+// Handlers must render apps through this type instead of auth.App.
+type AppRepData struct {
+	ID          uint      `json:"id"`
+	AppID       string    `json:"app_id"`
+	AppName     string    `json:"app_name"`
+	RedirectUri string    `json:"redirect_uri"`
+	Description string    `json:"description"`
+	Status      int8      `json:"status"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// newAppRepData converts an app into its response form, leaving out the secret.
+func newAppRepData(app *auth.App) AppRepData {
+	return AppRepData{
+		ID:          app.ID,
+		AppID:       app.AppID,
+		AppName:     app.AppName,
+		RedirectUri: app.RedirectUri,
+		Description: app.Description,
+		Status:      app.Status,
+		CreatedAt:   app.CreatedAt,
+		UpdatedAt:   app.UpdatedAt,
+	}
+}
+
+// List returns a gin.HandlerFunc that lists apps page by page.
+//
+// The response is the standard paginated envelope; app secrets are never included.
+// An unknown sort field responds with e.InvalidParams naming the field under "fields.sort".
+//
+// Returns:
+//   - gin.HandlerFunc: A function that handles the HTTP request for listing apps.
+func (h handler) List() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var params ListAppReqParams
+		if err := c.ShouldBindQuery(&params); err != nil {
+			h.Respond(c, nil, h.BindingError(c, err))
+			return
+		}
+
+		params.NormalizeFor("app")
+
+		order, err := query.ParseSort(params.Sort, appSortFields)
+		if err != nil {
+			h.Respond(c, nil, e.New(e.InvalidParams, err).WithField("sort", err.Error()))
+			return
+		}
+
+		apps, total, err := h.service.PaginateApps(h.Context(c), &service.ListAppParams{
+			AppName: params.AppName,
+			Status:  params.Status,
+			Order:   order,
+		}, params.Page, params.PageSize)
+		if err != nil {
+			h.Respond(c, nil, err)
+			return
+		}
+
+		list := make([]AppRepData, 0, len(apps))
+		for i := range apps {
+			list = append(list, newAppRepData(&apps[i]))
+		}
+
+		controller.PaginatedJSON(&h.BaseController, c, list, total, params.Page, params.PageSize)
+	}
+}
+
+// GetAppReqParams defines the structure for app detail request parameters.
+type GetAppReqParams struct {
+	ID uint `uri:"id" binding:"required"`
+}
+
+// Detail returns a gin.HandlerFunc that returns an app, without its secret.
+//
+// The response carries the ETag of the app version; a request whose If-None-Match matches it
+// gets 304 Not Modified without a body.
+//
+// Returns:
+//   - gin.HandlerFunc: A function that handles the HTTP request for getting an app.
+func (h handler) Detail() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var params GetAppReqParams
+		if err := c.ShouldBindUri(&params); err != nil {
+			h.Respond(c, nil, h.BindingError(c, err))
+			return
+		}
+
+		app, err := h.service.GetApp(h.Context(c), params.ID)
+		if err != nil {
+			h.Respond(c, nil, err)
+			return
+		}
+
+		if h.NotModified(c, app.ID, app.UpdatedAt) {
+			return
+		}
+
+		h.Respond(c, newAppRepData(app), nil)
+	}
+}
+
+// bustAppCache drops the cached app lists after an app changed. A failure only delays the
+// change in the lists until the cached responses expire, so it is logged and ignored.
+func (h handler) bustAppCache(ctx context.Context) {
+	if err := middleware.BustCache(h.AppCtx.Redis["go-api"], AppCacheTag); err != nil {
+		h.log.Warn(ctx, "bust app cache failed", zap.Error(err))
 	}
 }
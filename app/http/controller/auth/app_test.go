@@ -0,0 +1,109 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	nethttp "net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seakee/go-api/app/http"
+	"github.com/seakee/go-api/app/http/controller"
+	"github.com/seakee/go-api/app/model/auth"
+	"github.com/seakee/go-api/app/pkg/lang"
+	service "github.com/seakee/go-api/app/service/auth"
+	"github.com/sk-pkg/i18n"
+	"gorm.io/gorm"
+)
+
+// fakeAppService serves a single app.
+type fakeAppService struct {
+	service.AppService
+	app auth.App
+}
+
+func (s fakeAppService) GetApp(_ context.Context, _ uint) (*auth.App, error) {
+	app := s.app
+	return &app, nil
+}
+
+func (s fakeAppService) PaginateApps(_ context.Context, _ *service.ListAppParams, _, _ int) ([]auth.App, int64, error) {
+	return []auth.App{s.app}, 1, nil
+}
+
+func newTestRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+
+	manager, err := i18n.New(i18n.WithLangDir("../../../../bin/lang"))
+	if err != nil {
+		t.Fatalf("i18n.New() error = %v", err)
+	}
+
+	h := handler{
+		BaseController: controller.BaseController{AppCtx: &http.Context{}, I18n: lang.New(manager, "")},
+		service: fakeAppService{app: auth.App{
+			Model:     gorm.Model{ID: 1},
+			AppID:     "go-api-test",
+			AppName:   "test",
+			AppSecret: "SECRET",
+			Status:    1,
+		}},
+	}
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("app", h.List())
+	router.GET("app/:id", h.Detail())
+
+	return router
+}
+
+func TestAppResponsesOmitSecret(t *testing.T) {
+	router := newTestRouter(t)
+
+	for _, path := range []string{"/app/1", "/app?page=1&page_size=10"} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(nethttp.MethodGet, path, nil))
+
+		body := w.Body.String()
+		if !json.Valid([]byte(body)) {
+			t.Fatalf("GET %s body is not JSON: %s", path, body)
+		}
+
+		if !strings.Contains(body, `"app_id":"go-api-test"`) {
+			t.Errorf("GET %s body = %s, want the app", path, body)
+		}
+
+		if strings.Contains(body, "app_secret") || strings.Contains(body, "SECRET") {
+			t.Errorf("GET %s body = %s, want no app secret", path, body)
+		}
+	}
+}
+
+func TestAppDetailNotModified(t *testing.T) {
+	router := newTestRouter(t)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(nethttp.MethodGet, "/app/1", nil))
+
+	etag := w.Header().Get("ETag")
+	if w.Code != nethttp.StatusOK || etag == "" {
+		t.Fatalf("GET /app/1 = %d with ETag %q, want 200 with an ETag", w.Code, etag)
+	}
+
+	r := httptest.NewRequest(nethttp.MethodGet, "/app/1", nil)
+	r.Header.Set("If-None-Match", etag)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, r)
+
+	if w.Code != nethttp.StatusNotModified || w.Body.Len() != 0 {
+		t.Errorf("GET /app/1 with If-None-Match = %d with body %q, want 304 without body", w.Code, w.Body.String())
+	}
+}
@@ -9,7 +9,10 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/seakee/go-api/app/http"
 	"github.com/seakee/go-api/app/http/controller"
+	"github.com/seakee/go-api/app/pkg/logging"
 	"github.com/seakee/go-api/app/repository/auth"
+	"github.com/seakee/go-api/app/service/audit"
+	service "github.com/seakee/go-api/app/service/auth"
 )
 
 // Handler interface defines the methods that should be implemented by the auth handler.
@@ -17,12 +20,16 @@ type Handler interface {
 	i()
 	Create() gin.HandlerFunc
 	GetToken() gin.HandlerFunc
+	RotateSecret() gin.HandlerFunc
+	List() gin.HandlerFunc
+	Detail() gin.HandlerFunc
 }
 
 // handler struct implements the Handler interface.
 type handler struct {
 	controller.BaseController
-	repo auth.Repo
+	service service.AppService
+	log     *logging.Logger // Sampled and redacted logger of the auth logs
 }
 
 // i is a dummy method to satisfy the Handler interface.
@@ -36,6 +43,9 @@ func (h handler) i() {}
 // Returns:
 //   - Handler: A new Handler instance.
 func NewHandler(appCtx *http.Context) Handler {
+	repo := auth.NewAppRepo(appCtx.MysqlDB["go-api"], appCtx.Redis["go-api"], appCtx.Logger)
+	auditService := audit.NewService(appCtx.MongoDB["go-api"], appCtx.Logger)
+
 	return &handler{
 		BaseController: controller.BaseController{
 			AppCtx: appCtx,
@@ -43,6 +53,7 @@ func NewHandler(appCtx *http.Context) Handler {
 			Redis:  appCtx.Redis["dudu"],
 			I18n:   appCtx.I18n,
 		},
-		repo: auth.NewAppRepo(appCtx.MysqlDB["go-api"], appCtx.Redis["go-api"]),
+		service: service.NewAppService(repo, auditService),
+		log:     logging.New(appCtx.Logger, appCtx.Config.Log),
 	}
 }
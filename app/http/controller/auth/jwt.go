@@ -6,57 +6,58 @@ package auth
 
 import (
 	"github.com/gin-gonic/gin"
-	"github.com/seakee/go-api/app/model/auth"
-	"github.com/seakee/go-api/app/pkg/e"
-	"github.com/seakee/go-api/app/pkg/jwt"
+	"github.com/seakee/go-api/app/config"
 )
 
-// appTokenExpireTime defines the expiration time for app tokens in seconds (7 days).
-const appTokenExpireTime = 168 * 3600
-
 // GetToken is a gin.HandlerFunc that generates and returns an authentication token for an app.
 //
 // This function handles the following steps:
 // 1. Extracts app_id and app_secret from the POST form data.
-// 2. Validates the app credentials against the database.
-// 3. Generates a JWT token if the credentials are valid.
+// 2. Validates the app credentials and issues an app token through the app service.
+// 3. Sets the token in an HttpOnly cookie for browser apps when Auth.Cookie is enabled.
 // 4. Returns the token and its expiration time, or an error if any step fails.
 //
 // Returns:
 //   - gin.HandlerFunc: A function that can be used as a Gin route handler.
 func (h handler) GetToken() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var (
-			err     error
-			app     *auth.App
-			errCode int
-			data    gin.H
-			token   string
-		)
-
 		// Extract app credentials from the POST form
 		appID := c.PostForm("app_id")
 		appSecret := c.PostForm("app_secret")
-		data = make(gin.H)
-
-		errCode = e.InvalidParams
-		if appID != "" && appSecret != "" {
-			// Attempt to retrieve the app from the database
-			app, err = h.repo.GetApp(h.Context(c), &auth.App{AppID: appID, AppSecret: appSecret, Status: 1})
-			errCode = e.ServerAppNotFound
-			if err == nil {
-				// Generate a JWT token for the app
-				token, err = jwt.GenerateAppToken(app, appTokenExpireTime)
-				errCode = e.ServerAuthorizationFail
-				if err == nil {
-					errCode = e.SUCCESS
-					data["token"] = token
-					data["expires_in"] = appTokenExpireTime
-				}
-			}
+
+		token, expiresIn, err := h.service.IssueAppToken(h.Context(c), appID, appSecret)
+		if err != nil {
+			h.Respond(c, nil, err)
+			return
+		}
+
+		if cookie := h.AppCtx.Config.Auth.Cookie; cookie.Enable {
+			setTokenCookie(c, cookie, token, expiresIn)
 		}
 
 		// Respond with the result
-		h.I18n.JSON(c, errCode, data, err)
+		h.Respond(c, gin.H{"token": token, "expires_in": expiresIn}, nil)
+	}
+}
+
+// setTokenCookie sets token in the HttpOnly cookie described by cfg, expiring with the token.
+//
+// Parameters:
+//   - c: *gin.Context - The Gin context of the token request.
+//   - cfg: config.TokenCookie - The name and attributes of the cookie.
+//   - token: string - The issued token.
+//   - expiresIn: int64 - The lifetime of the token in seconds.
+func setTokenCookie(c *gin.Context, cfg config.TokenCookie, token string, expiresIn int64) {
+	name := cfg.Name
+	if name == "" {
+		name = config.DefaultTokenCookie
 	}
+
+	path := cfg.Path
+	if path == "" {
+		path = "/"
+	}
+
+	c.SetSameSite(cfg.SameSiteMode())
+	c.SetCookie(name, token, int(expiresIn), path, cfg.Domain, cfg.Secure, true)
 }
@@ -4,7 +4,8 @@ import (
 	"context"
 	"github.com/gin-gonic/gin"
 	"github.com/seakee/go-api/app/http"
-	"github.com/sk-pkg/i18n"
+	"github.com/seakee/go-api/app/pkg/e"
+	"github.com/seakee/go-api/app/pkg/lang"
 	"github.com/sk-pkg/logger"
 	"github.com/sk-pkg/redis"
 )
@@ -14,7 +15,7 @@ type BaseController struct {
 	AppCtx *http.Context
 	Logger *logger.Manager
 	Redis  *redis.Manager
-	I18n   *i18n.Manager
+	I18n   *lang.Manager
 }
 
 // Context creates a new context with the trace ID from the gin.Context.
@@ -27,3 +28,32 @@ type BaseController struct {
 func (b *BaseController) Context(c *gin.Context) context.Context {
 	return b.AppCtx.Context(c)
 }
+
+// Respond renders the result of an operation through I18n.JSON.
+//
+// A nil err responds with e.SUCCESS and data. An *e.APIError responds with its code, and
+// its field-level details (if any) under the "fields" key. Any other error responds with e.ERROR.
+//
+// Parameters:
+//   - c: *gin.Context - The gin context to write to.
+//   - data: interface{} - The response data on success.
+//   - err: error - The error returned by the operation.
+//
+// Example:
+//
+//	app, err := h.service.GetApp(ctx, id)
+//	h.Respond(c, app, err)
+func (b *BaseController) Respond(c *gin.Context, data interface{}, err error) {
+	apiErr := e.AsAPIError(err)
+	if apiErr == nil {
+		b.I18n.JSON(c, e.SUCCESS, data, nil)
+		return
+	}
+
+	var errData interface{}
+	if len(apiErr.Fields) > 0 {
+		errData = gin.H{"fields": apiErr.Fields}
+	}
+
+	b.I18n.JSON(c, apiErr.Code, errData, apiErr.Err)
+}
@@ -0,0 +1,80 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ETag returns the weak ETag of a resource version: W/"<id>-<updated_at in nanoseconds, base 36>".
+//
+// The scheme assumes every change of a resource bumps its updated_at, which gorm does for
+// updates made through the models. It is weak because the envelope may differ between two
+// responses of the same version, e.g. in the language of the message.
+//
+// Parameters:
+//   - id: uint - The ID of the resource.
+//   - updatedAt: time.Time - The last modification time of the resource.
+//
+// Returns:
+//   - string: The ETag.
+func ETag(id uint, updatedAt time.Time) string {
+	return `W/"` + strconv.FormatUint(uint64(id), 36) + "-" + strconv.FormatInt(updatedAt.UnixNano(), 36) + `"`
+}
+
+// NotModified sets the ETag of a resource on the response and answers 304 Not Modified if the
+// client already has that version.
+//
+// Call it in detail handlers once the resource is loaded; when it returns true the response is
+// written and the handler must return.
+//
+// Parameters:
+//   - c: *gin.Context - The gin context of the request.
+//   - id: uint - The ID of the resource.
+//   - updatedAt: time.Time - The last modification time of the resource.
+//
+// Returns:
+//   - bool: true if a 304 response was written.
+//
+// Example:
+//
+//	if h.NotModified(c, app.ID, app.UpdatedAt) {
+//	    return
+//	}
+//	h.Respond(c, newAppRepData(app), nil)
+func (b *BaseController) NotModified(c *gin.Context, id uint, updatedAt time.Time) bool {
+	etag := ETag(id, updatedAt)
+	c.Header("ETag", etag)
+
+	if !etagMatches(c.GetHeader("If-None-Match"), etag) {
+		return false
+	}
+
+	c.AbortWithStatus(http.StatusNotModified)
+
+	return true
+}
+
+// etagMatches reports whether an If-None-Match header matches etag, using the weak comparison.
+func etagMatches(header, etag string) bool {
+	if header == "" {
+		return false
+	}
+
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+
+	return false
+}
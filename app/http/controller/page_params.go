@@ -0,0 +1,100 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"github.com/seakee/go-api/app/config"
+)
+
+const (
+	DefaultPage        = 1   // Page used when the request omits it or sends an invalid value
+	DefaultPageSize    = 20  // Page size used when the request omits it or sends an invalid value
+	DefaultMaxPageSize = 200 // Page size cap used when System.MaxPageSize isn't configured
+)
+
+// PageParams defines the common pagination parameters of list endpoints.
+//
+// Embed it in a handler's request params and call Normalize after binding:
+//
+//	type ListAppReqParams struct {
+//	    controller.PageParams
+//	    AppName string `form:"app_name"`
+//	}
+type PageParams struct {
+	Page     int `json:"page" form:"page"`           // Page number (1-based)
+	PageSize int `json:"page_size" form:"page_size"` // Number of items per page
+}
+
+// Normalize clamps out-of-range pagination values instead of rejecting them.
+//
+// Page defaults to DefaultPage when it is less than 1. PageSize defaults to DefaultPageSize
+// when it is less than 1, and is capped at System.MaxPageSize (or DefaultMaxPageSize if unset).
+//
+// Returns:
+//   - *PageParams: The normalized params, for chaining.
+func (p *PageParams) Normalize() *PageParams {
+	return p.NormalizeFor("")
+}
+
+// NormalizeFor clamps out-of-range pagination values with the limits of resource.
+//
+// It behaves like Normalize, except that the default_page_size and max_page_size configured for
+// resource in Limits take precedence over DefaultPageSize and System.MaxPageSize.
+//
+// Parameters:
+//   - resource: The resource listed by the endpoint, e.g. "app"; its key in Limits.
+//
+// Returns:
+//   - *PageParams: The normalized params, for chaining.
+//
+// Example:
+//
+//	params.NormalizeFor("app")
+func (p *PageParams) NormalizeFor(resource string) *PageParams {
+	return p.clamp(resourceLimit(resource), maxPageSize())
+}
+
+// clamp clamps the pagination values with limit, falling back to DefaultPageSize and maxSize
+// for its zero values.
+func (p *PageParams) clamp(limit config.Limit, maxSize int) *PageParams {
+	if p.Page < 1 {
+		p.Page = DefaultPage
+	}
+
+	if p.PageSize < 1 {
+		p.PageSize = DefaultPageSize
+		if limit.DefaultPageSize > 0 {
+			p.PageSize = limit.DefaultPageSize
+		}
+	}
+
+	if limit.MaxPageSize > 0 {
+		maxSize = limit.MaxPageSize
+	}
+
+	if p.PageSize > maxSize {
+		p.PageSize = maxSize
+	}
+
+	return p
+}
+
+// maxPageSize returns the configured page size cap, falling back to DefaultMaxPageSize.
+func maxPageSize() int {
+	if cfg := config.Get(); cfg != nil && cfg.System.MaxPageSize > 0 {
+		return cfg.System.MaxPageSize
+	}
+
+	return DefaultMaxPageSize
+}
+
+// resourceLimit returns the limits configured for resource; zero values are unset.
+func resourceLimit(resource string) config.Limit {
+	if cfg := config.Get(); cfg != nil {
+		return cfg.Limits[resource]
+	}
+
+	return config.Limit{}
+}
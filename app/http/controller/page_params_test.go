@@ -0,0 +1,63 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"testing"
+
+	"github.com/seakee/go-api/app/config"
+)
+
+func TestPageParamsNormalizeFor(t *testing.T) {
+	tests := []struct {
+		name  string
+		in    PageParams
+		limit config.Limit
+		want  PageParams
+	}{
+		{name: "in range", in: PageParams{Page: 3, PageSize: 50}, want: PageParams{Page: 3, PageSize: 50}},
+		{name: "page zero", in: PageParams{Page: 0, PageSize: 50}, want: PageParams{Page: DefaultPage, PageSize: 50}},
+		{name: "negative page size", in: PageParams{Page: 1, PageSize: -1}, want: PageParams{Page: 1, PageSize: DefaultPageSize}},
+		{name: "page size over the max", in: PageParams{Page: 1, PageSize: 100000}, want: PageParams{Page: 1, PageSize: DefaultMaxPageSize}},
+		{
+			name:  "resource default page size",
+			in:    PageParams{Page: 1},
+			limit: config.Limit{DefaultPageSize: 50, MaxPageSize: 100},
+			want:  PageParams{Page: 1, PageSize: 50},
+		},
+		{
+			name:  "resource max page size",
+			in:    PageParams{Page: 1, PageSize: 150},
+			limit: config.Limit{DefaultPageSize: 50, MaxPageSize: 100},
+			want:  PageParams{Page: 1, PageSize: 100},
+		},
+		{
+			name:  "resource max above the global max",
+			in:    PageParams{Page: 1, PageSize: 100000},
+			limit: config.Limit{MaxPageSize: 500},
+			want:  PageParams{Page: 1, PageSize: 500},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.in
+			got.clamp(tt.limit, DefaultMaxPageSize)
+			if got != tt.want {
+				t.Errorf("clamp(%+v) = %+v, want %+v", tt.in, got, tt.want)
+			}
+
+			// Without a configuration, NormalizeFor applies the global defaults
+			if tt.limit != (config.Limit{}) {
+				return
+			}
+
+			got = tt.in
+			if got.NormalizeFor("app"); got != tt.want {
+				t.Errorf("NormalizeFor(%+v) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,30 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/seakee/go-api/app/http"
+	"github.com/seakee/go-api/app/pkg/e"
+)
+
+// PaginatedJSON emits a successful paginated response through the controller's I18n manager.
+//
+// Go methods can't declare type parameters, so this takes the BaseController explicitly.
+//
+// Parameters:
+//   - b: *BaseController - The controller emitting the response.
+//   - c: *gin.Context - The gin context to write to.
+//   - list: []T - The items of the current page.
+//   - total: int64 - The total number of matching items.
+//   - page: int - The current page (1-based).
+//   - pageSize: int - The number of items per page.
+//
+// Example:
+//
+//	controller.PaginatedJSON(&h.BaseController, c, apps, total, params.Page, params.PageSize)
+func PaginatedJSON[T any](b *BaseController, c *gin.Context, list []T, total int64, page, pageSize int) {
+	b.I18n.JSON(c, e.SUCCESS, http.NewPaginated(list, total, page, pageSize), nil)
+}
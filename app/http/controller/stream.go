@@ -0,0 +1,85 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+)
+
+// streamHeartbeat is the interval of the comments keeping idle streams open through proxies.
+const streamHeartbeat = 15 * time.Second
+
+// Event is a server-sent event.
+type Event struct {
+	ID    string      // Optional ID, sent back by the client in Last-Event-ID when it reconnects
+	Event string      // Optional event name; the client's "message" listener receives unnamed events
+	Data  interface{} // The payload; strings are sent as they are, other values as JSON
+	Retry uint        // Optional reconnection delay in milliseconds
+}
+
+// Stream answers the request with a text/event-stream response and writes events to it as they come.
+//
+// Each event is flushed as soon as it is written and a comment is sent on idle streams every
+// 15 seconds. Stream returns when events is closed or when the client goes away, which cancels
+// the request context; the producer should watch the same context to stop early. The server
+// write timeout is lifted for the response, but the Timeout middleware still applies: list the
+// route in System.RequestTimeoutSkip.
+//
+// Parameters:
+//   - c: *gin.Context - The gin context of the request.
+//   - events: <-chan Event - The events to send; close it to end the stream.
+//
+// Example:
+//
+//	events := make(chan controller.Event)
+//	go h.service.Import(h.Context(c), file, events)
+//	h.Stream(c, events)
+func (b *BaseController) Stream(c *gin.Context, events <-chan Event) {
+	ctx := c.Request.Context()
+
+	// The stream outlives the server write timeout, which is meant for regular responses
+	_ = http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{})
+
+	c.Header("Content-Type", sse.ContentType)
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Disable the response buffering of nginx
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			if _, err := c.Writer.WriteString(":\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+
+			err := sse.Encode(c.Writer, sse.Event{
+				Id:    event.ID,
+				Event: event.Event,
+				Retry: event.Retry,
+				Data:  event.Data,
+			})
+			if err != nil {
+				return
+			}
+		}
+
+		c.Writer.Flush()
+	}
+}
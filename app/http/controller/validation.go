@@ -0,0 +1,96 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package controller
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"github.com/seakee/go-api/app/http/middleware"
+	"github.com/seakee/go-api/app/pkg/e"
+)
+
+const (
+	validationKeyPrefix  = "validation."        // Prefix of the validation messages in the language files
+	validationDefaultKey = "validation.invalid" // Message used for tags without their own message
+)
+
+func init() {
+	// Report validation errors with the request field names instead of the Go struct field names
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(requestFieldName)
+	}
+}
+
+// BindingError converts the error returned by c.ShouldBind* into an e.InvalidParams error.
+//
+// Validation errors are translated into localized messages keyed by request field name,
+// which Respond renders under the "fields" key. Other errors, e.g. malformed JSON, carry no
+// field details. The language is taken from the "lang" header like I18n.JSON does.
+//
+// Parameters:
+//   - c: *gin.Context - The gin context of the request.
+//   - err: error - The error returned by binding.
+//
+// Returns:
+//   - *e.APIError: An e.InvalidParams error wrapping err.
+//
+// Example:
+//
+//	if err := c.ShouldBindJSON(&params); err != nil {
+//	    h.Respond(c, nil, h.BindingError(c, err))
+//	    return
+//	}
+func (b *BaseController) BindingError(c *gin.Context, err error) *e.APIError {
+	apiErr := e.New(e.InvalidParams, err)
+
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return apiErr
+	}
+
+	lang := middleware.RequestLang(c)
+	for _, fieldErr := range fieldErrs {
+		apiErr.WithField(fieldErr.Field(), b.fieldMessage(lang, fieldErr))
+	}
+
+	return apiErr
+}
+
+// fieldMessage returns the localized message of a validation error.
+func (b *BaseController) fieldMessage(lang string, fieldErr validator.FieldError) string {
+	params := []string{fieldErr.Field()}
+	if fieldErr.Param() != "" {
+		params = append(params, fieldErr.Param())
+	}
+
+	key := validationKeyPrefix + fieldErr.Tag()
+	if msg := b.I18n.Trans(lang, key, params...); msg != key {
+		return msg
+	}
+
+	return b.I18n.Trans(lang, validationDefaultKey, fieldErr.Field())
+}
+
+// requestFieldName returns the name of a struct field in requests: its json tag,
+// then its form tag, then the Go field name.
+func requestFieldName(field reflect.StructField) string {
+	for _, tag := range []string{"json", "form"} {
+		name, _, _ := strings.Cut(field.Tag.Get(tag), ",")
+		if name == "-" {
+			return ""
+		}
+
+		if name != "" {
+			return name
+		}
+	}
+
+	return field.Name
+}
@@ -0,0 +1,62 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package ws provides the handlers of the WebSocket endpoints.
+package ws
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/seakee/go-api/app/http"
+	"github.com/seakee/go-api/app/http/controller"
+	"github.com/seakee/go-api/app/http/ws"
+	"github.com/seakee/go-api/app/pkg/identity"
+)
+
+// Handler interface defines the methods that should be implemented by the WebSocket handler.
+type Handler interface {
+	i()
+	Notifications() gin.HandlerFunc
+}
+
+// handler struct implements the Handler interface.
+type handler struct {
+	controller.BaseController
+	hub *ws.Hub
+}
+
+// i is a dummy method to satisfy the Handler interface.
+func (h handler) i() {}
+
+// NewHandler creates and returns a new Handler instance.
+//
+// Parameters:
+//   - appCtx: *http.Context - The application context.
+//
+// Returns:
+//   - Handler: A new Handler instance.
+func NewHandler(appCtx *http.Context) Handler {
+	return &handler{
+		BaseController: controller.BaseController{
+			AppCtx: appCtx,
+			Logger: appCtx.Logger,
+			I18n:   appCtx.I18n,
+		},
+		hub: appCtx.WSHub,
+	}
+}
+
+// Notifications returns a Gin handler function that streams the notifications of the
+// authenticated app over a WebSocket connection.
+//
+// The app receives the messages sent to its app_id and the broadcast ones, such as the alerts
+// routed to the "websocket" channel, as JSON text frames: {"type": "alert", "data": {...}}.
+//
+// Returns:
+//   - gin.HandlerFunc: A Gin handler function that upgrades the request.
+func (h handler) Notifications() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, _ := identity.CurrentUser(h.Context(c))
+		h.hub.Serve(c, user.UserID)
+	}
+}
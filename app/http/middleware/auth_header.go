@@ -0,0 +1,97 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/seakee/go-api/app/pkg/e"
+)
+
+// MaxTokenLength is the length of the longest token CheckAppAuth parses. App tokens are a few
+// hundred bytes; longer values are rejected before they reach the JWT parser.
+const MaxTokenLength = 4096
+
+// bearerScheme is the authorization scheme of the tokens, optional in the Authorization header.
+const bearerScheme = "Bearer"
+
+// errMissingToken is returned when the request carries no token.
+var errMissingToken = errors.New("missing token")
+
+// parseAuthorization extracts the token from the value of an Authorization header.
+//
+// The header holds the token alone or after the "Bearer" scheme, matched case-insensitively.
+// Other schemes, tokens longer than MaxTokenLength and values not shaped as a JWT are rejected
+// without being parsed.
+//
+// Parameters:
+//   - header: string - The value of the Authorization header.
+//
+// Returns:
+//   - token: string - The token, when errCode is e.SUCCESS.
+//   - errCode: int - e.SUCCESS, e.MissingToken for an empty header or e.InvalidToken for a malformed one.
+//   - err: error - The reason the header is rejected, or nil.
+func parseAuthorization(header string) (token string, errCode int, err error) {
+	// Bound the work spent on the header before splitting it
+	if len(header) > MaxTokenLength+len(bearerScheme)+1 {
+		return "", e.InvalidToken, fmt.Errorf("authorization header of %d bytes is too long", len(header))
+	}
+
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return "", e.MissingToken, errMissingToken
+	}
+
+	token = header
+	if scheme, rest, found := strings.Cut(header, " "); found {
+		if !strings.EqualFold(scheme, bearerScheme) {
+			return "", e.InvalidToken, errors.New("unsupported authorization scheme")
+		}
+
+		token = strings.TrimSpace(rest)
+	}
+
+	return parseToken(token)
+}
+
+// parseToken checks that token is a JWT no longer than MaxTokenLength: three non-empty
+// base64url segments separated by dots. It doesn't verify the token.
+//
+// Parameters:
+//   - token: string - The token, e.g. from the "token" query parameter of a WebSocket upgrade.
+//
+// Returns:
+//   - string: The token, when errCode is e.SUCCESS.
+//   - errCode: int - e.SUCCESS, e.MissingToken for an empty token or e.InvalidToken for a malformed one.
+//   - err: error - The reason the token is rejected, or nil.
+func parseToken(token string) (string, int, error) {
+	if token == "" {
+		return "", e.MissingToken, errMissingToken
+	}
+
+	if len(token) > MaxTokenLength {
+		return "", e.InvalidToken, fmt.Errorf("token of %d bytes exceeds %d", len(token), MaxTokenLength)
+	}
+
+	segments := strings.Split(token, ".")
+	if len(segments) != 3 {
+		return "", e.InvalidToken, fmt.Errorf("token has %d segments, want 3", len(segments))
+	}
+
+	for _, segment := range segments {
+		if segment == "" || strings.IndexFunc(segment, notBase64URL) >= 0 {
+			return "", e.InvalidToken, errors.New("token segment is not base64url encoded")
+		}
+	}
+
+	return token, e.SUCCESS, nil
+}
+
+// notBase64URL reports whether r is outside the unpadded base64url alphabet of JWT segments.
+func notBase64URL(r rune) bool {
+	return !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_')
+}
@@ -0,0 +1,210 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seakee/go-api/app/config"
+	"github.com/seakee/go-api/app/pkg/e"
+	"github.com/sk-pkg/redis"
+	"github.com/sk-pkg/util"
+)
+
+const (
+	// CacheStatusHeader tells whether a response was served from the response cache ("HIT") or not ("MISS").
+	CacheStatusHeader = "X-Cache"
+
+	responseCacheKey        = "response_cache:"         // Prefix of the cached responses
+	responseCacheVersionKey = "response_cache:version:" // Prefix of the tag versions
+)
+
+// CacheKeyFunc returns the key identifying the response of a request in the response cache.
+type CacheKeyFunc func(c *gin.Context) string
+
+// cachedResponse is the response stored in Redis by Cache.
+type cachedResponse struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+}
+
+// DefaultCacheKey identifies a response by route, query string, authenticated app and language.
+//
+// Parameters:
+//   - c: *gin.Context - The Gin context of the request.
+//
+// Returns:
+//   - string: The key.
+func DefaultCacheKey(c *gin.Context) string {
+	return util.SpliceStr(c.FullPath(), "?", c.Request.URL.Query().Encode(), "|", c.GetString("app_id"), "|", RequestLang(c))
+}
+
+// Cache returns a Gin middleware function that caches the responses of GET requests in Redis.
+//
+// A successful response (2xx status and e.SUCCESS) is stored for ttl under the key returned by
+// keyFunc (DefaultCacheKey if nil) and served to the following requests with the same key,
+// with the "X-Cache: HIT" header. Responses setting cookies are not stored unless
+// Cache.Response.CacheSetCookie is set.
+//
+// The tags name the data the responses depend on; BustCache with one of the tags drops every
+// response cached with it, e.g. when that data changes. Without tags, responses only expire.
+// Register it on the route groups that opt in, after CheckAppAuth so the app is part of the key.
+// It passes every request through when Cache.Response.Enable is false.
+//
+// Parameters:
+//   - ttl: How long a response is served from the cache.
+//   - keyFunc: The function identifying a response; nil uses DefaultCacheKey.
+//   - tags: The tags of the cached responses.
+//
+// Returns:
+//   - gin.HandlerFunc: A middleware function for Gin framework.
+//
+// Example:
+//
+//	group.Use(ctx.Middleware.CheckAppAuth(), ctx.Middleware.Cache(time.Minute, nil, "app"))
+func (m middleware) Cache(ttl time.Duration, keyFunc CacheKeyFunc, tags ...string) gin.HandlerFunc {
+	var opts config.ResponseCache
+	if cfg := config.Get(); cfg != nil {
+		opts = cfg.Cache.Response
+	}
+
+	if keyFunc == nil {
+		keyFunc = DefaultCacheKey
+	}
+
+	seconds := int(ttl / time.Second)
+
+	return func(c *gin.Context) {
+		r := m.redis["go-api"]
+		if !opts.Enable || r == nil || seconds <= 0 || c.Request.Method != http.MethodGet {
+			c.Next()
+			return
+		}
+
+		versions, err := tagVersions(r, tags)
+		if err != nil {
+			// Without the tag versions a busted response could be served, so bypass the cache
+			c.Next()
+			return
+		}
+
+		key := util.SpliceStr(responseCacheKey, util.MD5(util.SpliceStr(keyFunc(c), "|", versions)))
+
+		var cached cachedResponse
+		if err = r.GetJSON(key, &cached); err == nil {
+			c.Header(CacheStatusHeader, "HIT")
+			c.Data(cached.Status, cached.ContentType, cached.Body)
+			c.Abort()
+			return
+		}
+
+		c.Header(CacheStatusHeader, "MISS")
+
+		writer := &bodyWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+		c.Writer = writer
+
+		c.Next()
+
+		if !cacheable(c, writer.body.Bytes(), opts) {
+			return
+		}
+
+		_ = r.SetJSON(key, cachedResponse{
+			Status:      c.Writer.Status(),
+			ContentType: c.Writer.Header().Get("Content-Type"),
+			Body:        writer.body.Bytes(),
+		}, seconds)
+	}
+}
+
+// BustCache drops every response cached by Cache with one of the tags.
+//
+// It bumps the version of each tag, so the keys of the responses cached before change; the
+// old entries are never served again and expire on their own.
+//
+// Parameters:
+//   - r: *redis.Manager - The Redis the responses are cached in.
+//   - tags: The tags to bust.
+//
+// Returns:
+//   - error: An error if a version can't be bumped.
+//
+// Example:
+//
+//	if err := middleware.BustCache(h.AppCtx.Redis["go-api"], "app"); err != nil {
+//	    h.Logger.Warn(ctx, "bust app cache failed", zap.Error(err))
+//	}
+func BustCache(r *redis.Manager, tags ...string) error {
+	if r == nil {
+		return nil
+	}
+
+	for _, tag := range tags {
+		if _, err := r.Incr(responseCacheVersionKey + tag); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// tagVersions returns the current versions of tags, joined into a string.
+func tagVersions(r *redis.Manager, tags []string) (string, error) {
+	versions := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		version, err := r.GetString(responseCacheVersionKey + tag)
+		if err != nil {
+			return "", err
+		}
+
+		versions = append(versions, tag+"="+version)
+	}
+
+	return strings.Join(versions, ","), nil
+}
+
+// cacheable reports whether the response just written may be stored.
+func cacheable(c *gin.Context, body []byte, opts config.ResponseCache) bool {
+	if c.IsAborted() || len(c.Errors) > 0 {
+		return false
+	}
+
+	status := c.Writer.Status()
+	if status < http.StatusOK || status >= http.StatusMultipleChoices {
+		return false
+	}
+
+	if !opts.CacheSetCookie && c.Writer.Header().Get("Set-Cookie") != "" {
+		return false
+	}
+
+	if strings.Contains(c.Writer.Header().Get("Cache-Control"), "no-store") {
+		return false
+	}
+
+	// Only store responses reporting success in the standard envelope
+	return successEnvelope(body)
+}
+
+// successEnvelope reports whether body is the standard envelope with e.SUCCESS as its code.
+//
+// Handlers render error codes through I18n.JSON with a 200 status, so the status alone
+// doesn't tell a failed response from a successful one.
+func successEnvelope(body []byte) bool {
+	var envelope struct {
+		Code *int `json:"code"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope.Code == nil {
+		return false
+	}
+
+	return *envelope.Code == e.SUCCESS
+}
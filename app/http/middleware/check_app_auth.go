@@ -5,29 +5,78 @@
 package middleware
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/seakee/go-api/app/config"
 	"github.com/seakee/go-api/app/pkg/e"
+	"github.com/seakee/go-api/app/pkg/identity"
 	apiJWT "github.com/seakee/go-api/app/pkg/jwt"
+	"github.com/seakee/go-api/app/pkg/logging"
+	"github.com/sk-pkg/logger"
+	"go.uber.org/zap"
 )
 
 // CheckAppAuth returns a Gin middleware function that checks the application's authentication.
 //
-// This middleware validates the JWT token in the "Authorization" header.
-// If the token is valid, it sets the app_id and app_name in the Gin context.
-// If the token is invalid or expired, it aborts the request with an appropriate error response.
+// It guards a whole route group: the routes listed in publicRoutes are let through without a
+// token, every other route requires one.
+//
+// This middleware validates the JWT token read from the locations of Auth.TokenSources, in
+// order, the "Authorization" header by default; in a header the token may follow the "Bearer"
+// scheme. WebSocket upgrades may also pass it in the "token" query parameter, since browsers
+// can't set headers on them. Malformed headers and tokens longer than MaxTokenLength are
+// rejected before the token is parsed.
+//
+// It panics on an unknown token location, so a broken configuration stops the service at startup.
+// If the token is valid, it sets the app_id and app_name in the Gin context, and the identity
+// of the app in the request context, read with identity.CurrentUser.
+// If the token is missing, malformed, invalid or expired, it aborts the request with a 401
+// response carrying the matching error code and logs the rejection, sampled as configured in
+// Log.Sampling for the info level.
 //
 // Returns:
 //   - gin.HandlerFunc: A middleware function for Gin framework.
 func (m middleware) CheckAppAuth() gin.HandlerFunc {
+	var opts config.LogConfig
+	sources := defaultTokenSources
+	if cfg := config.Get(); cfg != nil {
+		opts = cfg.Log
+		if len(cfg.Auth.TokenSources) > 0 {
+			sources = cfg.Auth.TokenSources
+		}
+	}
+
+	for _, source := range sources {
+		if !validTokenSource(source) {
+			panic(fmt.Sprintf("invalid auth token source: from %q, name %q", source.From, source.Name))
+		}
+	}
+
+	log := logging.New(m.logger, opts)
+
 	return func(c *gin.Context) {
-		errCode, err := checkByToken(c)
+		if isPublicRoute(c.Request.Method, c.FullPath()) {
+			c.Next()
+			return
+		}
+
+		errCode, err := checkByToken(c, sources)
 		if errCode != e.SUCCESS {
+			ctx := context.WithValue(context.Background(), logger.TraceIDKey, c.GetString("trace_id"))
+			log.Info(ctx, "App authentication failed",
+				zap.Int("code", errCode),
+				zap.String("IP", ClientIP(c)),
+				zap.String("RequestPath", c.Request.URL.Path),
+				zap.Error(err),
+			)
+
 			// If authentication fails, respond with an error and abort the request
-			m.i18n.JSON(c, errCode, nil, err)
-			c.Abort()
+			m.abortWithStatus(c, http.StatusUnauthorized, errCode, err)
 			return
 		}
 
@@ -36,13 +85,15 @@ func (m middleware) CheckAppAuth() gin.HandlerFunc {
 	}
 }
 
-// checkByToken validates the JWT token from the request header and returns the result.
+// checkByToken validates the JWT token of the request and returns the result.
 //
-// It extracts the token from the "Authorization" header, parses it, and sets
-// app_id and app_name in the Gin context if the token is valid.
+// It extracts the token from the first of sources present in the request (or the "token" query
+// parameter of a WebSocket upgrade), checks its format, parses it, and sets app_id and app_name
+// in the Gin context and the identity in the request context if the token is valid.
 //
 // Parameters:
 //   - c: *gin.Context - The Gin context containing the HTTP request information.
+//   - sources: []config.TokenSource - The token locations, tried in order.
 //
 // Returns:
 //   - errCode: int - An error code indicating the result of the token validation.
@@ -50,34 +101,85 @@ func (m middleware) CheckAppAuth() gin.HandlerFunc {
 //
 // Error codes:
 //   - e.SUCCESS: Token is valid
-//   - e.InvalidParams: No token provided
+//   - e.MissingToken: No token provided
+//   - e.InvalidToken: The header is malformed or the token too long
 //   - e.ServerAuthorizationExpired: Token has expired
 //   - e.ServerUnauthorized: Token is invalid
-func checkByToken(c *gin.Context) (errCode int, err error) {
-	errCode = e.InvalidParams
-
-	// Extract token from the Authorization header
-	token := c.Request.Header.Get("Authorization")
-	if token != "" {
-		var serverClaims *apiJWT.ServerClaims
-
-		errCode = e.SUCCESS
-
-		// Parse and validate the token
-		serverClaims, err = apiJWT.ParseAppAuth(token)
-		if err != nil {
-			switch {
-			case errors.Is(err, jwt.ErrTokenExpired):
-				errCode = e.ServerAuthorizationExpired
-			default:
-				errCode = e.ServerUnauthorized
+func checkByToken(c *gin.Context, sources []config.TokenSource) (errCode int, err error) {
+	// Extract token from the configured locations
+	token, errCode, err := tokenFromRequest(c, sources)
+	if errCode != e.SUCCESS {
+		return
+	}
+
+	// Parse and validate the token
+	serverClaims, err := apiJWT.ParseAppAuth(token)
+	if err != nil {
+		if errors.Is(err, jwt.ErrTokenExpired) {
+			return e.ServerAuthorizationExpired, err
+		}
+
+		return e.ServerUnauthorized, err
+	}
+
+	// If token is valid, set app_id and app_name in the context
+	c.Set("app_id", serverClaims.AppID)
+	c.Set("app_name", serverClaims.AppName)
+
+	// and the identity of the app in the request context, for the handlers and services
+	c.Request = c.Request.WithContext(identity.WithIdentity(c.Request.Context(), identity.Identity{
+		UserID:   serverClaims.AppID,
+		UserName: serverClaims.AppName,
+	}))
+
+	return e.SUCCESS, nil
+}
+
+// defaultTokenSources are the token locations when Auth.TokenSources is empty.
+var defaultTokenSources = []config.TokenSource{{From: config.TokenFromHeader, Name: "Authorization"}}
+
+// validTokenSource reports whether source names a supported location.
+func validTokenSource(source config.TokenSource) bool {
+	switch source.From {
+	case config.TokenFromHeader, config.TokenFromCookie, config.TokenFromQuery:
+		return source.Name != ""
+	default:
+		return false
+	}
+}
+
+// tokenFromRequest reads the token from the first of sources present in the request and checks its format.
+//
+// Parameters:
+//   - c: *gin.Context - The Gin context of the request.
+//   - sources: []config.TokenSource - The token locations, tried in order.
+//
+// Returns:
+//   - string: The token, when the error code is e.SUCCESS.
+//   - int: e.SUCCESS, e.MissingToken when no location holds a token or e.InvalidToken for a malformed one.
+//   - error: The reason the token is rejected, or nil.
+func tokenFromRequest(c *gin.Context, sources []config.TokenSource) (string, int, error) {
+	for _, source := range sources {
+		switch source.From {
+		case config.TokenFromHeader:
+			if value := c.GetHeader(source.Name); value != "" {
+				return parseAuthorization(value)
+			}
+		case config.TokenFromCookie:
+			if value, err := c.Cookie(source.Name); err == nil && value != "" {
+				return parseToken(value)
+			}
+		case config.TokenFromQuery:
+			if value := c.Query(source.Name); value != "" {
+				return parseToken(value)
 			}
-		} else {
-			// If token is valid, set app_id and app_name in the context
-			c.Set("app_id", serverClaims.AppID)
-			c.Set("app_name", serverClaims.AppName)
 		}
 	}
 
-	return
+	// Browsers can't set headers on WebSocket upgrades, so they pass the token in the query
+	if c.IsWebsocket() {
+		return parseToken(c.Query("token"))
+	}
+
+	return "", e.MissingToken, errMissingToken
 }
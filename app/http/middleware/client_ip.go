@@ -0,0 +1,35 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"net/netip"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ClientIP returns the IP of the client that sent the request.
+//
+// Use it wherever the client IP matters (logs, rate limits, IP filters) so every place agrees.
+// It relies on the trusted proxies of the engine (System.TrustedProxies):
+//  1. If the peer is not a trusted proxy, or none are configured, the peer address is the client;
+//     X-Forwarded-For and X-Real-IP are ignored, so clients can't spoof their IP.
+//  2. Otherwise X-Forwarded-For is walked from the right, skipping trusted proxies, and the first
+//     untrusted address is the client.
+//  3. If X-Forwarded-For is missing or malformed, X-Real-IP is used the same way, then the peer address.
+//
+// Parameters:
+//   - c: *gin.Context - The Gin context of the request.
+//
+// Returns:
+//   - string: The client IP, with IPv4-mapped IPv6 addresses converted to IPv4.
+func ClientIP(c *gin.Context) string {
+	ip := c.ClientIP()
+	if addr, err := netip.ParseAddr(ip); err == nil {
+		return addr.Unmap().String()
+	}
+
+	return ip
+}
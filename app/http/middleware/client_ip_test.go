@@ -0,0 +1,65 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestClientIP(t *testing.T) {
+	tests := []struct {
+		name       string
+		trusted    []string
+		remoteAddr string
+		forwarded  string
+		realIP     string
+		want       string
+	}{
+		{name: "no trusted proxies ignores headers", remoteAddr: "10.0.0.2:5000", forwarded: "198.51.100.7", realIP: "198.51.100.8", want: "10.0.0.2"},
+		{name: "untrusted peer can't spoof forwarded for", trusted: []string{"10.0.0.0/8"}, remoteAddr: "198.51.100.7:5000", forwarded: "10.1.1.1", want: "198.51.100.7"},
+		{name: "untrusted peer can't spoof real ip", trusted: []string{"10.0.0.0/8"}, remoteAddr: "198.51.100.7:5000", realIP: "10.1.1.1", want: "198.51.100.7"},
+		{name: "trusted proxy", trusted: []string{"10.0.0.0/8"}, remoteAddr: "10.0.0.2:5000", forwarded: "198.51.100.7", want: "198.51.100.7"},
+		{name: "rightmost untrusted hop wins", trusted: []string{"10.0.0.0/8"}, remoteAddr: "10.0.0.2:5000", forwarded: "1.2.3.4, 198.51.100.7, 10.0.0.3", want: "198.51.100.7"},
+		{name: "forwarded for before real ip", trusted: []string{"10.0.0.0/8"}, remoteAddr: "10.0.0.2:5000", forwarded: "198.51.100.7", realIP: "198.51.100.8", want: "198.51.100.7"},
+		{name: "real ip without forwarded for", trusted: []string{"10.0.0.0/8"}, remoteAddr: "10.0.0.2:5000", realIP: "198.51.100.8", want: "198.51.100.8"},
+		{name: "ipv6 trusted proxy", trusted: []string{"fd00::/8"}, remoteAddr: "[fd00::2]:5000", forwarded: "2001:db8::7", want: "2001:db8::7"},
+		{name: "ipv4 mapped ipv6 peer", remoteAddr: "[::ffff:198.51.100.7]:5000", want: "198.51.100.7"},
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := gin.New()
+			if err := engine.SetTrustedProxies(tt.trusted); err != nil {
+				t.Fatalf("SetTrustedProxies() error = %v", err)
+			}
+
+			var got string
+			engine.GET("/", func(c *gin.Context) {
+				got = ClientIP(c)
+			})
+
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = tt.remoteAddr
+			if tt.forwarded != "" {
+				r.Header.Set("X-Forwarded-For", tt.forwarded)
+			}
+			if tt.realIP != "" {
+				r.Header.Set("X-Real-IP", tt.realIP)
+			}
+
+			engine.ServeHTTP(httptest.NewRecorder(), r)
+
+			if got != tt.want {
+				t.Errorf("ClientIP() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,147 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seakee/go-api/app/config"
+	"github.com/seakee/go-api/app/pkg/e"
+)
+
+const (
+	// CSRFCookie is the cookie carrying the CSRF token; it is readable by scripts, unlike the token cookie.
+	CSRFCookie = "go_api_csrf"
+	// CSRFHeader is the request header browser apps copy the CSRF cookie into on unsafe requests.
+	CSRFHeader = "X-CSRF-Token"
+)
+
+// csrfSafeMethods are the methods that must not change state, exempt from the CSRF check.
+var csrfSafeMethods = map[string]struct{}{
+	http.MethodGet:     {},
+	http.MethodHead:    {},
+	http.MethodOptions: {},
+	http.MethodTrace:   {},
+}
+
+// CSRF returns a Gin middleware function that protects cookie authentication against
+// cross-site request forgery with a double-submit cookie.
+//
+// It sets a random token in the CSRFCookie cookie when the request lacks one, and rejects
+// unsafe requests authenticated by a token cookie unless the CSRFHeader header matches the
+// cookie, with 403 and e.CSRFTokenMismatch. A forged request carries the cookies of the victim
+// but its origin can't read them to set the header.
+//
+// The middleware passes every request through when no Auth.TokenSources reads a cookie, and
+// requests without a token cookie (header-token API clients) are never checked. Safe methods
+// and public routes, e.g. the token endpoint, are exempt.
+//
+// Returns:
+//   - gin.HandlerFunc: A middleware function for Gin framework.
+func (m middleware) CSRF() gin.HandlerFunc {
+	cfg := config.Get()
+	if cfg == nil {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	var authCookies []string
+	for _, source := range cfg.Auth.TokenSources {
+		if source.From == config.TokenFromCookie {
+			authCookies = append(authCookies, source.Name)
+		}
+	}
+
+	if len(authCookies) == 0 {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	path := cfg.Auth.Cookie.Path
+	if path == "" {
+		path = "/"
+	}
+
+	return func(c *gin.Context) {
+		if _, err := c.Cookie(CSRFCookie); err != nil {
+			token, err := newCSRFToken()
+			if err != nil {
+				m.abortWithStatus(c, http.StatusInternalServerError, e.ERROR, err)
+				return
+			}
+
+			// Session cookie readable by the browser app, sent back in CSRFHeader
+			c.SetSameSite(http.SameSiteStrictMode)
+			c.SetCookie(CSRFCookie, token, 0, path, cfg.Auth.Cookie.Domain, cfg.Auth.Cookie.Secure, false)
+		}
+
+		if err := checkCSRF(c, authCookies); err != nil {
+			m.abortWithStatus(c, http.StatusForbidden, e.CSRFTokenMismatch, err)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// checkCSRF checks the double-submit token of a request.
+//
+// Parameters:
+//   - c: *gin.Context - The Gin context of the request.
+//   - authCookies: []string - The names of the cookies carrying app tokens.
+//
+// Returns:
+//   - error: nil if the request is exempt or its CSRFHeader matches its CSRFCookie, otherwise the reason it is rejected.
+func checkCSRF(c *gin.Context, authCookies []string) error {
+	if _, ok := csrfSafeMethods[c.Request.Method]; ok || isPublicRoute(c.Request.Method, c.FullPath()) {
+		return nil
+	}
+
+	// Only the requests a browser authenticates on its own can be forged
+	authenticated := false
+	for _, name := range authCookies {
+		if value, err := c.Cookie(name); err == nil && value != "" {
+			authenticated = true
+			break
+		}
+	}
+
+	if !authenticated {
+		return nil
+	}
+
+	cookie, err := c.Cookie(CSRFCookie)
+	if err != nil || cookie == "" {
+		return errors.New("missing csrf cookie")
+	}
+
+	header := c.GetHeader(CSRFHeader)
+	if header == "" {
+		return errors.New("missing csrf header")
+	}
+
+	if subtle.ConstantTimeCompare([]byte(header), []byte(cookie)) != 1 {
+		return errors.New("csrf header does not match the cookie")
+	}
+
+	return nil
+}
+
+// newCSRFToken returns a random CSRF token.
+func newCSRFToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
@@ -0,0 +1,70 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestCheckCSRF(t *testing.T) {
+	const (
+		authCookie = "go_api_token"
+		csrf       = "Zm9vYmFyYmF6cXV4"
+	)
+
+	tests := []struct {
+		name    string
+		method  string
+		path    string
+		cookies map[string]string
+		header  string
+		wantErr bool
+	}{
+		{name: "matching token", method: http.MethodPost, path: "/go-api/external/service/auth/app",
+			cookies: map[string]string{authCookie: "jwt", CSRFCookie: csrf}, header: csrf},
+		{name: "missing header", method: http.MethodPost, path: "/go-api/external/service/auth/app",
+			cookies: map[string]string{authCookie: "jwt", CSRFCookie: csrf}, wantErr: true},
+		{name: "missing cookie", method: http.MethodPost, path: "/go-api/external/service/auth/app",
+			cookies: map[string]string{authCookie: "jwt"}, header: csrf, wantErr: true},
+		{name: "mismatched token", method: http.MethodDelete, path: "/go-api/external/service/auth/app",
+			cookies: map[string]string{authCookie: "jwt", CSRFCookie: csrf}, header: csrf + "x", wantErr: true},
+		{name: "safe method", method: http.MethodGet, path: "/go-api/external/service/auth/app",
+			cookies: map[string]string{authCookie: "jwt"}},
+		{name: "public route", method: http.MethodPost, path: "/go-api/external/service/auth/token",
+			cookies: map[string]string{authCookie: "jwt"}},
+		{name: "header token client", method: http.MethodPost, path: "/go-api/external/service/auth/app"},
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine := gin.New()
+
+			var err error
+			engine.Handle(tt.method, tt.path, func(c *gin.Context) {
+				err = checkCSRF(c, []string{authCookie})
+			})
+
+			r := httptest.NewRequest(tt.method, tt.path, nil)
+			for name, value := range tt.cookies {
+				r.AddCookie(&http.Cookie{Name: name, Value: value})
+			}
+			if tt.header != "" {
+				r.Header.Set(CSRFHeader, tt.header)
+			}
+
+			engine.ServeHTTP(httptest.NewRecorder(), r)
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkCSRF() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+// PublicRoutes exposes publicRoutes to the tests walking the real router.
+var PublicRoutes = publicRoutes
+
+// IsPublicRoute exposes isPublicRoute to the tests walking the real router.
+var IsPublicRoute = isPublicRoute
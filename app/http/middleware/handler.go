@@ -6,9 +6,13 @@
 package middleware
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
+	"github.com/seakee/go-api/app/pkg/alert"
+	"github.com/seakee/go-api/app/pkg/lang"
+	"github.com/seakee/go-api/app/pkg/robot"
 	"github.com/seakee/go-api/app/pkg/trace"
-	"github.com/sk-pkg/i18n"
 	"github.com/sk-pkg/logger"
 	"github.com/sk-pkg/redis"
 	"gorm.io/gorm"
@@ -16,32 +20,43 @@ import (
 
 // Middleware interface defines the methods that should be implemented by middleware handlers.
 type Middleware interface {
+	Cache(ttl time.Duration, keyFunc CacheKeyFunc, tags ...string) gin.HandlerFunc
 	CheckAppAuth() gin.HandlerFunc
 	Cors() gin.HandlerFunc
+	CSRF() gin.HandlerFunc
+	Idempotency() gin.HandlerFunc
+	IdempotencyWithoutReplay() gin.HandlerFunc
+	IPFilter() gin.HandlerFunc
+	Recovery() gin.HandlerFunc
 	RequestLogger() gin.HandlerFunc
 	SetTraceID() gin.HandlerFunc
+	Timeout() gin.HandlerFunc
 }
 
 // middleware struct implements the Middleware interface.
 type middleware struct {
 	logger  *logger.Manager
-	i18n    *i18n.Manager
+	i18n    *lang.Manager
 	db      map[string]*gorm.DB
 	redis   map[string]*redis.Manager
 	traceID *trace.ID
+	robot   *robot.Robot
+	alert   *alert.Alerter
 }
 
 // New creates and returns a new Middleware instance.
 //
 // Parameters:
 //   - logger: *logger.Manager - The logger manager.
-//   - i18n: *i18n.Manager - The internationalization manager.
+//   - i18n: *lang.Manager - The internationalization manager.
 //   - db: map[string]*gorm.DB - A map of database connections.
 //   - redis: map[string]*redis.Manager - A map of Redis managers.
 //   - traceID: *trace.ID - The trace ID generator.
+//   - robot: *robot.Robot - The robot panics are reported to, throttling identical panics.
+//   - alerter: *alert.Alerter - The alert facade panics are sent to as "panic_report" cards.
 //
 // Returns:
 //   - Middleware: A new Middleware instance.
-func New(logger *logger.Manager, i18n *i18n.Manager, db map[string]*gorm.DB, redis map[string]*redis.Manager, traceID *trace.ID) Middleware {
-	return &middleware{logger: logger, i18n: i18n, db: db, redis: redis, traceID: traceID}
+func New(logger *logger.Manager, i18n *lang.Manager, db map[string]*gorm.DB, redis map[string]*redis.Manager, traceID *trace.ID, robot *robot.Robot, alerter *alert.Alerter) Middleware {
+	return &middleware{logger: logger, i18n: i18n, db: db, redis: redis, traceID: traceID, robot: robot, alert: alerter}
 }
@@ -0,0 +1,208 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seakee/go-api/app/pkg/e"
+	"github.com/sk-pkg/util"
+)
+
+const (
+	// IdempotencyKeyHeader is the request header carrying the client-generated idempotency key.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// IdempotencyReplayedHeader is set on responses replayed from a previous request.
+	IdempotencyReplayedHeader = "Idempotency-Replayed"
+
+	idempotencyResponseTTL = 24 * 3600 // How long a successful response is replayed, in seconds
+	idempotencyLockTTL     = 60        // How long a request holds the in-flight lock, in seconds
+	idempotencyMaxBodySize = 1 << 20   // Largest request body accepted with an idempotency key, in bytes
+
+	// idempotencyUnlockScript deletes the in-flight lock only while it still holds the request's token.
+	idempotencyUnlockScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) end return 0`
+)
+
+// idempotentResponse is the response stored in Redis for replay.
+type idempotentResponse struct {
+	Status      int    `json:"status"`
+	ContentType string `json:"content_type"`
+	Body        []byte `json:"body"`
+	RequestHash string `json:"request_hash"` // SHA-256 of the request body the response was produced for
+	Withheld    bool   `json:"withheld"`     // The response isn't stored, only the completion of the request
+}
+
+// idempotencyStore is the part of redis.Manager used by Idempotency.
+type idempotencyStore interface {
+	GetJSON(key string, value any) error
+	SetJSON(key string, value any, expiration int) error
+	SetNX(key string, value any, sec int) (bool, error)
+	Lua(keyCount int, script string, keysAndArgs []string) (any, error)
+}
+
+// Idempotency returns a Gin middleware function that makes retried requests safe.
+//
+// When the request carries an "Idempotency-Key" header, the first successful response is stored
+// in Redis, keyed by the idempotency key, the route and the authenticated app, and replayed for
+// subsequent requests with the same key. A request arriving while another with the same key is
+// still in progress is rejected with 409 and e.RequestInProgress, and a request reusing the key
+// of a stored response with a different body is rejected with 422 and e.IdempotencyKeyReused.
+// Request bodies over 1 MiB are rejected with 413, as the whole body is hashed.
+//
+// Only responses with a 2xx status and e.SUCCESS as the code of the response envelope are
+// stored, so a failed attempt can be retried with the same key. Requests without the header,
+// and every request when Redis isn't configured, pass through untouched.
+// Register it after CheckAppAuth so the app is part of the key.
+//
+// Returns:
+//   - gin.HandlerFunc: A middleware function for Gin framework.
+func (m middleware) Idempotency() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		r := m.redis["go-api"]
+		if r == nil || c.GetHeader(IdempotencyKeyHeader) == "" {
+			c.Next()
+			return
+		}
+
+		m.idempotent(c, r, r.Prefix, true)
+	}
+}
+
+// IdempotencyWithoutReplay returns a Gin middleware function like Idempotency that never stores
+// the response.
+//
+// Use it on routes whose responses carry secrets, e.g. app credentials, which must not sit in
+// Redis. Only the completion of the request is recorded: a retry after a successful request is
+// rejected with 409 and e.IdempotencyKeyCompleted instead of being replayed, so the client
+// fetches the resource instead of creating it twice.
+//
+// Returns:
+//   - gin.HandlerFunc: A middleware function for Gin framework.
+func (m middleware) IdempotencyWithoutReplay() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		r := m.redis["go-api"]
+		if r == nil || c.GetHeader(IdempotencyKeyHeader) == "" {
+			c.Next()
+			return
+		}
+
+		m.idempotent(c, r, r.Prefix, false)
+	}
+}
+
+// idempotent runs the request identified by its idempotency key at most once with success,
+// replaying the stored response afterward.
+//
+// Parameters:
+//   - c: *gin.Context - The Gin context of the request.
+//   - store: idempotencyStore - The store of the responses and in-flight locks.
+//   - prefix: string - The key prefix of store, added to the keys passed to Lua scripts.
+//   - replay: bool - Whether the response is stored and replayed, or withheld.
+func (m middleware) idempotent(c *gin.Context, store idempotencyStore, prefix string, replay bool) {
+	requestHash, err := hashRequestBody(c)
+	if err != nil {
+		status := http.StatusBadRequest
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			status = http.StatusRequestEntityTooLarge
+		}
+
+		m.abortWithStatus(c, status, e.InvalidParams, err)
+		return
+	}
+
+	key := c.GetHeader(IdempotencyKeyHeader)
+	hash := util.MD5(util.SpliceStr(c.Request.Method, ":", c.FullPath(), ":", c.GetString("app_id"), ":", key))
+	respKey := util.SpliceStr("idempotency:response:", hash)
+	lockKey := util.SpliceStr("idempotency:lock:", hash)
+
+	// Replay the stored response of a previous successful request
+	var stored idempotentResponse
+	if err = store.GetJSON(respKey, &stored); err == nil {
+		if stored.RequestHash != requestHash {
+			m.abortWithStatus(c, http.StatusUnprocessableEntity, e.IdempotencyKeyReused, errors.New("idempotency key reused with a different request body"))
+			return
+		}
+
+		if stored.Withheld {
+			m.abortWithStatus(c, http.StatusConflict, e.IdempotencyKeyCompleted, errors.New("request with the same idempotency key already succeeded"))
+			return
+		}
+
+		c.Header(IdempotencyReplayedHeader, "true")
+		c.Data(stored.Status, stored.ContentType, stored.Body)
+		c.Abort()
+		return
+	}
+
+	// Reject the request if another one with the same key is still in progress
+	token := util.RandLowStr(16)
+	locked, err := store.SetNX(lockKey, token, idempotencyLockTTL)
+	if err != nil {
+		m.abortWithStatus(c, http.StatusServiceUnavailable, e.BUSY, err)
+		return
+	}
+
+	if !locked {
+		m.abortWithStatus(c, http.StatusConflict, e.RequestInProgress, errors.New("request with the same idempotency key is in progress"))
+		return
+	}
+
+	// Release the lock only if it is still ours: past idempotencyLockTTL it may be another request's
+	defer func() {
+		_, _ = store.Lua(1, idempotencyUnlockScript, []string{prefix + lockKey, token})
+	}()
+
+	writer := &bodyWriter{ResponseWriter: c.Writer, body: &bytes.Buffer{}}
+	c.Writer = writer
+
+	c.Next()
+
+	// Store only successful responses
+	status := c.Writer.Status()
+	if status < http.StatusOK || status >= http.StatusMultipleChoices || !successEnvelope(writer.body.Bytes()) {
+		return
+	}
+
+	stored = idempotentResponse{Status: status, RequestHash: requestHash, Withheld: !replay}
+	if replay {
+		stored.ContentType = c.Writer.Header().Get("Content-Type")
+		stored.Body = writer.body.Bytes()
+	}
+
+	_ = store.SetJSON(respKey, stored, idempotencyResponseTTL)
+}
+
+// hashRequestBody returns the hex encoded SHA-256 hash of the request body, and restores the
+// body for the handlers.
+//
+// Parameters:
+//   - c: *gin.Context - The Gin context of the request.
+//
+// Returns:
+//   - string: The hash of the body; the hash of an empty body when the request has none.
+//   - error: An error if the body can't be read, a *http.MaxBytesError if it is over
+//     idempotencyMaxBodySize bytes.
+func hashRequestBody(c *gin.Context) (string, error) {
+	var body []byte
+	if c.Request.Body != nil {
+		var err error
+		if body, err = io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, idempotencyMaxBodySize)); err != nil {
+			return "", err
+		}
+
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	sum := sha256.Sum256(body)
+
+	return hex.EncodeToString(sum[:]), nil
+}
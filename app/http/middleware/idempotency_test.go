@@ -0,0 +1,215 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seakee/go-api/app/pkg/e"
+	"github.com/seakee/go-api/app/pkg/lang"
+	"github.com/sk-pkg/i18n"
+)
+
+// fakeIdempotencyStore is an in-memory idempotencyStore.
+type fakeIdempotencyStore struct {
+	values map[string][]byte
+}
+
+func (s *fakeIdempotencyStore) GetJSON(key string, value any) error {
+	b, ok := s.values[key]
+	if !ok {
+		return errors.New("nil returned")
+	}
+
+	return json.Unmarshal(b, value)
+}
+
+func (s *fakeIdempotencyStore) SetJSON(key string, value any, _ int) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	s.values[key] = b
+
+	return nil
+}
+
+func (s *fakeIdempotencyStore) SetNX(key string, value any, _ int) (bool, error) {
+	if _, ok := s.values[key]; ok {
+		return false, nil
+	}
+
+	s.values[key] = []byte(value.(string))
+
+	return true, nil
+}
+
+func (s *fakeIdempotencyStore) Lua(_ int, _ string, keysAndArgs []string) (any, error) {
+	if string(s.values[keysAndArgs[0]]) != keysAndArgs[1] {
+		return int64(0), nil
+	}
+
+	delete(s.values, keysAndArgs[0])
+
+	return int64(1), nil
+}
+
+// newTestI18n returns an i18n manager rendering the built-in messages of the e package.
+func newTestI18n(t *testing.T) *lang.Manager {
+	dir := t.TempDir()
+	for _, name := range []string{"en-US.json", "zh-CN.json"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(`{"0": "ok"}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manager, err := i18n.New(i18n.WithLangDir(dir))
+	if err != nil {
+		t.Fatalf("i18n.New() error = %v", err)
+	}
+
+	return lang.New(manager, "en-US")
+}
+
+func TestIdempotencyStoresOnlySuccessfulResponses(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name         string
+		code         int
+		wantCalls    int
+		wantReplayed bool
+	}{
+		// Handlers render failures through I18n.JSON with a 200 status
+		{name: "failed create is retried", code: e.ServerAppAlreadyExists, wantCalls: 2},
+		{name: "successful create is replayed", code: e.SUCCESS, wantCalls: 1, wantReplayed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &fakeIdempotencyStore{values: make(map[string][]byte)}
+			engine := gin.New()
+
+			calls := 0
+			engine.POST("/app", func(c *gin.Context) { middleware{}.idempotent(c, store, "", true) }, func(c *gin.Context) {
+				calls++
+				c.JSON(http.StatusOK, gin.H{"code": tt.code, "msg": "", "data": nil})
+			})
+
+			var last *httptest.ResponseRecorder
+			for i := 0; i < 2; i++ {
+				req := httptest.NewRequest(http.MethodPost, "/app", strings.NewReader(`{"app_name":"a"}`))
+				req.Header.Set(IdempotencyKeyHeader, "key")
+
+				last = httptest.NewRecorder()
+				engine.ServeHTTP(last, req)
+			}
+
+			if calls != tt.wantCalls {
+				t.Errorf("handler calls = %d, want %d", calls, tt.wantCalls)
+			}
+
+			if replayed := last.Header().Get(IdempotencyReplayedHeader) == "true"; replayed != tt.wantReplayed {
+				t.Errorf("second response replayed = %v, want %v", replayed, tt.wantReplayed)
+			}
+
+			for key := range store.values {
+				if strings.HasPrefix(key, "idempotency:lock:") {
+					t.Errorf("lock %s still held after the request", key)
+				}
+			}
+		})
+	}
+}
+
+func TestIdempotencyWithoutReplayStoresNoSecret(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	m := middleware{i18n: newTestI18n(t)}
+	store := &fakeIdempotencyStore{values: make(map[string][]byte)}
+	engine := gin.New()
+
+	calls := 0
+	engine.POST("/app", func(c *gin.Context) { m.idempotent(c, store, "", false) }, func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusOK, gin.H{"code": e.SUCCESS, "msg": "ok", "data": gin.H{"app_id": "go-api-abcdefgh", "app_secret": "SECRET"}})
+	})
+
+	var last *httptest.ResponseRecorder
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodPost, "/app", strings.NewReader(`{"app_name":"a"}`))
+		req.Header.Set(IdempotencyKeyHeader, "key")
+
+		last = httptest.NewRecorder()
+		engine.ServeHTTP(last, req)
+	}
+
+	if calls != 1 {
+		t.Errorf("handler calls = %d, want 1", calls)
+	}
+
+	if last.Code != http.StatusConflict || strings.Contains(last.Body.String(), "SECRET") {
+		t.Errorf("retry = %d %s, want 409 without the secret", last.Code, last.Body.String())
+	}
+
+	var envelope struct {
+		Code int `json:"code"`
+	}
+	if err := json.Unmarshal(last.Body.Bytes(), &envelope); err != nil || envelope.Code != e.IdempotencyKeyCompleted {
+		t.Errorf("retry code = %d, %v, want %d", envelope.Code, err, e.IdempotencyKeyCompleted)
+	}
+
+	for key, value := range store.values {
+		if strings.Contains(string(value), "app_secret") || strings.Contains(string(value), "SECRET") {
+			t.Errorf("stored value of %s holds the secret: %s", key, value)
+		}
+	}
+}
+
+func TestHashRequestBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	hash := func(body string) (string, string) {
+		engine := gin.New()
+
+		var got, restored string
+		engine.POST("/app", func(c *gin.Context) {
+			var err error
+			if got, err = hashRequestBody(c); err != nil {
+				t.Fatalf("hashRequestBody() error = %v", err)
+			}
+
+			b, _ := io.ReadAll(c.Request.Body)
+			restored = string(b)
+		})
+
+		engine.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/app", strings.NewReader(body)))
+
+		return got, restored
+	}
+
+	first, restored := hash(`{"app_name":"a"}`)
+	if restored != `{"app_name":"a"}` {
+		t.Errorf("body after hashing = %q, want it restored", restored)
+	}
+
+	if again, _ := hash(`{"app_name":"a"}`); again != first {
+		t.Errorf("hash of the same body = %s, want %s", again, first)
+	}
+
+	if other, _ := hash(`{"app_name":"b"}`); other == first {
+		t.Error("different bodies have the same hash")
+	}
+}
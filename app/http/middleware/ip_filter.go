@@ -0,0 +1,52 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/netip"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seakee/go-api/app/config"
+	"github.com/seakee/go-api/app/pkg/e"
+	"github.com/seakee/go-api/app/pkg/ipfilter"
+)
+
+// IPFilter returns a Gin middleware function that restricts access by client IP.
+//
+// The client IP is resolved with ClientIP, trusting the X-Forwarded-For and X-Real-IP headers
+// only from System.TrustedProxies. Clients in IPFilter.Deny, or outside
+// IPFilter.Allow when it is set, receive a 403 response with e.Forbidden. The middleware passes
+// every request through when IPFilter.Enable is false.
+//
+// It panics on invalid CIDRs, so a broken allow list stops the service at startup instead of
+// leaving it open.
+//
+// Returns:
+//   - gin.HandlerFunc: A middleware function for Gin framework.
+func (m middleware) IPFilter() gin.HandlerFunc {
+	cfg := config.Get()
+	if cfg == nil || !cfg.IPFilter.Enable {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	filter, err := ipfilter.New(cfg.IPFilter.Allow, cfg.IPFilter.Deny)
+	if err != nil {
+		panic(fmt.Sprintf("invalid ip_filter configuration: %v", err))
+	}
+
+	return func(c *gin.Context) {
+		ip := ClientIP(c)
+		if addr, _ := netip.ParseAddr(ip); !filter.Allowed(addr) {
+			m.abortWithStatus(c, http.StatusForbidden, e.Forbidden, fmt.Errorf("client ip %q is not allowed", ip))
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestLang returns the language requested by the client, from the "lang" header or the
+// "lang=" parameter of the User-Agent, like I18n.JSON does. An empty result selects the default language.
+//
+// Parameters:
+//   - c: *gin.Context - The Gin context of the request.
+//
+// Returns:
+//   - string: The language, e.g. "en-US".
+func RequestLang(c *gin.Context) string {
+	if lang := c.GetHeader("lang"); lang != "" {
+		return lang
+	}
+
+	for _, param := range strings.Split(c.Request.UserAgent(), ";") {
+		if name, value, ok := strings.Cut(param, "="); ok && name == "lang" {
+			return value
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,34 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+// publicRoutes are the external routes served without app authentication, as the method and
+// the gin path pattern of the route, e.g. "GET /go-api/external/version".
+//
+// CheckAppAuth guards the whole external group and lets these routes through, so a new
+// endpoint requires authentication unless it is listed here. Keep the list short and review
+// every addition: a route listed here is reachable by anyone. The internal group is served
+// without app authentication and is restricted by the network and IPFilter instead.
+var publicRoutes = map[string]struct{}{
+	"GET /go-api/external/ping":                {},
+	"GET /go-api/external/version":             {},
+	"GET /go-api/external/config/public":       {},
+	"GET /go-api/external/service/ping":        {},
+	"GET /go-api/external/service/auth/ping":   {},
+	"POST /go-api/external/service/auth/token": {},
+}
+
+// isPublicRoute reports whether the route of method and fullPath is served without app authentication.
+//
+// Parameters:
+//   - method: string - The HTTP method of the request.
+//   - fullPath: string - The path pattern of the matched route, as returned by gin.Context.FullPath.
+//
+// Returns:
+//   - bool: true if the route is listed in publicRoutes.
+func isPublicRoute(method, fullPath string) bool {
+	_, ok := publicRoutes[method+" "+fullPath]
+	return ok
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seakee/go-api/app/config"
+	appHttp "github.com/seakee/go-api/app/http"
+	"github.com/seakee/go-api/app/http/middleware"
+	"github.com/seakee/go-api/app/http/router"
+	"github.com/seakee/go-api/app/http/ws"
+	"github.com/sk-pkg/logger"
+	"go.uber.org/zap"
+)
+
+// authCheckedHeader marks the responses rejected by fakeMiddleware.CheckAppAuth.
+const authCheckedHeader = "X-Auth-Checked"
+
+// fakeMiddleware passes every request through, except CheckAppAuth which rejects the requests
+// to the routes that aren't public.
+type fakeMiddleware struct{}
+
+func passThrough(c *gin.Context) { c.Next() }
+
+func (fakeMiddleware) Cache(time.Duration, middleware.CacheKeyFunc, ...string) gin.HandlerFunc {
+	return passThrough
+}
+func (fakeMiddleware) Cors() gin.HandlerFunc          { return passThrough }
+func (fakeMiddleware) CSRF() gin.HandlerFunc          { return passThrough }
+func (fakeMiddleware) Idempotency() gin.HandlerFunc   { return passThrough }
+func (fakeMiddleware) IPFilter() gin.HandlerFunc      { return passThrough }
+func (fakeMiddleware) Recovery() gin.HandlerFunc      { return passThrough }
+func (fakeMiddleware) RequestLogger() gin.HandlerFunc { return passThrough }
+func (fakeMiddleware) SetTraceID() gin.HandlerFunc    { return passThrough }
+func (fakeMiddleware) Timeout() gin.HandlerFunc       { return passThrough }
+
+func (fakeMiddleware) CheckAppAuth() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if middleware.IsPublicRoute(c.Request.Method, c.FullPath()) {
+			c.Next()
+			return
+		}
+
+		c.Header(authCheckedHeader, "true")
+		c.AbortWithStatus(http.StatusUnauthorized)
+	}
+}
+
+// routeParam matches the parameters of a gin path pattern.
+var routeParam = regexp.MustCompile(`[:*][^/]+`)
+
+// newRouter builds the application router with every optional route group enabled.
+func newRouter(t *testing.T) *gin.Engine {
+	t.Helper()
+
+	log := &logger.Manager{Zap: zap.NewNop()}
+	cfg := &config.Config{Docs: config.Docs{Enable: true}}
+
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	router.Register(engine, &appHttp.Context{
+		Logger:     log,
+		Middleware: fakeMiddleware{},
+		Config:     cfg,
+		WSHub:      ws.NewHub(cfg.WebSocket, log),
+	})
+
+	return engine
+}
+
+func TestPublicRoutesExist(t *testing.T) {
+	routes := make(map[string]struct{})
+	for _, route := range newRouter(t).Routes() {
+		routes[route.Method+" "+route.Path] = struct{}{}
+	}
+
+	for route := range middleware.PublicRoutes {
+		if _, ok := routes[route]; !ok {
+			t.Errorf("public route %q is not registered", route)
+		}
+	}
+}
+
+func TestExternalRoutesRequireAuth(t *testing.T) {
+	engine := newRouter(t)
+
+	checked := 0
+	for _, route := range engine.Routes() {
+		if !strings.HasPrefix(route.Path, "/go-api/external/") || middleware.IsPublicRoute(route.Method, route.Path) {
+			continue
+		}
+
+		path := routeParam.ReplaceAllString(route.Path, "1")
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(route.Method, path, nil))
+
+		if w.Code != http.StatusUnauthorized || w.Header().Get(authCheckedHeader) == "" {
+			t.Errorf("%s %s is served without CheckAppAuth (status %d)", route.Method, route.Path, w.Code)
+		}
+
+		checked++
+	}
+
+	if checked == 0 {
+		t.Fatal("no external route found")
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestIsPublicRoute(t *testing.T) {
+	tests := []struct {
+		method   string
+		fullPath string
+		want     bool
+	}{
+		{http.MethodPost, "/go-api/external/service/auth/token", true},
+		{http.MethodGet, "/go-api/external/service/auth/token", false},
+		{http.MethodGet, "/go-api/external/service/auth/app", false},
+		{http.MethodGet, "/go-api/external/service/auth/app/:id", false},
+		{http.MethodGet, "/go-api/external/ping/", false},
+		{http.MethodGet, "", false},
+	}
+
+	for _, tt := range tests {
+		if got := isPublicRoute(tt.method, tt.fullPath); got != tt.want {
+			t.Errorf("isPublicRoute(%s, %q) = %v, want %v", tt.method, tt.fullPath, got, tt.want)
+		}
+	}
+}
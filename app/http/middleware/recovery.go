@@ -0,0 +1,87 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seakee/go-api/app/pkg/alert"
+	"github.com/seakee/go-api/app/pkg/e"
+	"github.com/seakee/go-api/app/pkg/larkcard"
+	"github.com/seakee/go-api/app/pkg/sanitize"
+	"github.com/sk-pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Recovery returns a Gin middleware function that recovers from panics in later handlers.
+//
+// The panic is logged with its stack. It is also posted to the configured panic robots, and
+// sent through the alert facade as an error level "panic_report" card, together with the
+// request method, URI and trace ID; sensitive query parameters are masked with sanitize.URL.
+// Identical panics (same value on the same route) are reported at most once per
+// robot.DefaultThrottle. The client receives a 500 response with e.ERROR through I18n.JSON.
+//
+// Returns:
+//   - gin.HandlerFunc: A middleware function for Gin framework.
+func (m middleware) Recovery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+
+			stack := debug.Stack()
+			traceID := c.GetString("trace_id")
+			ctx := context.WithValue(context.Background(), logger.TraceIDKey, traceID)
+
+			m.logger.Error(ctx, "http handler has a panic error", zap.Any("error", r), zap.ByteString("stack", stack))
+
+			// Throttle identical panics in front of every channel
+			if m.robot.Allow(fmt.Sprintf("%v@%s", r, c.FullPath())) {
+				// The reports leave for external services, so mask secrets passed in the query
+				m.reportPanic(ctx, c.Request.Method+" "+sanitize.URL(c.Request.URL.RequestURI(), nil), traceID, r, stack)
+			}
+
+			if c.Writer.Written() {
+				// The response has started, the status can no longer be changed
+				c.Abort()
+				return
+			}
+
+			m.abortWithStatus(c, http.StatusInternalServerError, e.ERROR, fmt.Errorf("panic: %v", r))
+		}()
+
+		c.Next()
+	}
+}
+
+// reportPanic posts a recovered panic to the panic robots and sends it as an alert card in the
+// background; the card leaves the stack out, it is logged with the trace ID.
+//
+// Parameters:
+//   - ctx: context.Context - The context carrying the trace ID.
+//   - request: string - The request method and sanitized URI.
+//   - traceID: string - The trace ID of the request.
+//   - r: any - The recovered value.
+//   - stack: []byte - The stack of the panic.
+func (m middleware) reportPanic(ctx context.Context, request, traceID string, r any, stack []byte) {
+	m.robot.Report(fmt.Sprintf("Request: %s\nTraceID: %s\nPanic: %v\n%s", request, traceID, r, stack))
+
+	card := larkcard.New("Panic recovered").
+		Text(fmt.Sprintf("%v", r)).
+		Field("Request", request).
+		Field("Trace ID", traceID)
+
+	go func() {
+		if err := m.alert.AlertCard(ctx, alert.ErrorLevel, "panic_report", card); err != nil {
+			m.logger.Error(ctx, "send panic alert failed", zap.Error(err))
+		}
+	}()
+}
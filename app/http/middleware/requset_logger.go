@@ -11,8 +11,10 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/seakee/go-api/app/config"
+	"github.com/seakee/go-api/app/pkg/logging"
+	"github.com/seakee/go-api/app/pkg/sanitize"
 	"github.com/sk-pkg/logger"
-	"github.com/sk-pkg/util"
 	"go.uber.org/zap"
 )
 
@@ -20,10 +22,25 @@ import (
 //
 // This middleware captures request details such as method, URI, status code, latency,
 // client IP, and request body. It logs this information using a structured logger.
+// The body and the query string are logged with the Log.RedactFields masked, the body is
+// truncated to Log.BodyMaxSize bytes, and the lines are sampled as configured in Log.Sampling
+// for the info level.
 //
 // Returns:
 //   - gin.HandlerFunc: A middleware function for Gin framework.
 func (m middleware) RequestLogger() gin.HandlerFunc {
+	var opts config.LogConfig
+	if cfg := config.Get(); cfg != nil {
+		opts = cfg.Log
+	}
+
+	var redactFields []string
+	if len(opts.RedactFields) > 0 {
+		redactFields = opts.RedactFields
+	}
+
+	log := logging.New(m.logger, opts)
+
 	return func(c *gin.Context) {
 		// Record start time
 		startTime := time.Now()
@@ -41,9 +58,9 @@ func (m middleware) RequestLogger() gin.HandlerFunc {
 
 		// Collect request details
 		reqMethod := c.Request.Method
-		reqUri := c.Request.RequestURI
+		reqUri := sanitize.URL(c.Request.RequestURI, redactFields)
 		statusCode := c.Writer.Status()
-		clientIP := util.GetRealIP(c)
+		clientIP := ClientIP(c)
 
 		// Get or generate trace ID
 		traceID, exists := c.Get("trace_id")
@@ -55,14 +72,14 @@ func (m middleware) RequestLogger() gin.HandlerFunc {
 		ctx := context.WithValue(context.Background(), logger.TraceIDKey, traceID.(string))
 
 		// Log request details
-		m.logger.Info(ctx,
+		log.Info(ctx,
 			"Request Logs",
 			zap.Int("StatusCode", statusCode),
 			zap.Any("Latency", latencyTime),
 			zap.String("IP", clientIP),
 			zap.String("Method", reqMethod),
 			zap.String("RequestPath", reqUri),
-			zap.Any("body", string(buf)),
+			zap.Any("body", sanitize.Body(buf, c.ContentType(), opts.BodyMaxSize, redactFields)),
 		)
 	}
 }
@@ -0,0 +1,55 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"bytes"
+
+	"github.com/gin-gonic/gin"
+)
+
+// statusWriter pins the HTTP status of a response, so I18n.JSON (which always renders 200)
+// can be used to write the standard envelope with another status code.
+type statusWriter struct {
+	gin.ResponseWriter
+	status int
+}
+
+// WriteHeader ignores the requested code and writes the pinned status.
+func (w *statusWriter) WriteHeader(int) {
+	w.ResponseWriter.WriteHeader(w.status)
+}
+
+// abortWithStatus aborts the request and responds with the standard I18n envelope and the given HTTP status.
+//
+// Parameters:
+//   - c: *gin.Context - The Gin context of the request.
+//   - status: int - The HTTP status code to respond with.
+//   - errCode: int - The API error code to respond with.
+//   - err: error - The underlying error, reported in the trace in debug mode.
+func (m middleware) abortWithStatus(c *gin.Context, status int, errCode int, err error) {
+	c.Writer = &statusWriter{ResponseWriter: c.Writer, status: status}
+	m.i18n.JSON(c, errCode, nil, err)
+	c.Abort()
+}
+
+// bodyWriter tees everything written to the response into a buffer, so middleware
+// can inspect the response body after the handler has run.
+type bodyWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+// Write writes b to the response and to the buffer.
+func (w *bodyWriter) Write(b []byte) (int, error) {
+	w.body.Write(b)
+	return w.ResponseWriter.Write(b)
+}
+
+// WriteString writes s to the response and to the buffer.
+func (w *bodyWriter) WriteString(s string) (int, error) {
+	w.body.WriteString(s)
+	return w.ResponseWriter.WriteString(s)
+}
@@ -0,0 +1,61 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seakee/go-api/app/config"
+	"github.com/seakee/go-api/app/pkg/e"
+)
+
+// Timeout returns a Gin middleware function that sets a deadline on the request context.
+//
+// The deadline is System.RequestTimeout seconds; routes listed in System.RequestTimeoutSkip
+// (matched against the route path, e.g. "/go-api/external/service/export") run without one.
+// Handlers get the deadline through h.Context(c), so gorm, qmgo, redis and resty calls made with
+// that context are cancelled once it passes. If the deadline passed and the handler wrote nothing,
+// the client receives a 504 response with e.RequestTimeout through I18n.JSON.
+//
+// Returns:
+//   - gin.HandlerFunc: A middleware function for Gin framework.
+func (m middleware) Timeout() gin.HandlerFunc {
+	var timeout time.Duration
+	skip := make(map[string]struct{})
+
+	if cfg := config.Get(); cfg != nil {
+		timeout = cfg.System.RequestTimeout * time.Second
+		for _, path := range cfg.System.RequestTimeoutSkip {
+			skip[path] = struct{}{}
+		}
+	}
+
+	return func(c *gin.Context) {
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		if _, ok := skip[c.FullPath()]; ok {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) && !c.Writer.Written() {
+			m.abortWithStatus(c, http.StatusGatewayTimeout, e.RequestTimeout, ctx.Err())
+		}
+	}
+}
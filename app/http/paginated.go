@@ -0,0 +1,43 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package http
+
+// Paginated is the standard response envelope for paginated lists.
+type Paginated[T any] struct {
+	List       []T   `json:"list"`        // Items of the current page
+	Total      int64 `json:"total"`       // Total number of matching items
+	Page       int   `json:"page"`        // Current page (1-based)
+	PageSize   int   `json:"page_size"`   // Number of items per page
+	TotalPages int   `json:"total_pages"` // Total number of pages
+}
+
+// NewPaginated creates a Paginated envelope and derives the total number of pages.
+//
+// Parameters:
+//   - list: The items of the current page.
+//   - total: The total number of matching items.
+//   - page: The current page (1-based).
+//   - pageSize: The number of items per page.
+//
+// Returns:
+//   - Paginated[T]: The populated envelope. A nil list is emitted as an empty array.
+func NewPaginated[T any](list []T, total int64, page, pageSize int) Paginated[T] {
+	if list == nil {
+		list = []T{}
+	}
+
+	totalPages := 0
+	if pageSize > 0 {
+		totalPages = int((total + int64(pageSize) - 1) / int64(pageSize))
+	}
+
+	return Paginated[T]{
+		List:       list,
+		Total:      total,
+		Page:       page,
+		PageSize:   pageSize,
+		TotalPages: totalPages,
+	}
+}
@@ -4,14 +4,33 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/seakee/go-api/app/http"
 	"github.com/seakee/go-api/app/http/router/external/service"
+	"github.com/seakee/go-api/app/http/router/external/ws"
+	"github.com/seakee/go-api/app/pkg/buildinfo"
+	"github.com/seakee/go-api/app/pkg/e"
 )
 
 func RegisterRoutes(api *gin.RouterGroup, ctx *http.Context) {
+	// Every external route requires app authentication, except those listed as public in the middleware,
+	// and a CSRF token when authenticated by a cookie
+	api.Use(ctx.Middleware.CheckAppAuth(), ctx.Middleware.CSRF())
+
 	api.GET("ping", func(c *gin.Context) {
 		ctx.I18n.JSON(c, 0, nil, nil)
 	})
 
+	api.GET("version", func(c *gin.Context) {
+		ctx.I18n.JSON(c, e.SUCCESS, buildinfo.Get(ctx.Config.System.Name, ctx.Config.System.Version), nil)
+	})
+
+	// GET /config/public - The runtime settings the frontends adapt to, see config.Public
+	api.GET("config/public", func(c *gin.Context) {
+		ctx.I18n.JSON(c, e.SUCCESS, ctx.Config.Public(), nil)
+	})
+
 	// 注册服务相关路由
 	serviceGroup := api.Group("service")
 	service.RegisterRoutes(serviceGroup, ctx)
+
+	wsGroup := api.Group("ws")
+	ws.RegisterRoutes(wsGroup, ctx)
 }
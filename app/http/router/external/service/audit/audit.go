@@ -0,0 +1,24 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package audit
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/seakee/go-api/app/http"
+	"github.com/seakee/go-api/app/http/controller/audit"
+)
+
+// RegisterRoutes sets up the routes for audit-related endpoints.
+//
+// Parameters:
+//   - api: *gin.RouterGroup - The router group to add the audit routes to.
+//   - ctx: *http.Context - The application context containing necessary dependencies.
+func RegisterRoutes(api *gin.RouterGroup, ctx *http.Context) {
+	auditHandler := audit.NewHandler(ctx)
+	{
+		// GET /record - List audit records filtered by actor, action, target and time range (requires app authentication)
+		api.GET("record", auditHandler.List())
+	}
+}
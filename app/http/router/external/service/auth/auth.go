@@ -5,6 +5,8 @@
 package auth
 
 import (
+	"time"
+
 	"github.com/gin-gonic/gin"
 	"github.com/seakee/go-api/app/http"
 	"github.com/seakee/go-api/app/http/controller/auth"
@@ -23,9 +25,16 @@ func RegisterRoutes(api *gin.RouterGroup, ctx *http.Context) {
 	// Create a new auth handler
 	authHandler := auth.NewHandler(ctx)
 	{
-		// POST /app - Create a new app (requires app authentication)
-		api.POST("app", ctx.Middleware.CheckAppAuth(), authHandler.Create())
-		// POST /token - Get a new token
+		// POST /app - Create a new app (requires app authentication, retry-safe with an Idempotency-Key header;
+		// the response holds the secret, so a retry after success gets 409 instead of a replay)
+		api.POST("app", ctx.Middleware.IdempotencyWithoutReplay(), authHandler.Create())
+		// GET /app - List apps page by page, without their secrets (requires app authentication, cached for a minute)
+		api.GET("app", ctx.Middleware.Cache(time.Minute, nil, auth.AppCacheTag), authHandler.List())
+		// GET /app/:id - Get an app, without its secret (requires app authentication)
+		api.GET("app/:id", authHandler.Detail())
+		// POST /app/:id/secret - Rotate the secret of an app (requires app authentication)
+		api.POST("app/:id/secret", authHandler.RotateSecret())
+		// POST /token - Get a new token (public)
 		api.POST("token", authHandler.GetToken())
 	}
 }
@@ -3,6 +3,7 @@ package service
 import (
 	"github.com/gin-gonic/gin"
 	"github.com/seakee/go-api/app/http"
+	"github.com/seakee/go-api/app/http/router/external/service/audit"
 	"github.com/seakee/go-api/app/http/router/external/service/auth"
 )
 
@@ -13,4 +14,7 @@ func RegisterRoutes(api *gin.RouterGroup, ctx *http.Context) {
 
 	authAPI := api.Group("auth")
 	auth.RegisterRoutes(authAPI, ctx)
+
+	auditAPI := api.Group("audit")
+	audit.RegisterRoutes(auditAPI, ctx)
 }
@@ -0,0 +1,30 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ws
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/seakee/go-api/app/http"
+	"github.com/seakee/go-api/app/http/controller/ws"
+)
+
+// RegisterRoutes sets up the WebSocket endpoints; none are registered when WebSocket is disabled.
+//
+// Parameters:
+//   - api: *gin.RouterGroup - The router group to add the WebSocket routes to.
+//   - ctx: *http.Context - The application context containing necessary dependencies.
+func RegisterRoutes(api *gin.RouterGroup, ctx *http.Context) {
+	if ctx.WSHub == nil {
+		return
+	}
+
+	wsHandler := ws.NewHandler(ctx)
+	{
+		// GET /notifications - Stream notifications and alerts over WebSocket (requires app authentication;
+		// browsers, which can't set headers on the upgrade, pass the token in the "token" query parameter
+		// and must connect from one of websocket.allowed_origins)
+		api.GET("notifications", wsHandler.Notifications())
+	}
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package internal
+
+import (
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+
+	"github.com/gin-gonic/gin"
+	appHttp "github.com/seakee/go-api/app/http"
+	"github.com/seakee/go-api/docs"
+)
+
+// registerDocs serves the OpenAPI spec of the API, and Swagger UI to browse it, to
+// authenticated apps when Docs.Enable is set.
+//
+// The spec is always embedded in the binary. Swagger UI is served from the assets embedded in
+// docs.SwaggerUI, so it is only available in binaries built with the "swaggerui" tag; the
+// page and its assets are loaded by the browser, which authenticates with the token cookie
+// when Auth.TokenSources reads one.
+//
+// Parameters:
+//   - api: *gin.RouterGroup - The router group to add the documentation routes to.
+//   - ctx: *appHttp.Context - The application context containing necessary dependencies.
+func registerDocs(api *gin.RouterGroup, ctx *appHttp.Context) {
+	if !ctx.Config.Docs.Enable {
+		return
+	}
+
+	group := api.Group("docs", ctx.Middleware.CheckAppAuth())
+
+	// GET /docs/openapi.yaml - The OpenAPI spec (requires app authentication)
+	group.GET("openapi.yaml", func(c *gin.Context) {
+		c.Data(http.StatusOK, "application/yaml; charset=utf-8", docs.OpenAPI)
+	})
+
+	if docs.SwaggerUI == nil {
+		return
+	}
+
+	entries, err := fs.ReadDir(docs.SwaggerUI, ".")
+	if err != nil {
+		panic(err)
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		content, err := fs.ReadFile(docs.SwaggerUI, name)
+		if err != nil {
+			panic(err)
+		}
+
+		contentType := mime.TypeByExtension(path.Ext(name))
+		serve := func(c *gin.Context) {
+			c.Data(http.StatusOK, contentType, content)
+		}
+
+		if name == "index.html" {
+			// GET /docs - Swagger UI (requires app authentication)
+			group.GET("", serve)
+			continue
+		}
+
+		// GET /docs/<asset> - The Swagger UI assets (requires app authentication)
+		group.GET(name, serve)
+	}
+}
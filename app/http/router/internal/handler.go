@@ -1,12 +1,42 @@
 package internal
 
 import (
+	"net/http"
+
 	"github.com/gin-gonic/gin"
-	"github.com/seakee/go-api/app/http"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	appHttp "github.com/seakee/go-api/app/http"
+	"github.com/seakee/go-api/app/pkg/buildinfo"
+	"github.com/seakee/go-api/app/pkg/e"
 )
 
-func RegisterRoutes(api *gin.RouterGroup, ctx *http.Context) {
+func RegisterRoutes(api *gin.RouterGroup, ctx *appHttp.Context) {
 	api.GET("ping", func(c *gin.Context) {
 		ctx.I18n.JSON(c, 0, nil, nil)
 	})
+
+	api.GET("version", func(c *gin.Context) {
+		ctx.I18n.JSON(c, e.SUCCESS, buildinfo.Get(ctx.Config.System.Name, ctx.Config.System.Version), nil)
+	})
+
+	// GET /readyz - Answer 503 while a dependency, e.g. Kafka, is unreachable, so the node gets no traffic
+	api.GET("readyz", func(c *gin.Context) {
+		failures := ctx.Health.Run(ctx.Context(c))
+		if len(failures) == 0 {
+			c.JSON(http.StatusOK, gin.H{"status": "ready"})
+			return
+		}
+
+		checks := make(map[string]string, len(failures))
+		for name, err := range failures {
+			checks[name] = err.Error()
+		}
+
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "not ready", "checks": checks})
+	})
+
+	// GET /metrics - The Prometheus metrics, e.g. of the outbound HTTP requests
+	api.GET("metrics", gin.WrapH(promhttp.Handler()))
+
+	registerDocs(api, ctx)
 }
@@ -0,0 +1,49 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ws
+
+import (
+	"context"
+
+	"github.com/seakee/go-api/app/pkg/alert"
+	"github.com/seakee/go-api/app/pkg/larkcard"
+)
+
+// AlertMessageType is the type of the messages carrying alerts.
+const AlertMessageType = "alert"
+
+// AlertData is the payload of an alert message.
+type AlertData struct {
+	Level   alert.Level `json:"level"`
+	Type    string      `json:"type"`
+	Title   string      `json:"title"`
+	Content string      `json:"content"`
+}
+
+// alertChannel pushes alerts to the WebSocket connections.
+type alertChannel struct {
+	hub *Hub
+}
+
+// NewAlertChannel creates an alert.Channel pushing alerts to every connection of hub.
+//
+// Parameters:
+//   - hub: *Hub - The hub of the connections.
+//
+// Returns:
+//   - alert.Channel: The WebSocket channel, routed as "websocket" in the notify configuration.
+func NewAlertChannel(hub *Hub) alert.Channel {
+	return alertChannel{hub: hub}
+}
+
+// Send broadcasts card as an alert message.
+func (a alertChannel) Send(_ context.Context, level alert.Level, alertType string, card *larkcard.LarkCard) error {
+	_, err := a.hub.Broadcast(Message{
+		Type: AlertMessageType,
+		Data: AlertData{Level: level, Type: alertType, Title: card.Title(), Content: card.String()},
+	})
+
+	return err
+}
@@ -0,0 +1,329 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package ws pushes live data to clients over WebSocket connections.
+//
+// A Hub keeps the open connections by user, the authenticated app_id for the external
+// endpoints, so a message can be pushed to the connections of one user or to every connection.
+package ws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seakee/go-api/app/config"
+	"github.com/sk-pkg/logger"
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	defaultPingInterval = 30 * time.Second
+	defaultWriteTimeout = 10 * time.Second
+	defaultSendBuffer   = 64
+
+	// maxReceiveBytes caps the messages read from the clients, which only push data down.
+	maxReceiveBytes = 4 << 10
+)
+
+// Message is a message pushed to the clients, sent as a JSON text frame.
+type Message struct {
+	Type string      `json:"type"` // The kind of data, e.g. "alert"
+	Data interface{} `json:"data"` // The payload
+}
+
+// pingCodec sends empty ping frames; the clients answer with pongs.
+var pingCodec = websocket.Codec{
+	Marshal: func(interface{}) ([]byte, byte, error) {
+		return nil, websocket.PingFrame, nil
+	},
+}
+
+// Hub is the registry of the open WebSocket connections.
+type Hub struct {
+	pingInterval time.Duration
+	writeTimeout time.Duration
+	sendBuffer   int
+	origins      map[string]struct{} // Allowed browser origins, lowercased
+	logger       *logger.Manager
+
+	mu      sync.RWMutex
+	clients map[string]map[*client]struct{}
+	closed  bool
+}
+
+// client is an open connection. Messages are queued on send and written by its write loop.
+type client struct {
+	conn *websocket.Conn
+	send chan []byte
+	done chan struct{}
+	once sync.Once
+}
+
+// NewHub creates a Hub.
+//
+// Parameters:
+//   - cfg: config.WebSocket - The WebSocket configuration; zero values use 30s pings, a 10s write timeout and a 64 message buffer,
+//     and no allowed browser origin.
+//   - logger: *logger.Manager - The logger connection errors are reported to.
+//
+// Returns:
+//   - *Hub: A new Hub instance.
+func NewHub(cfg config.WebSocket, logger *logger.Manager) *Hub {
+	h := &Hub{
+		pingInterval: cfg.PingInterval * time.Second,
+		writeTimeout: cfg.WriteTimeout * time.Second,
+		sendBuffer:   cfg.SendBuffer,
+		origins:      make(map[string]struct{}, len(cfg.AllowedOrigins)),
+		logger:       logger,
+		clients:      make(map[string]map[*client]struct{}),
+	}
+
+	for _, origin := range cfg.AllowedOrigins {
+		h.origins[normalizeOrigin(origin)] = struct{}{}
+	}
+
+	if h.pingInterval <= 0 {
+		h.pingInterval = defaultPingInterval
+	}
+
+	if h.writeTimeout <= 0 {
+		h.writeTimeout = defaultWriteTimeout
+	}
+
+	if h.sendBuffer <= 0 {
+		h.sendBuffer = defaultSendBuffer
+	}
+
+	return h
+}
+
+// Serve upgrades the request to a WebSocket connection of userID and blocks until it is closed.
+//
+// The connection is pinged every ping interval and closed when a write fails, when the client
+// closes it, when it falls more than the send buffer behind, or when the hub is closed.
+// Messages from the client are discarded. Upgrades from browsers are rejected with 403 unless
+// their Origin is one of WebSocket.AllowedOrigins; callers must still authenticate the request,
+// e.g. through CheckAppAuth.
+//
+// Parameters:
+//   - c: *gin.Context - The Gin context of the upgrade request.
+//   - userID: string - The user the connection receives the messages of.
+//
+// Example:
+//
+//	api.GET("notifications", ctx.Middleware.CheckAppAuth(), func(c *gin.Context) {
+//	    hub.Serve(c, c.GetString("app_id"))
+//	})
+func (h *Hub) Serve(c *gin.Context, userID string) {
+	h.mu.RLock()
+	closed := h.closed
+	h.mu.RUnlock()
+
+	if closed {
+		c.AbortWithStatus(http.StatusServiceUnavailable)
+		return
+	}
+
+	ctx := context.WithValue(context.Background(), logger.TraceIDKey, c.GetString("trace_id"))
+
+	websocket.Server{
+		Handshake: func(_ *websocket.Config, r *http.Request) error { return h.checkOrigin(r) },
+		Handler: func(conn *websocket.Conn) {
+			h.handle(ctx, conn, userID)
+		},
+	}.ServeHTTP(c.Writer, c.Request)
+
+	c.Abort()
+}
+
+// checkOrigin accepts requests without an Origin header, sent by non-browser clients, and
+// those from an allowed origin.
+func (h *Hub) checkOrigin(r *http.Request) error {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return nil
+	}
+
+	if _, ok := h.origins[normalizeOrigin(origin)]; !ok {
+		return fmt.Errorf("origin %s is not allowed", origin)
+	}
+
+	return nil
+}
+
+// normalizeOrigin lowercases origin and drops a trailing slash, so configured origins match
+// the Origin headers of browsers.
+func normalizeOrigin(origin string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(origin)), "/")
+}
+
+// Send pushes msg to the connections of userID.
+//
+// Parameters:
+//   - userID: string - The user.
+//   - msg: Message - The message.
+//
+// Returns:
+//   - int: The number of connections the message was queued on.
+//   - error: An error if msg can't be encoded.
+func (h *Hub) Send(userID string, msg Message) (int, error) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return 0, fmt.Errorf("marshal message failed: %w", err)
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return h.queue(h.clients[userID], data), nil
+}
+
+// Broadcast pushes msg to every connection.
+//
+// Parameters:
+//   - msg: Message - The message.
+//
+// Returns:
+//   - int: The number of connections the message was queued on.
+//   - error: An error if msg can't be encoded.
+func (h *Hub) Broadcast(msg Message) (int, error) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return 0, fmt.Errorf("marshal message failed: %w", err)
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	sent := 0
+	for _, clients := range h.clients {
+		sent += h.queue(clients, data)
+	}
+
+	return sent, nil
+}
+
+// Close closes every connection and rejects new ones. It is registered on the HTTP server
+// shutdown, since http.Server.Shutdown doesn't track the hijacked WebSocket connections.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.closed = true
+	for _, clients := range h.clients {
+		for cl := range clients {
+			cl.close()
+		}
+	}
+}
+
+// handle runs an upgraded connection until it is closed.
+func (h *Hub) handle(ctx context.Context, conn *websocket.Conn, userID string) {
+	// Clear the deadlines the HTTP server set on the connection before it was hijacked
+	_ = conn.SetDeadline(time.Time{})
+	conn.MaxPayloadBytes = maxReceiveBytes
+
+	cl := &client{conn: conn, send: make(chan []byte, h.sendBuffer), done: make(chan struct{})}
+	if !h.register(userID, cl) {
+		return
+	}
+	defer h.unregister(userID, cl)
+
+	go h.writeLoop(ctx, cl)
+
+	var discard []byte
+	for {
+		if err := websocket.Message.Receive(conn, &discard); err != nil {
+			cl.close()
+			return
+		}
+	}
+}
+
+// writeLoop writes the queued messages and the pings of cl, and closes the connection when cl is closed.
+func (h *Hub) writeLoop(ctx context.Context, cl *client) {
+	ticker := time.NewTicker(h.pingInterval)
+	defer ticker.Stop()
+	defer cl.conn.Close()
+
+	for {
+		var err error
+
+		select {
+		case <-cl.done:
+			return
+		case data := <-cl.send:
+			_ = cl.conn.SetWriteDeadline(time.Now().Add(h.writeTimeout))
+			err = websocket.Message.Send(cl.conn, string(data))
+		case <-ticker.C:
+			_ = cl.conn.SetWriteDeadline(time.Now().Add(h.writeTimeout))
+			err = pingCodec.Send(cl.conn, nil)
+		}
+
+		if err != nil {
+			h.logger.Warn(ctx, "websocket write failed", zap.Error(err))
+			cl.close()
+			return
+		}
+	}
+}
+
+// register adds cl to the connections of userID; it reports false once the hub is closed.
+func (h *Hub) register(userID string, cl *client) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.closed {
+		return false
+	}
+
+	if h.clients[userID] == nil {
+		h.clients[userID] = make(map[*client]struct{})
+	}
+	h.clients[userID][cl] = struct{}{}
+
+	return true
+}
+
+// unregister removes cl from the connections of userID.
+func (h *Hub) unregister(userID string, cl *client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	delete(h.clients[userID], cl)
+	if len(h.clients[userID]) == 0 {
+		delete(h.clients, userID)
+	}
+}
+
+// queue queues data on clients and returns how many accepted it. A client whose buffer is full
+// is closed rather than blocking the others; the caller holds h.mu.
+func (h *Hub) queue(clients map[*client]struct{}, data []byte) int {
+	sent := 0
+	for cl := range clients {
+		select {
+		case <-cl.done:
+		case cl.send <- data:
+			sent++
+		default:
+			cl.close()
+		}
+	}
+
+	return sent
+}
+
+// close signals the write loop to close the connection; it is safe to call more than once.
+func (cl *client) close() {
+	cl.once.Do(func() {
+		close(cl.done)
+	})
+}
@@ -0,0 +1,41 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ws
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/seakee/go-api/app/config"
+)
+
+func TestHubCheckOrigin(t *testing.T) {
+	h := NewHub(config.WebSocket{AllowedOrigins: []string{"https://Admin.example.com/"}}, nil)
+
+	tests := []struct {
+		name    string
+		origin  string
+		wantErr bool
+	}{
+		{name: "no origin", origin: ""},
+		{name: "allowed origin", origin: "https://admin.example.com"},
+		{name: "other origin", origin: "https://evil.example.com", wantErr: true},
+		{name: "other scheme", origin: "http://admin.example.com", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/go-api/external/ws/notifications", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+
+			if err := h.checkOrigin(r); (err != nil) != tt.wantErr {
+				t.Errorf("checkOrigin(%q) error = %v, wantErr %v", tt.origin, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@
 package job
 
 import (
+	"github.com/go-resty/resty/v2"
 	"github.com/seakee/go-api/app/job/monitor"
 	"github.com/seakee/go-api/app/pkg/schedule"
 	"github.com/sk-pkg/logger"
@@ -22,13 +23,14 @@ import (
 //   - redis: A map of Redis managers, keyed by their names.
 //   - db: A map of GORM database connections, keyed by their names.
 //   - notify: A pointer to the Notify manager for Notify-related operations.
+//   - httpClient: A pointer to the shared resty.Client for outbound HTTP calls.
 //   - s: A pointer to the schedule.Schedule instance for job scheduling.
 //
 // This function initializes various monitoring jobs and adds them to the scheduler.
 // Currently, it sets up an IP monitor job that runs every 5 minutes without overlapping.
-func Register(logger *logger.Manager, redis map[string]*redis.Manager, db map[string]*gorm.DB, notify *notify.Manager, s *schedule.Schedule) {
+func Register(logger *logger.Manager, redis map[string]*redis.Manager, db map[string]*gorm.DB, notify *notify.Manager, httpClient *resty.Client, s *schedule.Schedule) {
 	// Initialize the IP monitor
-	ipMonitor := monitor.NewIpMonitor(logger, redis["go-api"])
+	ipMonitor := monitor.NewIpMonitor(logger, redis["go-api"], httpClient)
 
 	// Add the IP monitor job to the scheduler
 	// It will run every 5 minutes without overlapping with previous executions
@@ -34,6 +34,7 @@ type ipHandler struct {
 	error  chan error
 	logger *logger.Manager
 	redis  *redis.Manager
+	client *resty.Client
 	lastIp string
 }
 
@@ -61,11 +62,8 @@ func (ih *ipHandler) Exec(ctx context.Context) {
 	// Set the last known IP from Redis
 	ih.setLastIp()
 
-	// Create a new HTTP client
-	client := resty.New()
-
 	// Make a GET request to check the current IP
-	res, err := client.R().Get(CheckCNIpApi)
+	res, err := ih.client.R().SetContext(ctx).Get(CheckCNIpApi)
 	if err == nil && res != nil && res.StatusCode() == 200 {
 		// Trim any newline characters from the response
 		currentIp := strings.TrimRight(string(res.Body()), "\n")
@@ -103,15 +101,17 @@ func (ih *ipHandler) Done() <-chan struct{} {
 // Parameters:
 //   - logger: A pointer to the logger.Manager for logging purposes.
 //   - redis: A pointer to the redis.Manager for Redis operations.
+//   - client: A pointer to the shared resty.Client used to query the IP APIs.
 //
 // Returns:
 //   - schedule.HandlerFunc: A handler function that can be scheduled for execution.
-func NewIpMonitor(logger *logger.Manager, redis *redis.Manager) schedule.HandlerFunc {
+func NewIpMonitor(logger *logger.Manager, redis *redis.Manager, client *resty.Client) schedule.HandlerFunc {
 	return &ipHandler{
 		done:   make(chan struct{}),
 		error:  make(chan error),
 		logger: logger,
 		lastIp: "",
 		redis:  redis,
+		client: client,
 	}
 }
@@ -0,0 +1,166 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package audit provides the models of the audit trail.
+package audit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/qiniu/qmgo"
+	"github.com/qiniu/qmgo/options"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Record represents one audited change.
+type Record struct {
+	ID        primitive.ObjectID     `json:"id" bson:"_id,omitempty"`
+	Actor     string                 `bson:"actor" json:"actor"`                       // Who made the change, e.g. the app_id of the caller
+	Action    string                 `bson:"action" json:"action"`                     // What was done, e.g. "app.rotate_secret"
+	Target    string                 `bson:"target" json:"target"`                     // What was changed, e.g. "app:go-api-abcdefgh"
+	Before    map[string]interface{} `bson:"before,omitempty" json:"before,omitempty"` // Summary of the state before the change
+	After     map[string]interface{} `bson:"after,omitempty" json:"after,omitempty"`   // Summary of the state after the change
+	TraceID   string                 `bson:"trace_id" json:"trace_id"`                 // Trace ID of the request making the change
+	CreatedAt time.Time              `bson:"created_at" json:"created_at"`             // Time of the change
+}
+
+// Filter defines the conditions of an audit query; zero values don't filter.
+type Filter struct {
+	Actor     string    // Exact actor
+	Action    string    // Exact action
+	Target    string    // Exact target
+	StartTime time.Time // Inclusive lower bound of CreatedAt
+	EndTime   time.Time // Exclusive upper bound of CreatedAt
+}
+
+// CollectionName returns the name of the MongoDB collection for Record.
+func (r *Record) CollectionName() string {
+	return "audit_record"
+}
+
+// Create inserts the record into the database, setting CreatedAt if it is zero.
+//
+// Parameters:
+//   - ctx: A context.Context for the database operation.
+//   - db: A pointer to the qmgo.Database to perform the operation on.
+//
+// Returns:
+//   - string: The hexadecimal representation of the inserted document's ObjectID.
+//   - error: An error if the operation fails, or nil on success.
+func (r *Record) Create(ctx context.Context, db *qmgo.Database) (string, error) {
+	if r.CreatedAt.IsZero() {
+		r.CreatedAt = time.Now()
+	}
+
+	result, err := db.Collection(r.CollectionName()).InsertOne(ctx, r)
+	if err != nil {
+		return "", fmt.Errorf("create failed: %w", err)
+	}
+
+	objectID, ok := result.InsertedID.(primitive.ObjectID)
+	if !ok {
+		return "", fmt.Errorf("create failed: unable to convert inserted ID to ObjectID")
+	}
+
+	return objectID.Hex(), nil
+}
+
+// PaginationWithTotal retrieves a page of the records matching filter, newest first, with the total count.
+//
+// Parameters:
+//   - ctx: A context.Context for the database operation.
+//   - db: A pointer to the qmgo.Database to perform the operation on.
+//   - filter: The query conditions.
+//   - page: The page number (1-based).
+//   - size: The number of records per page.
+//
+// Returns:
+//   - []Record: The records of the page.
+//   - int64: The total count of matching records.
+//   - error: An error if the operation fails, or nil on success.
+//
+// Example:
+//
+//	records, total, err := (&Record{}).PaginationWithTotal(ctx, db, Filter{Action: "app.rotate_secret"}, 1, 20)
+func (r *Record) PaginationWithTotal(ctx context.Context, db *qmgo.Database, filter Filter, page, size int) ([]Record, int64, error) {
+	query := filter.query()
+	collection := db.Collection(r.CollectionName())
+
+	total, err := collection.Find(ctx, query).Count()
+	if err != nil {
+		return nil, 0, fmt.Errorf("count failed: %w", err)
+	}
+
+	records := make([]Record, 0)
+	if total == 0 {
+		return records, 0, nil
+	}
+
+	err = collection.Find(ctx, query).Sort("-created_at").Skip(int64((page - 1) * size)).Limit(int64(size)).All(&records)
+	if err != nil {
+		return nil, 0, fmt.Errorf("find with pagination failed: %w", err)
+	}
+
+	return records, total, nil
+}
+
+// EnsureIndexes creates the indexes used by the audit queries.
+//
+// Each filterable field gets a compound index with created_at, so every query is sorted by an index.
+// Creating an existing index is a no-op, so it is safe to call on every startup.
+//
+// Parameters:
+//   - ctx: A context.Context for the database operation.
+//   - db: A pointer to the qmgo.Database to perform the operation on.
+//
+// Returns:
+//   - error: An error if the operation fails, or nil on success.
+func (r *Record) EnsureIndexes(ctx context.Context, db *qmgo.Database) error {
+	err := db.Collection(r.CollectionName()).CreateIndexes(ctx, []options.IndexModel{
+		{Key: []string{"-created_at"}},
+		{Key: []string{"actor", "-created_at"}},
+		{Key: []string{"target", "-created_at"}},
+		{Key: []string{"action", "-created_at"}},
+	})
+	if err != nil {
+		return fmt.Errorf("create indexes failed: %w", err)
+	}
+
+	return nil
+}
+
+// query builds the BSON query of the filter.
+func (f Filter) query() bson.M {
+	query := bson.M{}
+
+	if f.Actor != "" {
+		query["actor"] = f.Actor
+	}
+
+	if f.Action != "" {
+		query["action"] = f.Action
+	}
+
+	if f.Target != "" {
+		query["target"] = f.Target
+	}
+
+	createdAt := bson.M{}
+	if !f.StartTime.IsZero() {
+		createdAt["$gte"] = f.StartTime
+	}
+
+	if !f.EndTime.IsZero() {
+		createdAt["$lt"] = f.EndTime
+	}
+
+	if len(createdAt) > 0 {
+		query["created_at"] = createdAt
+	}
+
+	return query
+}
@@ -8,19 +8,22 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
+	"github.com/seakee/go-api/app/pkg/query"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type App struct {
 	gorm.Model
 
-	AppID       string `gorm:"column:app_id" json:"app_id"`             // Application ID
-	AppName     string `gorm:"column:app_name" json:"app_name"`         // Application Name
-	AppSecret   string `gorm:"column:app_secret" json:"app_secret"`     // Application Secret Key
-	RedirectUri string `gorm:"column:redirect_uri" json:"redirect_uri"` // Redirect URI after authorization
-	Description string `gorm:"column:description" json:"description"`   // Description
-	Status      int8   `gorm:"column:status" json:"status"`             // 1: Active; 2: Disabled
+	AppID       string `gorm:"column:app_id;type:varchar(30);not null" json:"app_id"`          // Application ID
+	AppName     string `gorm:"column:app_name;type:varchar(50)" json:"app_name"`               // Application Name
+	AppSecret   string `gorm:"column:app_secret;type:varchar(256);not null" json:"app_secret"` // Application Secret Key
+	RedirectUri string `gorm:"column:redirect_uri;type:varchar(500)" json:"redirect_uri"`      // Redirect URI after authorization
+	Description string `gorm:"column:description;type:text" json:"description"`                // Description
+	Status      int8   `gorm:"column:status;type:tinyint(1);not null;default:0" json:"status"` // 1: Active; 2: Disabled
 }
 
 // TableName specifies the table name for the App model.
@@ -109,6 +112,32 @@ func (a *App) Delete(ctx context.Context, db *gorm.DB) error {
 	return db.WithContext(ctx).Delete(a).Error
 }
 
+// DeleteByIDs soft-deletes the apps with the given IDs in a single statement.
+//
+// An empty ids slice deletes nothing and doesn't query the database.
+//
+// Parameters:
+//   - ctx: context.Context for managing request-scoped values, cancellation signals, and deadlines.
+//   - db: *gorm.DB database connection.
+//   - ids: IDs of the apps to delete.
+//
+// Returns:
+//   - int64: number of apps deleted; IDs that don't exist or are already deleted aren't counted.
+//   - error: error if the delete operation fails, otherwise nil.
+func (a *App) DeleteByIDs(ctx context.Context, db *gorm.DB, ids []uint) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	// Perform the database delete operation with context.
+	result := db.WithContext(ctx).Where("id IN ?", ids).Delete(&App{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("delete by ids failed: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
 // Updates applies the specified updates to the app in the database.
 //
 // Parameters:
@@ -165,6 +194,48 @@ func (a *App) ListByArgs(ctx context.Context, db *gorm.DB, query interface{}, ar
 	return apps, nil
 }
 
+// ListByIDs retrieves the apps with the given IDs from the database.
+//
+// The result follows the order of ids; IDs that don't exist are skipped.
+// An empty ids slice returns an empty result without querying the database.
+//
+// Parameters:
+//   - ctx: context.Context for managing request-scoped values, cancellation signals, and deadlines.
+//   - db: *gorm.DB database connection.
+//   - ids: IDs of the apps to retrieve.
+//
+// Returns:
+//   - []App: slice of retrieved apps, ordered as ids.
+//   - error: error if the query fails, otherwise nil.
+func (a *App) ListByIDs(ctx context.Context, db *gorm.DB, ids []uint) ([]App, error) {
+	if len(ids) == 0 {
+		return []App{}, nil
+	}
+
+	var apps []App
+
+	// Perform the database query with context.
+	if err := db.WithContext(ctx).Where("id IN ?", ids).Find(&apps).Error; err != nil {
+		return nil, fmt.Errorf("list by ids failed: %w", err)
+	}
+
+	// Restore the order of the input ids.
+	appMap := make(map[uint]App, len(apps))
+	for _, app := range apps {
+		appMap[app.ID] = app
+	}
+
+	ordered := make([]App, 0, len(apps))
+	for _, id := range ids {
+		if app, ok := appMap[id]; ok {
+			ordered = append(ordered, app)
+			delete(appMap, id)
+		}
+	}
+
+	return ordered, nil
+}
+
 // CountByArgs counts the number of apps matching the specified query and arguments in the database.
 //
 // Parameters:
@@ -221,6 +292,70 @@ func (a *App) BatchInsert(ctx context.Context, db *gorm.DB, apps []App) error {
 	return db.WithContext(ctx).Create(&apps).Error
 }
 
+// Upsert inserts the app, or resolves a conflict on the given columns when a matching row already exists.
+//
+// Parameters:
+//   - ctx: context.Context for managing request-scoped values, cancellation signals, and deadlines.
+//   - db: *gorm.DB database connection.
+//   - conflictColumns: columns forming the unique key that detects the conflict (e.g., []string{"app_id"}).
+//   - updateColumns: columns to overwrite on conflict; if empty, the conflicting row is left untouched (DO NOTHING).
+//
+// Returns:
+//   - error: error if the upsert operation fails, otherwise nil.
+func (a *App) Upsert(ctx context.Context, db *gorm.DB, conflictColumns []string, updateColumns []string) error {
+	// Perform the database upsert operation with context.
+	if err := db.WithContext(ctx).Clauses(a.onConflict(conflictColumns, updateColumns)).Create(a).Error; err != nil {
+		return fmt.Errorf("upsert failed: %w", err)
+	}
+
+	return nil
+}
+
+// BatchUpsert inserts multiple apps in a single batch operation, resolving conflicts on the given columns.
+//
+// Parameters:
+//   - ctx: context.Context for managing request-scoped values, cancellation signals, and deadlines.
+//   - db: *gorm.DB database connection.
+//   - apps: slice of App instances to be inserted.
+//   - conflictColumns: columns forming the unique key that detects the conflict (e.g., []string{"app_id"}).
+//   - updateColumns: columns to overwrite on conflict; if empty, conflicting rows are skipped (DO NOTHING).
+//
+// Returns:
+//   - error: error if the batch upsert operation fails, otherwise nil.
+func (a *App) BatchUpsert(ctx context.Context, db *gorm.DB, apps []App, conflictColumns []string, updateColumns []string) error {
+	if len(apps) == 0 {
+		return nil
+	}
+
+	// Perform the database batch upsert operation with context.
+	if err := db.WithContext(ctx).Clauses(a.onConflict(conflictColumns, updateColumns)).Create(&apps).Error; err != nil {
+		return fmt.Errorf("batch upsert failed: %w", err)
+	}
+
+	return nil
+}
+
+// onConflict builds the ON CONFLICT / ON DUPLICATE KEY clause used by Upsert and BatchUpsert.
+//
+// Parameters:
+//   - conflictColumns: columns forming the unique key that detects the conflict.
+//   - updateColumns: columns to overwrite on conflict; if empty, the clause becomes DO NOTHING.
+//
+// Returns:
+//   - clause.OnConflict: the conflict resolution clause.
+func (a *App) onConflict(conflictColumns []string, updateColumns []string) clause.OnConflict {
+	columns := make([]clause.Column, len(conflictColumns))
+	for i, name := range conflictColumns {
+		columns[i] = clause.Column{Name: name}
+	}
+
+	if len(updateColumns) == 0 {
+		return clause.OnConflict{Columns: columns, DoNothing: true}
+	}
+
+	return clause.OnConflict{Columns: columns, DoUpdates: clause.AssignmentColumns(updateColumns)}
+}
+
 // FindWithPagination retrieves apps matching the criteria from the database with pagination support.
 //
 // Parameters:
@@ -244,6 +379,97 @@ func (a *App) FindWithPagination(ctx context.Context, db *gorm.DB, page, size in
 	return apps, nil
 }
 
+// Paginate retrieves apps matching the criteria with pagination support, along with their total count.
+//
+// The count and the page are built from the same query, so the total always matches the
+// conditions of the page.
+//
+// Parameters:
+//   - ctx: context.Context for managing request-scoped values, cancellation signals, and deadlines.
+//   - db: *gorm.DB database connection.
+//   - page: page number for pagination (1-based).
+//   - size: number of apps per page.
+//
+// Returns:
+//   - []App: slice of retrieved apps.
+//   - int64: total count of matching apps.
+//   - error: error if the query fails, otherwise nil.
+func (a *App) Paginate(ctx context.Context, db *gorm.DB, page, size int) ([]App, int64, error) {
+	var (
+		apps  []App
+		total int64
+	)
+
+	query := db.WithContext(ctx).Model(&App{}).Where(a)
+
+	// Count the matching apps before applying pagination.
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("paginate count failed: %w", err)
+	}
+
+	if total == 0 {
+		return []App{}, 0, nil
+	}
+
+	// Perform the database query, applying offset and limit for pagination.
+	if err := query.Offset((page - 1) * size).Limit(size).Find(&apps).Error; err != nil {
+		return nil, 0, fmt.Errorf("paginate failed: %w", err)
+	}
+
+	return apps, total, nil
+}
+
+// Search retrieves apps whose given fields fuzzy-match the keyword, with pagination support.
+//
+// The keyword is matched with LIKE '%keyword%' against each field, combined with OR,
+// on top of the non-zero fields of the receiver. Wildcards in the keyword are escaped,
+// so '%' and '_' are matched literally. An empty keyword or fields list only applies
+// the receiver's conditions.
+//
+// Parameters:
+//   - ctx: context.Context for managing request-scoped values, cancellation signals, and deadlines.
+//   - db: *gorm.DB database connection.
+//   - fields: column names to search; these are interpolated into SQL and must never come from user input.
+//   - keyword: the search keyword.
+//   - page: page number for pagination (1-based).
+//   - size: number of apps per page.
+//
+// Returns:
+//   - []App: slice of retrieved apps.
+//   - int64: total count of matching apps.
+//   - error: error if the query fails, otherwise nil.
+func (a *App) Search(ctx context.Context, db *gorm.DB, fields []string, keyword string, page, size int) ([]App, int64, error) {
+	var (
+		apps  []App
+		total int64
+	)
+
+	tx := db.WithContext(ctx).Model(&App{}).Where(a)
+	if keyword != "" && len(fields) > 0 {
+		conditions := make([]string, len(fields))
+		args := make([]interface{}, len(fields))
+		pattern := "%" + query.EscapeLike(keyword) + "%"
+		for i, field := range fields {
+			conditions[i] = field + " LIKE ?"
+			args[i] = pattern
+		}
+
+		tx = tx.Where("("+strings.Join(conditions, " OR ")+")", args...)
+	}
+
+	// Count the matching apps before applying pagination.
+	if err := tx.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("search count failed: %w", err)
+	}
+
+	// Perform the database query, applying offset and limit for pagination.
+	if err := tx.Offset((page - 1) * size).Limit(size).Find(&apps).Error; err != nil {
+		return nil, 0, fmt.Errorf("search failed: %w", err)
+	}
+
+	return apps, total, nil
+}
+
 // FindWithSort retrieves apps matching the criteria from the database with sorting support.
 //
 // Parameters:
@@ -7,12 +7,22 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"reflect"
 
 	"github.com/qiniu/qmgo"
+	"github.com/qiniu/qmgo/options"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
+	mgoptions "go.mongodb.org/mongo-driver/mongo/options"
+)
+
+var (
+	// ErrNotFound is returned when no MgoApp document matches the given id.
+	ErrNotFound = errors.New("app not found")
+	// ErrInvalidID is returned when the given id is not a valid hex ObjectID.
+	ErrInvalidID = errors.New("invalid app id")
 )
 
 // MgoApp represents an application in the authentication system.
@@ -223,6 +233,45 @@ func (a *MgoApp) Updates(ctx context.Context, db *qmgo.Database, updates bson.M)
 	return nil
 }
 
+// UpdateByID modifies the MgoApp document with the given id with the provided updates.
+//
+// Unlike Updates, it targets "_id" directly and reports a missing document, so callers
+// don't need a racy get-then-update to tell "not found" apart from success.
+//
+// Parameters:
+//   - ctx: A context.Context for the database operation.
+//   - db: A pointer to the qmgo.Database to perform the operation on.
+//   - id: The hexadecimal ObjectID of the document to update.
+//   - updates: A bson.M containing the fields to update and their new values.
+//
+// Returns:
+//   - error: ErrInvalidID if id is not a valid ObjectID, ErrNotFound if no document has that id,
+//     another error if the operation fails, or nil on success.
+//
+// Example:
+//
+//	err := (&MgoApp{}).UpdateByID(ctx, db, "66b1f1c2e4b0a1b2c3d4e5f6", bson.M{"status": 2})
+//	if errors.Is(err, ErrNotFound) {
+//	    log.Printf("App does not exist")
+//	    return
+//	}
+func (a *MgoApp) UpdateByID(ctx context.Context, db *qmgo.Database, id string, updates bson.M) error {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return fmt.Errorf("update by id failed: %w", ErrInvalidID)
+	}
+
+	err = db.Collection(a.CollectionName()).UpdateId(ctx, objectID, bson.M{"$set": updates})
+	if errors.Is(err, qmgo.ErrNoSuchDocuments) {
+		return fmt.Errorf("update by id failed: %w", ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("update by id failed: %w", err)
+	}
+
+	return nil
+}
+
 // List retrieves all MgoApp documents that match the query.
 //
 // Parameters:
@@ -337,6 +386,51 @@ func (a *MgoApp) Pagination(ctx context.Context, db *qmgo.Database, page, size i
 	return apps, nil
 }
 
+// PaginationWithTotal retrieves a paginated list of MgoApp documents that match the query,
+// together with the total number of matching documents.
+//
+// Parameters:
+//   - ctx: A context.Context for the database operation.
+//   - db: A pointer to the qmgo.Database to perform the operation on.
+//   - page: The page number (1-based) to retrieve.
+//   - size: The number of documents per page.
+//
+// Returns:
+//   - []MgoApp: A slice of MgoApp structs containing the matching documents for the specified page.
+//   - int64: The total count of matching documents.
+//   - error: An error if the operation fails, or nil on success.
+//
+// Example:
+//
+//	app := &MgoApp{Status: 1}
+//	results, total, err := app.PaginationWithTotal(ctx, db, 1, 10)
+//	if err != nil {
+//	    log.Printf("Error retrieving paginated apps: %v", err)
+//	    return
+//	}
+//	fmt.Printf("Found %d of %d apps\n", len(results), total)
+func (a *MgoApp) PaginationWithTotal(ctx context.Context, db *qmgo.Database, page, size int) ([]MgoApp, int64, error) {
+	query := a.buildQuery()
+	collection := db.Collection(a.CollectionName())
+
+	total, err := collection.Find(ctx, query).Count()
+	if err != nil {
+		return nil, 0, fmt.Errorf("count failed: %w", err)
+	}
+
+	apps := make([]MgoApp, 0)
+	if total == 0 {
+		return apps, 0, nil
+	}
+
+	err = collection.Find(ctx, query).Skip(int64((page - 1) * size)).Limit(int64(size)).All(&apps)
+	if err != nil {
+		return nil, 0, fmt.Errorf("find with pagination failed: %w", err)
+	}
+
+	return apps, total, nil
+}
+
 // FindWithSort retrieves all MgoApp documents that match the query, sorted according to the provided sort string.
 //
 // Parameters:
@@ -396,3 +490,97 @@ func (a *MgoApp) Count(ctx context.Context, db *qmgo.Database) (int64, error) {
 
 	return count, nil
 }
+
+// FindByAppID retrieves the MgoApp document with the given app ID.
+//
+// Unlike First, the query matches "app_id" only, so it is served by the unique index
+// created by EnsureIndexes instead of scanning the collection.
+//
+// Parameters:
+//   - ctx: A context.Context for the database operation.
+//   - db: A pointer to the qmgo.Database to perform the operation on.
+//   - appID: The app ID to look up.
+//
+// Returns:
+//   - *MgoApp: A pointer to the retrieved MgoApp, or nil if not found.
+//   - error: An error if the operation fails, or nil on success.
+//
+// Example:
+//
+//	result, err := (&MgoApp{}).FindByAppID(ctx, db, "example_id")
+//	if err != nil {
+//	    log.Printf("Error finding app: %v", err)
+//	    return
+//	}
+func (a *MgoApp) FindByAppID(ctx context.Context, db *qmgo.Database, appID string) (*MgoApp, error) {
+	var app MgoApp
+
+	err := db.Collection(a.CollectionName()).Find(ctx, bson.M{"app_id": appID}).One(&app)
+	if err != nil {
+		return nil, fmt.Errorf("find by app id failed: %w", err)
+	}
+
+	return &app, nil
+}
+
+// FindByStatus retrieves a paginated list of MgoApp documents with the given status, newest first.
+//
+// The query is served by the {status: 1, _id: -1} index created by EnsureIndexes.
+//
+// Parameters:
+//   - ctx: A context.Context for the database operation.
+//   - db: A pointer to the qmgo.Database to perform the operation on.
+//   - status: The status to match.
+//   - page: The page number (1-based) to retrieve.
+//   - size: The number of documents per page.
+//
+// Returns:
+//   - []MgoApp: A slice of MgoApp structs containing the matching documents for the specified page.
+//   - error: An error if the operation fails, or nil on success.
+//
+// Example:
+//
+//	results, err := (&MgoApp{}).FindByStatus(ctx, db, 1, 1, 20)
+//	if err != nil {
+//	    log.Printf("Error finding apps: %v", err)
+//	    return
+//	}
+func (a *MgoApp) FindByStatus(ctx context.Context, db *qmgo.Database, status uint8, page, size int) ([]MgoApp, error) {
+	var apps []MgoApp
+
+	err := db.Collection(a.CollectionName()).Find(ctx, bson.M{"status": status}).Sort("-_id").Skip(int64((page - 1) * size)).Limit(int64(size)).All(&apps)
+	if err != nil {
+		return nil, fmt.Errorf("find by status failed: %w", err)
+	}
+
+	return apps, nil
+}
+
+// EnsureIndexes creates the indexes used by FindByAppID and FindByStatus.
+//
+// It creates a unique index on "app_id" and a compound index on {status: 1, _id: -1}.
+// Creating an existing index is a no-op, so it is safe to call on every startup.
+//
+// Parameters:
+//   - ctx: A context.Context for the database operation.
+//   - db: A pointer to the qmgo.Database to perform the operation on.
+//
+// Returns:
+//   - error: An error if the operation fails, or nil on success.
+//
+// Example:
+//
+//	if err := (&MgoApp{}).EnsureIndexes(ctx, db); err != nil {
+//	    log.Printf("Error creating indexes: %v", err)
+//	}
+func (a *MgoApp) EnsureIndexes(ctx context.Context, db *qmgo.Database) error {
+	err := db.Collection(a.CollectionName()).CreateIndexes(ctx, []options.IndexModel{
+		{Key: []string{"app_id"}, IndexOptions: mgoptions.Index().SetUnique(true)},
+		{Key: []string{"status", "-_id"}},
+	})
+	if err != nil {
+		return fmt.Errorf("create indexes failed: %w", err)
+	}
+
+	return nil
+}
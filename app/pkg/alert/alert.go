@@ -0,0 +1,203 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package alert provides a single entry point for operator alerts, routing each alert
+// by level to the configured notification channels.
+package alert
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/seakee/go-api/app/config"
+	"github.com/seakee/go-api/app/pkg/larkcard"
+)
+
+// Level is the severity of an alert.
+type Level string
+
+// Alert levels, from the least to the most severe.
+const (
+	InfoLevel    Level = "info"
+	SuccessLevel Level = "success"
+	WarnLevel    Level = "warn"
+	ErrorLevel   Level = "error"
+)
+
+// severity orders the levels for the minimum level threshold.
+var severity = map[Level]int{
+	InfoLevel:    0,
+	SuccessLevel: 1,
+	WarnLevel:    2,
+	ErrorLevel:   3,
+}
+
+// levelColor is the Lark card header color of each level.
+var levelColor = map[Level]larkcard.Color{
+	InfoLevel:    larkcard.Blue,
+	SuccessLevel: larkcard.Green,
+	WarnLevel:    larkcard.Yellow,
+	ErrorLevel:   larkcard.Red,
+}
+
+// ErrUnknownLevel is returned for a level other than info, success, warn and error.
+var ErrUnknownLevel = errors.New("unknown alert level")
+
+// Channel delivers alerts to one notification channel, e.g. Lark, email or a webhook.
+type Channel interface {
+	// Send delivers card as an alert of the given level and type.
+	// Channels without rich messages send card.String().
+	Send(ctx context.Context, level Level, alertType string, card *larkcard.LarkCard) error
+}
+
+// Alerter routes alerts by level to notification channels.
+type Alerter struct {
+	minLevel Level
+	channels map[string]Channel
+	routes   map[Level][]string
+	fallback string
+}
+
+// New creates an Alerter from the notify configuration.
+//
+// Alerts below cfg.DefaultLevel are dropped. An alert is sent to the channels listed for its
+// level in cfg.Routes, or to cfg.DefaultChannel when its level has no route. Channel names
+// missing from channels, e.g. disabled ones, are skipped.
+//
+// Parameters:
+//   - cfg: config.Notify - The notify configuration.
+//   - channels: map[string]Channel - The available channels by name, e.g. {"lark": alert.NewLarkChannel(sender)}.
+//
+// Returns:
+//   - *Alerter: A new Alerter instance.
+//   - error: ErrUnknownLevel if cfg uses an unknown level.
+//
+// Example:
+//
+//	alerter, err := alert.New(cfg.Notify, map[string]alert.Channel{
+//	    "lark": alert.NewLarkChannel(larkcard.NewSender(manager.Lark, cfg.Notify.Lark.Cards)),
+//	})
+func New(cfg config.Notify, channels map[string]Channel) (*Alerter, error) {
+	a := &Alerter{
+		minLevel: InfoLevel,
+		channels: channels,
+		routes:   make(map[Level][]string, len(cfg.Routes)),
+		fallback: cfg.DefaultChannel,
+	}
+
+	if cfg.DefaultLevel != "" {
+		a.minLevel = Level(cfg.DefaultLevel)
+		if _, ok := severity[a.minLevel]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownLevel, cfg.DefaultLevel)
+		}
+	}
+
+	for level, names := range cfg.Routes {
+		if _, ok := severity[Level(level)]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownLevel, level)
+		}
+
+		a.routes[Level(level)] = names
+	}
+
+	return a, nil
+}
+
+// Alert sends a text alert.
+//
+// Parameters:
+//   - ctx: context.Context - The context of the alert.
+//   - level: Level - The severity; alerts below the configured minimum level are dropped.
+//   - title: string - The alert title.
+//   - message: string - The alert body, markdown is rendered by channels supporting it.
+//
+// Returns:
+//   - error: ErrUnknownLevel, or the errors of the channels that failed.
+//
+// Example:
+//
+//	err := alerter.Alert(ctx, alert.ErrorLevel, "Order sync failed", err.Error())
+func (a *Alerter) Alert(ctx context.Context, level Level, title, message string) error {
+	return a.AlertCard(ctx, level, "", larkcard.New(title).Text(message))
+}
+
+// AlertCard sends a card alert of the given type.
+//
+// The channels get a copy of card with the header color of the level, so card can be reused
+// for alerts of other levels; the Lark card configuration of alertType may still override it.
+//
+// Parameters:
+//   - ctx: context.Context - The context of the alert.
+//   - level: Level - The severity; alerts below the configured minimum level are dropped.
+//   - alertType: string - The alert type, e.g. "login_alert"; empty for generic alerts.
+//   - card: *larkcard.LarkCard - The alert content.
+//
+// Returns:
+//   - error: ErrUnknownLevel, or the errors of the channels that failed.
+func (a *Alerter) AlertCard(ctx context.Context, level Level, alertType string, card *larkcard.LarkCard) error {
+	if a == nil {
+		return nil
+	}
+
+	s, ok := severity[level]
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownLevel, level)
+	}
+
+	if s < severity[a.minLevel] {
+		return nil
+	}
+
+	card = card.Clone().Color(levelColor[level])
+
+	var errs []error
+	for _, name := range a.route(level) {
+		channel, ok := a.channels[name]
+		if !ok {
+			continue
+		}
+
+		if err := channel.Send(ctx, level, alertType, card); err != nil {
+			errs = append(errs, fmt.Errorf("send alert to %s failed: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// route returns the channel names of level.
+func (a *Alerter) route(level Level) []string {
+	if names, ok := a.routes[level]; ok {
+		return names
+	}
+
+	if a.fallback == "" {
+		return nil
+	}
+
+	return []string{a.fallback}
+}
+
+// larkChannel sends alerts as Lark cards.
+type larkChannel struct {
+	sender *larkcard.Sender
+}
+
+// NewLarkChannel creates a Channel sending alerts through a Lark card sender.
+//
+// Parameters:
+//   - sender: *larkcard.Sender - The card sender; the alert type selects its card configuration.
+//
+// Returns:
+//   - Channel: The Lark channel.
+func NewLarkChannel(sender *larkcard.Sender) Channel {
+	return larkChannel{sender: sender}
+}
+
+// Send sends card through Lark.
+func (l larkChannel) Send(_ context.Context, _ Level, alertType string, card *larkcard.LarkCard) error {
+	_, err := l.sender.Send(alertType, card)
+	return err
+}
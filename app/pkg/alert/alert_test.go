@@ -0,0 +1,123 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package alert
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/seakee/go-api/app/config"
+	"github.com/seakee/go-api/app/pkg/larkcard"
+)
+
+// fakeChannel records the levels and header colors it received and fails when err is set.
+type fakeChannel struct {
+	levels []Level
+	colors []string
+	err    error
+}
+
+func (f *fakeChannel) Send(_ context.Context, level Level, _ string, card *larkcard.LarkCard) error {
+	f.levels = append(f.levels, level)
+	f.colors = append(f.colors, headerColor(card))
+	return f.err
+}
+
+// headerColor returns the header color of card.
+func headerColor(card *larkcard.LarkCard) string {
+	return card.Build()["header"].(map[string]any)["template"].(string)
+}
+
+// TestAlerter_Alert checks the minimum level threshold and the routing by level.
+func TestAlerter_Alert(t *testing.T) {
+	lark, email := &fakeChannel{}, &fakeChannel{}
+
+	a, err := New(config.Notify{
+		DefaultChannel: "lark",
+		DefaultLevel:   "warn",
+		Routes:         map[string][]string{"error": {"lark", "email", "disabled"}},
+	}, map[string]Channel{"lark": lark, "email": email})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	ctx := context.Background()
+	for _, level := range []Level{InfoLevel, SuccessLevel, WarnLevel, ErrorLevel} {
+		if err = a.Alert(ctx, level, "title", "message"); err != nil {
+			t.Fatalf("Alert(%s) error = %v", level, err)
+		}
+	}
+
+	if want := []Level{WarnLevel, ErrorLevel}; !reflect.DeepEqual(lark.levels, want) {
+		t.Errorf("lark received %v, want %v", lark.levels, want)
+	}
+
+	if want := []Level{ErrorLevel}; !reflect.DeepEqual(email.levels, want) {
+		t.Errorf("email received %v, want %v", email.levels, want)
+	}
+
+	if err = a.Alert(ctx, "fatal", "title", "message"); !errors.Is(err, ErrUnknownLevel) {
+		t.Errorf("Alert(fatal) error = %v, want ErrUnknownLevel", err)
+	}
+}
+
+// TestAlerter_AlertChannelError checks that a failing channel does not stop the others.
+func TestAlerter_AlertChannelError(t *testing.T) {
+	sendErr := errors.New("send failed")
+	lark, email := &fakeChannel{err: sendErr}, &fakeChannel{}
+
+	a, err := New(config.Notify{
+		Routes: map[string][]string{"error": {"lark", "email"}},
+	}, map[string]Channel{"lark": lark, "email": email})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if err = a.Alert(context.Background(), ErrorLevel, "title", "message"); !errors.Is(err, sendErr) {
+		t.Errorf("Alert() error = %v, want %v", err, sendErr)
+	}
+
+	if len(email.levels) != 1 {
+		t.Errorf("email received %d alerts, want 1", len(email.levels))
+	}
+}
+
+// TestAlerter_AlertCardReused checks that the level color doesn't stick to a reused card.
+func TestAlerter_AlertCardReused(t *testing.T) {
+	lark := &fakeChannel{}
+
+	a, err := New(config.Notify{DefaultChannel: "lark"}, map[string]Channel{"lark": lark})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	card := larkcard.New("title").Field("key", "value")
+	for _, level := range []Level{ErrorLevel, WarnLevel} {
+		if err = a.AlertCard(context.Background(), level, "", card); err != nil {
+			t.Fatalf("AlertCard(%s) error = %v", level, err)
+		}
+	}
+
+	if want := []string{string(larkcard.Red), string(larkcard.Yellow)}; !reflect.DeepEqual(lark.colors, want) {
+		t.Errorf("lark received colors %v, want %v", lark.colors, want)
+	}
+
+	if got := headerColor(card); got != string(larkcard.Blue) {
+		t.Errorf("card color after AlertCard() = %s, want it unchanged (%s)", got, larkcard.Blue)
+	}
+}
+
+// TestNew checks that unknown levels in the configuration are rejected.
+func TestNew(t *testing.T) {
+	if _, err := New(config.Notify{DefaultLevel: "debug"}, nil); !errors.Is(err, ErrUnknownLevel) {
+		t.Errorf("New() with default level debug error = %v, want ErrUnknownLevel", err)
+	}
+
+	if _, err := New(config.Notify{Routes: map[string][]string{"fatal": {"lark"}}}, nil); !errors.Is(err, ErrUnknownLevel) {
+		t.Errorf("New() with route fatal error = %v, want ErrUnknownLevel", err)
+	}
+}
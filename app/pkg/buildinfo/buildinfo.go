@@ -0,0 +1,82 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package buildinfo holds the build metadata injected at link time.
+//
+// Set the variables with -ldflags when building, e.g.:
+//
+//	go build -ldflags "-X github.com/seakee/go-api/app/pkg/buildinfo.Commit=$(git rev-parse --short HEAD) \
+//	    -X github.com/seakee/go-api/app/pkg/buildinfo.BuildTime=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./main.go
+package buildinfo
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// Unknown is the value of build metadata that was not injected.
+const Unknown = "unknown"
+
+// Build metadata, injected with -ldflags "-X".
+var (
+	Commit    = Unknown // Git commit the binary was built from
+	BuildTime = Unknown // Build time, RFC 3339 in UTC
+)
+
+// Info describes the running build.
+type Info struct {
+	Name      string `json:"name"`       // Application name
+	Version   string `json:"version"`    // Application version from the configuration
+	Commit    string `json:"commit"`     // Git commit the binary was built from
+	BuildTime string `json:"build_time"` // Build time
+	GoVersion string `json:"go_version"` // Go version the binary was built with
+}
+
+// Get returns the build information of the running binary.
+//
+// When Commit was not injected, the VCS revision recorded by the Go toolchain is used if present.
+//
+// Parameters:
+//   - name: The application name.
+//   - version: The application version.
+//
+// Returns:
+//   - Info: The build information.
+//
+// Example:
+//
+//	info := buildinfo.Get(cfg.System.Name, cfg.System.Version)
+func Get(name, version string) Info {
+	if version == "" {
+		version = Unknown
+	}
+
+	return Info{
+		Name:      name,
+		Version:   version,
+		Commit:    commit(),
+		BuildTime: BuildTime,
+		GoVersion: runtime.Version(),
+	}
+}
+
+// commit returns the injected Commit, falling back to the VCS revision stamped by go build.
+func commit() string {
+	if Commit != Unknown {
+		return Commit
+	}
+
+	bi, ok := debug.ReadBuildInfo()
+	if !ok {
+		return Unknown
+	}
+
+	for _, setting := range bi.Settings {
+		if setting.Key == "vcs.revision" && setting.Value != "" {
+			return setting.Value
+		}
+	}
+
+	return Unknown
+}
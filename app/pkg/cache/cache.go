@@ -0,0 +1,124 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package cache provides helpers for the read-through caching pattern on top of Redis.
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	redigo "github.com/gomodule/redigo/redis"
+	"github.com/sk-pkg/logger"
+	"github.com/sk-pkg/redis"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+// RebuildTimeout bounds a rebuild, which doesn't stop with the context of the caller running it.
+const RebuildTimeout = 10 * time.Second
+
+// rebuilds deduplicates the concurrent rebuilds of a key.
+var rebuilds singleflight.Group
+
+// Remember returns the value cached under key, building and caching it on a miss.
+//
+// A miss (redigo.ErrNil), an unreadable entry, or an unavailable Redis all fall through to build,
+// so a cache problem never fails the caller. Such problems and failing to write the rebuilt value
+// back are logged as warnings with the trace ID of ctx, and otherwise ignored.
+//
+// Concurrent misses of the same key in the process run build once: the other callers wait for
+// it and share its result, so an expired entry doesn't send every request to the database at
+// once. They share the value itself, so a pointer T must not be modified by the callers.
+// The shared build runs with the values of the context of the first caller but not its
+// cancellation, bounded by RebuildTimeout instead, so one cancelled request doesn't fail the
+// others waiting for the same key.
+//
+// Parameters:
+//   - ctx: The context for the operation; a cancelled context stops waiting for the value, the
+//     rebuild goes on for the other callers.
+//   - log: The logger of the cache problems; nil discards them.
+//   - redis: The Redis manager holding the cache.
+//   - key: The cache key (the manager's prefix is applied).
+//   - ttl: The expiration of the cached value in seconds; 0 means no expiration.
+//   - build: The function producing the value on a miss, with the context it must use.
+//
+// Returns:
+//   - T: The cached or freshly built value.
+//   - error: The error returned by build, or the context error if ctx is done before the value is built.
+//
+// Example:
+//
+//	app, err := cache.Remember(ctx, r.logger, r.redis, "auth:app:"+appID, 300, func(ctx context.Context) (*auth.App, error) {
+//	    return (&auth.App{AppID: appID}).First(ctx, r.db)
+//	})
+func Remember[T any](ctx context.Context, log *logger.Manager, redis *redis.Manager, key string, ttl int, build func(ctx context.Context) (T, error)) (T, error) {
+	var value T
+
+	err := redis.GetJSON(key, &value)
+	if err == nil {
+		return value, nil
+	}
+
+	if !errors.Is(err, redigo.ErrNil) && log != nil {
+		log.Warn(ctx, "Cache get failed, rebuilding", zap.String("key", key), zap.Error(err))
+	}
+
+	if err = ctx.Err(); err != nil {
+		return value, err
+	}
+
+	return rebuild(ctx, key, build, func(ctx context.Context, value T) {
+		if err := redis.SetJSON(key, value, ttl); err != nil && log != nil {
+			log.Warn(ctx, "Cache set failed", zap.String("key", key), zap.Error(err))
+		}
+	})
+}
+
+// rebuild builds the value of key and stores it, once for all the concurrent callers, with a
+// context detached from the cancellation of ctx and bounded by RebuildTimeout.
+func rebuild[T any](ctx context.Context, key string, build func(context.Context) (T, error), store func(context.Context, T)) (T, error) {
+	var value T
+
+	ch := rebuilds.DoChan(key, func() (interface{}, error) {
+		buildCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), RebuildTimeout)
+		defer cancel()
+
+		built, err := build(buildCtx)
+		if err != nil {
+			return nil, err
+		}
+
+		store(buildCtx, built)
+
+		return built, nil
+	})
+
+	select {
+	case <-ctx.Done():
+		return value, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return value, res.Err
+		}
+
+		value, _ = res.Val.(T)
+
+		return value, nil
+	}
+}
+
+// Forget removes the value cached under key.
+//
+// Parameters:
+//   - redis: The Redis manager holding the cache.
+//   - key: The cache key (the manager's prefix is applied).
+//
+// Returns:
+//   - error: An error if the deletion fails.
+func Forget(redis *redis.Manager, key string) error {
+	_, err := redis.Del(key)
+	return err
+}
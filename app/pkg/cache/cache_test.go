@@ -0,0 +1,117 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package cache
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRebuildOncePerKey(t *testing.T) {
+	const callers = 20
+
+	var (
+		builds  int32
+		stores  int32
+		waiting sync.WaitGroup
+		done    sync.WaitGroup
+	)
+
+	release := make(chan struct{})
+	build := func(context.Context) (string, error) {
+		atomic.AddInt32(&builds, 1)
+		<-release
+		return "value", nil
+	}
+	store := func(context.Context, string) {
+		atomic.AddInt32(&stores, 1)
+	}
+
+	waiting.Add(callers)
+	done.Add(callers)
+	for i := 0; i < callers; i++ {
+		go func() {
+			defer done.Done()
+			waiting.Done()
+
+			value, err := rebuild(context.Background(), "auth:app:go-api-a", build, store)
+			if err != nil || value != "value" {
+				t.Errorf("rebuild() = %q, %v, want value, nil", value, err)
+			}
+		}()
+	}
+
+	// Let every caller reach the in-flight rebuild before it completes
+	waiting.Wait()
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	done.Wait()
+
+	if builds != 1 || stores != 1 {
+		t.Errorf("build ran %d times and store %d times, want once each", builds, stores)
+	}
+}
+
+func TestRebuildContextCancelled(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := rebuild(ctx, "auth:app:go-api-b", func(context.Context) (int, error) {
+		<-release
+		return 1, nil
+	}, func(context.Context, int) {})
+	if err != context.DeadlineExceeded {
+		t.Errorf("rebuild() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRebuildSurvivesCancelledFirstCaller(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+
+	var buildErr error
+	build := func(ctx context.Context) (string, error) {
+		close(started)
+		<-release
+		buildErr = ctx.Err()
+		return "value", nil
+	}
+
+	first, cancel := context.WithCancel(context.Background())
+	firstDone := make(chan error, 1)
+	go func() {
+		_, err := rebuild(first, "auth:app:go-api-c", build, func(context.Context, string) {})
+		firstDone <- err
+	}()
+
+	// Join the rebuild of the first caller, then cancel it
+	<-started
+	waiterDone := make(chan string, 1)
+	go func() {
+		value, _ := rebuild(context.Background(), "auth:app:go-api-c", build, func(context.Context, string) {})
+		waiterDone <- value
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	cancel()
+	if err := <-firstDone; err != context.Canceled {
+		t.Errorf("first rebuild() error = %v, want %v", err, context.Canceled)
+	}
+
+	close(release)
+	if value := <-waiterDone; value != "value" {
+		t.Errorf("waiting rebuild() = %q, want value", value)
+	}
+
+	if buildErr != nil {
+		t.Errorf("build context error = %v, want nil", buildErr)
+	}
+}
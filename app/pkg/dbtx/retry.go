@@ -0,0 +1,96 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package dbtx provides helpers for running SQL transactions through gorm.
+package dbtx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	// retryBackoff is the base wait before the next attempt, multiplied by the attempt number.
+	retryBackoff = 20 * time.Millisecond
+
+	mysqlDeadlock        = 1213    // ER_LOCK_DEADLOCK
+	postgresDeadlock     = "40P01" // deadlock_detected
+	postgresSerializable = "40001" // serialization_failure
+)
+
+// RetryOnDeadlock runs fn, and runs it again while it fails with a deadlock or serialization error.
+//
+// The database rolls the whole transaction back on these errors, so fn must run the whole
+// transaction, not a statement of it. Other errors are returned at once. The attempts are
+// spaced by a short, growing and jittered wait, so the competing transactions don't collide again.
+//
+// Parameters:
+//   - ctx: Context bounding the waits between the attempts.
+//   - fn: The transaction.
+//   - maxAttempts: The number of attempts, including the first; 0 or less runs fn once.
+//
+// Returns:
+//   - error: The error of fn, wrapped once the attempts are exhausted, or the error of ctx.
+//
+// Example:
+//
+//	err := dbtx.RetryOnDeadlock(ctx, func() error {
+//	    return db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+//	        if err := tx.Create(&app).Error; err != nil {
+//	            return err
+//	        }
+//	        return tx.Model(&quota).Update("used", gorm.Expr("used + 1")).Error
+//	    })
+//	}, 3)
+func RetryOnDeadlock(ctx context.Context, fn func() error, maxAttempts int) error {
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || !IsDeadlock(err) {
+			return err
+		}
+
+		if attempt >= maxAttempts {
+			return fmt.Errorf("transaction failed after %d attempts: %w", attempt, err)
+		}
+
+		wait := time.Duration(attempt) * retryBackoff
+		wait += time.Duration(rand.Int63n(int64(wait)))
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("retry transaction failed: %w", ctx.Err())
+		case <-timer.C:
+		}
+	}
+}
+
+// IsDeadlock reports whether err is a MySQL deadlock or a Postgres deadlock or serialization failure.
+//
+// Parameters:
+//   - err: The error of a statement or a transaction.
+//
+// Returns:
+//   - bool: true if the transaction was rolled back and can be retried.
+func IsDeadlock(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlDeadlock
+	}
+
+	// The errors of pgx and lib/pq both expose their SQLSTATE
+	var pgErr interface{ SQLState() string }
+	if errors.As(err, &pgErr) {
+		state := pgErr.SQLState()
+		return state == postgresDeadlock || state == postgresSerializable
+	}
+
+	return false
+}
@@ -0,0 +1,77 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package dbtx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// pgError mimics the errors of the Postgres drivers.
+type pgError string
+
+func (e pgError) Error() string    { return "pg error " + string(e) }
+func (e pgError) SQLState() string { return string(e) }
+
+func TestRetryOnDeadlock(t *testing.T) {
+	deadlock := &mysql.MySQLError{Number: mysqlDeadlock, Message: "Deadlock found when trying to get lock"}
+
+	attempts := 0
+	err := RetryOnDeadlock(context.Background(), func() error {
+		attempts++
+		if attempts == 1 {
+			return fmt.Errorf("create menu failed: %w", deadlock)
+		}
+
+		return nil
+	}, 3)
+
+	if err != nil || attempts != 2 {
+		t.Errorf("RetryOnDeadlock() error = %v after %d attempts, want nil after 2", err, attempts)
+	}
+}
+
+func TestRetryOnDeadlockGivesUp(t *testing.T) {
+	attempts := 0
+	err := RetryOnDeadlock(context.Background(), func() error {
+		attempts++
+		return pgError(postgresSerializable)
+	}, 3)
+
+	if !errors.Is(err, pgError(postgresSerializable)) || attempts != 3 {
+		t.Errorf("RetryOnDeadlock() error = %v after %d attempts, want the serialization failure after 3", err, attempts)
+	}
+}
+
+func TestRetryOnDeadlockOtherErrors(t *testing.T) {
+	duplicate := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}
+
+	attempts := 0
+	err := RetryOnDeadlock(context.Background(), func() error {
+		attempts++
+		return duplicate
+	}, 3)
+
+	if !errors.Is(err, duplicate) || attempts != 1 {
+		t.Errorf("RetryOnDeadlock() error = %v after %d attempts, want the duplicate error after 1", err, attempts)
+	}
+}
+
+func TestRetryOnDeadlockContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := RetryOnDeadlock(ctx, func() error {
+		return pgError(postgresDeadlock)
+	}, 3)
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("RetryOnDeadlock() error = %v, want %v", err, context.Canceled)
+	}
+}
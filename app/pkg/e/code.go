@@ -6,13 +6,17 @@
 // These codes help standardize error handling and client-side error interpretation.
 package e
 
+import "fmt"
+
 // Error codes
 const (
 	BUSY    = -1  // System is busy
 	SUCCESS = 0   // Operation successful
 	ERROR   = 500 // General server error
 
-	InvalidParams = 400 // Invalid parameters
+	InvalidParams  = 400 // Invalid parameters
+	Forbidden      = 403 // The client is not allowed to access the resource
+	RequestTimeout = 504 // Request processing exceeded its deadline
 
 	ServerUnauthorized         = 10001 // Server is not authorized
 	ServerAuthorizationExpired = 10002 // Server authorization has expired
@@ -21,4 +25,75 @@ const (
 	ServerAppAlreadyExists     = 10005 // Server application already exists
 	ServerAPIUserNotFound      = 10006 // Server API user not found
 	InvalidServerAppID         = 10007 // Invalid server application ID
+	RequestInProgress          = 10008 // A request with the same idempotency key is still in progress
+	IdempotencyKeyReused       = 10009 // The idempotency key was already used with a different request body
+	IdempotencyKeyCompleted    = 10010 // The request with the idempotency key succeeded and its response isn't replayed
+
+	MissingToken      = 11000 // The request carries no token
+	InvalidToken      = 11001 // The Authorization header is malformed or the token too long
+	CSRFTokenMismatch = 11002 // The CSRF header is missing or doesn't match the CSRF cookie
 )
+
+// messages holds the built-in English message of every error code.
+// It is the last-resort fallback when a language file lacks a translation.
+var messages = map[int]string{
+	BUSY:                       "System is busy",
+	SUCCESS:                    "ok",
+	ERROR:                      "fail",
+	InvalidParams:              "Request parameter error",
+	Forbidden:                  "Access denied",
+	RequestTimeout:             "Request timed out",
+	ServerUnauthorized:         "Unauthorized",
+	ServerAuthorizationExpired: "Authorization has expired",
+	ServerAuthorizationFail:    "Authorization failed",
+	ServerAppNotFound:          "Application does not exist or account info error",
+	ServerAppAlreadyExists:     "Application already exists",
+	ServerAPIUserNotFound:      "User does not exist",
+	InvalidServerAppID:         "Invalid application ID",
+	RequestInProgress:          "A request with the same idempotency key is still in progress",
+	IdempotencyKeyReused:       "The idempotency key was already used with a different request",
+	IdempotencyKeyCompleted:    "The request with this idempotency key already succeeded",
+	MissingToken:               "Missing token",
+	InvalidToken:               "Invalid token",
+	CSRFTokenMismatch:          "CSRF token missing or mismatched",
+}
+
+// Codes returns all error codes defined in this package.
+//
+// Returns:
+//   - []int: The error codes, in no particular order.
+func Codes() []int {
+	codes := make([]int, 0, len(messages))
+	for code := range messages {
+		codes = append(codes, code)
+	}
+
+	return codes
+}
+
+// Message returns the built-in English message of an error code.
+//
+// Parameters:
+//   - code: The error code.
+//
+// Returns:
+//   - string: The message, or "Unknown error" if the code is unknown.
+func Message(code int) string {
+	if msg, ok := messages[code]; ok {
+		return msg
+	}
+
+	return "Unknown error"
+}
+
+// FallbackMessage returns the message rendered for an error code without a translation: its
+// built-in English message followed by the numeric code.
+//
+// Parameters:
+//   - code: The error code.
+//
+// Returns:
+//   - string: The message, e.g. "Request timed out (code 504)" or "Unknown error (code 12345)".
+func FallbackMessage(code int) string {
+	return fmt.Sprintf("%s (code %d)", Message(code), code)
+}
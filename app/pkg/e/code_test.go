@@ -0,0 +1,94 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package e
+
+import (
+	"encoding/json"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+)
+
+// langDir is the directory holding the language files, relative to this package.
+var langDir = filepath.Join("..", "..", "..", "bin", "lang")
+
+// TestCodes_HaveMessages asserts that every constant declared in code.go has a built-in message.
+func TestCodes_HaveMessages(t *testing.T) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "code.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	declared := 0
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+
+		for _, spec := range gen.Specs {
+			declared += len(spec.(*ast.ValueSpec).Names)
+		}
+	}
+
+	if declared != len(messages) {
+		t.Errorf("code.go declares %d codes but messages has %d entries", declared, len(messages))
+	}
+}
+
+// TestCodes_HaveTranslations asserts that every error code is translated in every language file.
+func TestCodes_HaveTranslations(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join(langDir, "*.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(files) == 0 {
+		t.Fatalf("no language files found in %s", langDir)
+	}
+
+	for _, file := range files {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var translations map[string]string
+		if err = json.Unmarshal(content, &translations); err != nil {
+			t.Fatalf("%s: %v", file, err)
+		}
+
+		for _, code := range Codes() {
+			if translations[strconv.Itoa(code)] == "" {
+				t.Errorf("%s: missing translation for code %d", filepath.Base(file), code)
+			}
+		}
+	}
+}
+
+func TestMessage(t *testing.T) {
+	if got := Message(InvalidParams); got != "Request parameter error" {
+		t.Errorf("Message(InvalidParams) = %q", got)
+	}
+
+	if got := Message(-12345); got != "Unknown error" {
+		t.Errorf("Message(-12345) = %q", got)
+	}
+}
+
+func TestFallbackMessage(t *testing.T) {
+	if got := FallbackMessage(RequestTimeout); got != "Request timed out (code 504)" {
+		t.Errorf("FallbackMessage(RequestTimeout) = %q", got)
+	}
+
+	if got := FallbackMessage(-12345); got != "Unknown error (code -12345)" {
+		t.Errorf("FallbackMessage(-12345) = %q", got)
+	}
+}
@@ -0,0 +1,94 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package e
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// APIError is an error carrying the API error code to respond with, the underlying cause,
+// and optional field-level validation details.
+type APIError struct {
+	Code   int               // Error code from this package
+	Err    error             // Underlying cause, reported in the response trace in debug mode
+	Fields map[string]string // Field-level details, keyed by request field name
+}
+
+// New creates an APIError with the given code and cause.
+//
+// Parameters:
+//   - code: The error code to respond with.
+//   - err: The underlying cause; may be nil.
+//
+// Returns:
+//   - *APIError: The new error.
+//
+// Example:
+//
+//	if exists {
+//	    return nil, e.New(e.ServerAppAlreadyExists, nil)
+//	}
+func New(code int, err error) *APIError {
+	return &APIError{Code: code, Err: err}
+}
+
+// Error implements the error interface.
+func (ae *APIError) Error() string {
+	if ae.Err != nil {
+		return fmt.Sprintf("%s (code: %d): %v", Message(ae.Code), ae.Code, ae.Err)
+	}
+
+	return fmt.Sprintf("%s (code: %d)", Message(ae.Code), ae.Code)
+}
+
+// Unwrap returns the underlying cause, so errors.Is and errors.As see through an APIError.
+func (ae *APIError) Unwrap() error {
+	return ae.Err
+}
+
+// WithField adds a field-level detail to the error.
+//
+// Parameters:
+//   - field: The request field name.
+//   - msg: The message describing the problem with the field.
+//
+// Returns:
+//   - *APIError: The same error, for chaining.
+func (ae *APIError) WithField(field, msg string) *APIError {
+	if ae.Fields == nil {
+		ae.Fields = make(map[string]string)
+	}
+
+	ae.Fields[field] = msg
+
+	return ae
+}
+
+// AsAPIError converts err into an APIError.
+//
+// Parameters:
+//   - err: The error to convert.
+//
+// Returns:
+//   - *APIError: The APIError found in err's chain, a RequestTimeout-coded APIError if err is
+//     context.DeadlineExceeded, or an ERROR-coded APIError wrapping err. A nil err yields nil.
+func AsAPIError(err error) *APIError {
+	if err == nil {
+		return nil
+	}
+
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+
+	if errors.Is(err, context.DeadlineExceeded) {
+		return New(RequestTimeout, err)
+	}
+
+	return New(ERROR, err)
+}
@@ -0,0 +1,47 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package e
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+func TestAsAPIError(t *testing.T) {
+	cause := errors.New("duplicate entry")
+
+	if AsAPIError(nil) != nil {
+		t.Error("AsAPIError(nil) should be nil")
+	}
+
+	wrapped := fmt.Errorf("create app: %w", New(ServerAppAlreadyExists, cause))
+	apiErr := AsAPIError(wrapped)
+	if apiErr.Code != ServerAppAlreadyExists {
+		t.Errorf("code = %d, want %d", apiErr.Code, ServerAppAlreadyExists)
+	}
+
+	if !errors.Is(wrapped, cause) {
+		t.Error("errors.Is should see the cause through APIError")
+	}
+
+	plain := AsAPIError(cause)
+	if plain.Code != ERROR || !errors.Is(plain, cause) {
+		t.Errorf("plain error should map to ERROR wrapping the cause, got %v", plain)
+	}
+
+	timeout := AsAPIError(fmt.Errorf("query app: %w", context.DeadlineExceeded))
+	if timeout.Code != RequestTimeout {
+		t.Errorf("deadline exceeded should map to RequestTimeout, got %v", timeout)
+	}
+}
+
+func TestAPIError_WithField(t *testing.T) {
+	apiErr := New(InvalidParams, nil).WithField("app_name", "required")
+	if apiErr.Fields["app_name"] != "required" {
+		t.Errorf("fields = %v", apiErr.Fields)
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package featureflag tells whether the features being rolled out are enabled.
+//
+// Flags default to their state in the configuration and can be flipped without a redeploy in
+// the "feature_flags" Redis hash, e.g. `HSET go-api:feature_flags maintenance_mode 1` with the
+// key prefix of the connection. An override is a boolean as parsed by strconv.ParseBool;
+// delete the field to fall back to the configuration.
+package featureflag
+
+import (
+	"context"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/seakee/go-api/app/config"
+)
+
+const (
+	// RedisKey is the Redis hash holding the overrides, by flag name.
+	RedisKey = "feature_flags"
+
+	// DefaultCacheTTL is the time the overrides are cached when no TTL is configured.
+	DefaultCacheTTL = 10 * time.Second
+)
+
+// Store reads the overrides; *redis.Manager satisfies it.
+type Store interface {
+	HGetAll(key string) (map[string]string, error)
+}
+
+// Flags tells whether the feature flags are enabled.
+type Flags struct {
+	defaults map[string]bool
+	store    Store
+	ttl      time.Duration
+	now      func() time.Time // Clock of the cache, replaced in tests
+
+	mu        sync.Mutex
+	overrides map[string]bool
+	expiresAt time.Time
+}
+
+// New creates Flags from the configuration.
+//
+// Parameters:
+//   - cfg: config.FeatureFlags - The default state of the flags and the TTL of the cached overrides.
+//   - store: Store - The Redis connection holding the overrides; nil disables them.
+//
+// Returns:
+//   - *Flags: A new Flags instance.
+func New(cfg config.FeatureFlags, store Store) *Flags {
+	ttl := cfg.CacheTTL * time.Second
+	if ttl <= 0 {
+		ttl = DefaultCacheTTL
+	}
+
+	defaults := make(map[string]bool, len(cfg.Flags))
+	for name, enabled := range cfg.Flags {
+		defaults[name] = enabled
+	}
+
+	return &Flags{defaults: defaults, store: store, ttl: ttl, now: time.Now}
+}
+
+// IsEnabled reports whether the flag name is enabled.
+//
+// The Redis override wins over the configuration. The overrides are read at most once per
+// cache TTL, all at once, so a flip takes up to the TTL to apply. When Redis can't be read,
+// the overrides read last are kept until the next attempt.
+//
+// Parameters:
+//   - ctx: context.Context - The context of the caller.
+//   - name: string - The flag, e.g. "maintenance_mode".
+//
+// Returns:
+//   - bool: true if the flag is enabled; unknown flags are disabled.
+//
+// Example:
+//
+//	if h.AppCtx.Flags.IsEnabled(ctx, "login_alerts") {
+//	    h.alertLogin(ctx, app)
+//	}
+func (f *Flags) IsEnabled(_ context.Context, name string) bool {
+	if f == nil {
+		return false
+	}
+
+	if enabled, ok := f.override(name); ok {
+		return enabled
+	}
+
+	return f.defaults[name]
+}
+
+// override returns the Redis override of name, refreshing the cached overrides once they expired.
+func (f *Flags) override(name string) (enabled, ok bool) {
+	if f.store == nil {
+		return false, false
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if now := f.now(); !now.Before(f.expiresAt) {
+		// Retry a failed read after the TTL too, rather than hitting a down Redis on every call
+		f.expiresAt = now.Add(f.ttl)
+
+		if values, err := f.store.HGetAll(RedisKey); err == nil {
+			f.overrides = parse(values)
+		}
+	}
+
+	enabled, ok = f.overrides[name]
+
+	return enabled, ok
+}
+
+// parse converts the fields of the Redis hash into overrides, skipping the invalid values.
+func parse(values map[string]string) map[string]bool {
+	overrides := make(map[string]bool, len(values))
+	for name, value := range values {
+		if enabled, err := strconv.ParseBool(value); err == nil {
+			overrides[name] = enabled
+		}
+	}
+
+	return overrides
+}
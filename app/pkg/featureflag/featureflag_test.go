@@ -0,0 +1,87 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package featureflag
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/seakee/go-api/app/config"
+)
+
+// fakeStore is a Store counting its reads.
+type fakeStore struct {
+	values map[string]string
+	err    error
+	reads  int
+}
+
+func (s *fakeStore) HGetAll(string) (map[string]string, error) {
+	s.reads++
+	return s.values, s.err
+}
+
+func TestIsEnabled(t *testing.T) {
+	store := &fakeStore{values: map[string]string{"auto_provisioning": "0", "maintenance_mode": "true", "bogus": "maybe"}}
+	f := New(config.FeatureFlags{Flags: map[string]bool{"auto_provisioning": true, "login_alerts": true, "bogus": true}}, store)
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		want bool
+	}{
+		{name: "auto_provisioning", want: false}, // Disabled in Redis
+		{name: "maintenance_mode", want: true},   // Only enabled in Redis
+		{name: "login_alerts", want: true},       // Only enabled in the configuration
+		{name: "bogus", want: true},              // Invalid override
+		{name: "unknown", want: false},
+	}
+
+	for _, tt := range tests {
+		if got := f.IsEnabled(ctx, tt.name); got != tt.want {
+			t.Errorf("IsEnabled(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+
+	if store.reads != 1 {
+		t.Errorf("Redis read %d times, want once", store.reads)
+	}
+}
+
+func TestIsEnabledCache(t *testing.T) {
+	now := time.Now()
+	store := &fakeStore{values: map[string]string{"maintenance_mode": "1"}}
+	f := New(config.FeatureFlags{CacheTTL: 10}, store)
+	f.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if !f.IsEnabled(ctx, "maintenance_mode") {
+		t.Fatal("IsEnabled() = false, want the Redis override")
+	}
+
+	// Flipped in Redis: the cached overrides apply until they expire
+	store.values = map[string]string{"maintenance_mode": "0"}
+	if !f.IsEnabled(ctx, "maintenance_mode") {
+		t.Error("IsEnabled() = false before the TTL, want the cached override")
+	}
+
+	now = now.Add(10 * time.Second)
+	if f.IsEnabled(ctx, "maintenance_mode") {
+		t.Error("IsEnabled() = true after the TTL, want the new override")
+	}
+
+	// Redis is down: the last overrides are kept
+	store.err = errors.New("connection refused")
+	now = now.Add(10 * time.Second)
+	if f.IsEnabled(ctx, "maintenance_mode") {
+		t.Error("IsEnabled() = true while Redis is down, want the last override")
+	}
+
+	if store.reads != 3 {
+		t.Errorf("Redis read %d times, want 3", store.reads)
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package health checks the dependencies the application needs to serve traffic, for the
+// readiness endpoint.
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// DefaultTimeout bounds each check when no timeout is given.
+const DefaultTimeout = 3 * time.Second
+
+// Check reports whether a dependency is usable; it should return once ctx is done.
+type Check func(ctx context.Context) error
+
+// Checker runs the registered checks of the readiness endpoint.
+type Checker struct {
+	timeout time.Duration
+
+	mu     sync.RWMutex
+	checks map[string]Check
+}
+
+// New creates a Checker without checks.
+//
+// Parameters:
+//   - timeout: The time each check may take; 0 or less uses DefaultTimeout.
+//
+// Returns:
+//   - *Checker: A new Checker instance.
+func New(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	return &Checker{timeout: timeout, checks: make(map[string]Check)}
+}
+
+// Register adds a check under name, replacing the check already registered under it.
+//
+// Parameters:
+//   - name: The name of the dependency, reported with its error, e.g. "kafka".
+//   - check: The check.
+//
+// Example:
+//
+//	checker.Register("kafka", kafkaProbe.Ping)
+func (c *Checker) Register(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checks[name] = check
+}
+
+// Run runs every check concurrently, each bounded by the timeout of the Checker, and returns
+// the failed ones. A check still running at the timeout fails with context.DeadlineExceeded.
+//
+// Parameters:
+//   - ctx: The context of the readiness request.
+//
+// Returns:
+//   - map[string]error: The errors by check name; empty when every dependency is ready.
+func (c *Checker) Run(ctx context.Context) map[string]error {
+	c.mu.RLock()
+	checks := make(map[string]Check, len(c.checks))
+	for name, check := range c.checks {
+		checks[name] = check
+	}
+	c.mu.RUnlock()
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		failures = make(map[string]error)
+	)
+
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check Check) {
+			defer wg.Done()
+
+			if err := c.run(ctx, check); err != nil {
+				mu.Lock()
+				failures[name] = err
+				mu.Unlock()
+			}
+		}(name, check)
+	}
+
+	wg.Wait()
+
+	return failures
+}
+
+// run runs check and gives up on it at the timeout, even if it ignores its context.
+func (c *Checker) run(ctx context.Context, check Check) error {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		done <- check(ctx)
+	}()
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
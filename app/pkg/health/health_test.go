@@ -0,0 +1,50 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package health
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckerRun(t *testing.T) {
+	errDown := errors.New("broker down")
+
+	c := New(50 * time.Millisecond)
+	c.Register("redis", func(context.Context) error { return nil })
+	c.Register("kafka", func(context.Context) error { return errDown })
+	// Ignores its context: the checker gives up on it anyway
+	c.Register("slow", func(context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	})
+
+	start := time.Now()
+	failures := c.Run(context.Background())
+
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Run() took %v, want about the timeout", elapsed)
+	}
+
+	if len(failures) != 2 {
+		t.Fatalf("Run() failures = %v, want kafka and slow", failures)
+	}
+
+	if !errors.Is(failures["kafka"], errDown) {
+		t.Errorf("Run() kafka error = %v, want %v", failures["kafka"], errDown)
+	}
+
+	if !errors.Is(failures["slow"], context.DeadlineExceeded) {
+		t.Errorf("Run() slow error = %v, want %v", failures["slow"], context.DeadlineExceeded)
+	}
+}
+
+func TestCheckerRunWithoutChecks(t *testing.T) {
+	if failures := New(0).Run(context.Background()); len(failures) != 0 {
+		t.Errorf("Run() failures = %v, want none", failures)
+	}
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/IBM/sarama"
+	"github.com/seakee/go-api/app/config"
+)
+
+// Kafka probes the Kafka cluster the producer and the consumer are connected to.
+//
+// kafka.Manager doesn't expose its sarama client, so Kafka keeps a client of its own, used
+// only for the metadata requests of Ping.
+type Kafka struct {
+	client sarama.Client
+}
+
+// NewKafka creates a Kafka probe of the brokers in cfg. It doesn't connect until the first Ping.
+//
+// Parameters:
+//   - cfg: The Kafka configuration.
+//
+// Returns:
+//   - *Kafka: A new Kafka probe.
+//   - error: An error if the configuration is invalid, e.g. without brokers.
+func NewKafka(cfg config.Kafka) (*Kafka, error) {
+	conf := sarama.NewConfig()
+	conf.ClientID = cfg.ClientID
+	conf.Version = sarama.V3_5_1_0
+	// Fail fast: the readiness endpoint is polled again anyway
+	conf.Metadata.Full = false
+	conf.Metadata.Retry.Max = 0
+	conf.Metadata.Timeout = DefaultTimeout
+	conf.Net.DialTimeout = DefaultTimeout
+	conf.Net.ReadTimeout = DefaultTimeout
+	conf.Net.WriteTimeout = DefaultTimeout
+
+	client, err := sarama.NewClient(cfg.Brokers, conf)
+	if err != nil {
+		return nil, fmt.Errorf("create kafka client failed: %w", err)
+	}
+
+	return &Kafka{client: client}, nil
+}
+
+// Ping fetches the cluster metadata from one of the brokers.
+//
+// Parameters:
+//   - ctx: The context bounding the wait; the request itself is bounded by DefaultTimeout.
+//
+// Returns:
+//   - error: An error if no broker answered or the cluster has no brokers.
+//
+// Example:
+//
+//	checker.Register("kafka", kafkaProbe.Ping)
+func (k *Kafka) Ping(ctx context.Context) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- k.client.RefreshMetadata()
+	}()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("fetch kafka metadata failed: %w", err)
+		}
+	}
+
+	if len(k.client.Brokers()) == 0 {
+		return errors.New("kafka cluster has no brokers")
+	}
+
+	return nil
+}
+
+// Close closes the connections of the probe.
+func (k *Kafka) Close() error {
+	return k.client.Close()
+}
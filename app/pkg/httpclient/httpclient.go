@@ -0,0 +1,75 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package httpclient builds the shared resty client used for outbound HTTP calls,
+// so every upstream request gets the same timeout, retry and proxy behavior.
+package httpclient
+
+import (
+	"time"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/seakee/go-api/app/config"
+)
+
+const (
+	DefaultTimeout          = 10 * time.Second        // Applied when no timeout is configured
+	DefaultRetryWaitTime    = 500 * time.Millisecond  // Applied when no retry wait time is configured
+	DefaultRetryMaxWaitTime = 3000 * time.Millisecond // Applied when no max retry wait time is configured
+	DefaultUserAgent        = "go-api"                // Applied when no User-Agent is configured
+)
+
+// New creates a resty client from the given configuration.
+//
+// Zero values fall back to the package defaults, so a missing "http_client" block
+// still yields a client that times out instead of hanging on a slow upstream.
+//
+// Parameters:
+//   - cfg: The outbound HTTP client configuration.
+//
+// Returns:
+//   - *resty.Client: The configured client.
+//
+// Example:
+//
+//	client := httpclient.New(config.HTTPClient{Timeout: 5, RetryCount: 1})
+//	res, err := client.R().Get("https://example.com")
+func New(cfg config.HTTPClient) *resty.Client {
+	timeout := cfg.Timeout * time.Second
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	retryWaitTime := cfg.RetryWaitTime * time.Millisecond
+	if retryWaitTime <= 0 {
+		retryWaitTime = DefaultRetryWaitTime
+	}
+
+	retryMaxWaitTime := cfg.RetryMaxWaitTime * time.Millisecond
+	if retryMaxWaitTime <= 0 {
+		retryMaxWaitTime = DefaultRetryMaxWaitTime
+	}
+
+	if retryMaxWaitTime < retryWaitTime {
+		retryMaxWaitTime = retryWaitTime
+	}
+
+	userAgent := cfg.UserAgent
+	if userAgent == "" {
+		userAgent = DefaultUserAgent
+	}
+
+	client := resty.New().
+		SetTimeout(timeout).
+		SetRetryCount(cfg.RetryCount).
+		SetRetryWaitTime(retryWaitTime).
+		SetRetryMaxWaitTime(retryMaxWaitTime).
+		SetHeader("User-Agent", userAgent)
+
+	if cfg.Proxy != "" {
+		client.SetProxy(cfg.Proxy)
+	}
+
+	return client
+}
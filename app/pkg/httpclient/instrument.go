@@ -0,0 +1,113 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package httpclient
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-resty/resty/v2"
+	"github.com/seakee/go-api/app/pkg/sanitize"
+	"github.com/sk-pkg/logger"
+	"go.uber.org/zap"
+)
+
+// TraceIDHeader carries the trace ID of the inbound request to the upstreams.
+const TraceIDHeader = "X-Trace-ID"
+
+// Instrument logs and measures the requests of client, and propagates the trace ID to the upstreams.
+//
+// Every attempt is logged with its method, URL, host, status code and latency, at warn level
+// for 4xx and 5xx responses; requests failing without a response are logged at error level.
+// Every attempt is also counted in http_client_requests_total by host, method and status code,
+// "error" without a response, and its latency observed in http_client_request_duration_seconds.
+// The sensitive query parameters of the URLs, e.g. corpsecret and access_token, are masked.
+// The trace ID is taken from the request context, so pass h.Context(c) to SetContext.
+//
+// Parameters:
+//   - client: *resty.Client - The client to instrument.
+//   - log: *logger.Manager - The logger the requests are logged with.
+//
+// Returns:
+//   - *resty.Client: client, for chaining.
+//
+// Example:
+//
+//	client := httpclient.Instrument(httpclient.New(cfg.HTTPClient), log)
+//	res, err := client.R().SetContext(ctx).Get("https://example.com")
+func Instrument(client *resty.Client, log *logger.Manager) *resty.Client {
+	return client.
+		OnBeforeRequest(func(_ *resty.Client, r *resty.Request) error {
+			if traceID, ok := r.Context().Value(logger.TraceIDKey).(string); ok && traceID != "" && r.Header.Get(TraceIDHeader) == "" {
+				r.SetHeader(TraceIDHeader, traceID)
+			}
+
+			return nil
+		}).
+		OnAfterResponse(func(_ *resty.Client, resp *resty.Response) error {
+			r := resp.Request
+			observe(requestHost(r), r.Method, resp.StatusCode(), resp.Time())
+
+			fields := []zap.Field{
+				zap.String("Method", r.Method),
+				zap.String("URL", sanitize.URL(requestURL(r), nil)),
+				zap.String("Host", requestHost(r)),
+				zap.Int("StatusCode", resp.StatusCode()),
+				zap.Duration("Latency", resp.Time()),
+				zap.Int("Attempt", r.Attempt),
+			}
+
+			if resp.IsError() {
+				log.Warn(r.Context(), "Outbound request failed", fields...)
+			} else {
+				log.Info(r.Context(), "Outbound request", fields...)
+			}
+
+			return nil
+		}).
+		OnError(func(r *resty.Request, err error) {
+			// Responses are already logged; only log the requests that got none
+			var respErr *resty.ResponseError
+			if errors.As(err, &respErr) {
+				if respErr.Response.RawResponse != nil {
+					return
+				}
+
+				err = respErr.Err
+			}
+
+			observe(requestHost(r), r.Method, 0, 0)
+
+			rawURL := requestURL(r)
+			safeURL := sanitize.URL(rawURL, nil)
+
+			log.Error(r.Context(), "Outbound request failed",
+				zap.String("Method", r.Method),
+				zap.String("URL", safeURL),
+				zap.String("Host", requestHost(r)),
+				zap.Int("Attempt", r.Attempt),
+				// The error of net/http quotes the URL, query string included
+				zap.String("error", strings.ReplaceAll(err.Error(), rawURL, safeURL)),
+			)
+		})
+}
+
+// requestURL returns the URL r was sent to, query parameters included.
+func requestURL(r *resty.Request) string {
+	if r.RawRequest != nil && r.RawRequest.URL != nil {
+		return r.RawRequest.URL.String()
+	}
+
+	return r.URL
+}
+
+// requestHost returns the host r was sent to.
+func requestHost(r *resty.Request) string {
+	if r.RawRequest != nil && r.RawRequest.URL != nil {
+		return r.RawRequest.URL.Host
+	}
+
+	return ""
+}
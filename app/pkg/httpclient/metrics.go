@@ -0,0 +1,51 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package httpclient
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// noResponseCode is the code label of the requests failing without a response.
+const noResponseCode = "error"
+
+var (
+	// requestsTotal counts the outbound requests by upstream host, method and status code.
+	requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "http_client_requests_total",
+		Help: "Outbound HTTP requests by upstream host, method and status code.",
+	}, []string{"host", "method", "code"})
+
+	// requestDuration observes the latency of the outbound requests answered by the upstreams.
+	requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_client_request_duration_seconds",
+		Help:    "Latency of the outbound HTTP requests answered by the upstreams.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"host", "method"})
+)
+
+func init() {
+	prometheus.MustRegister(requestsTotal, requestDuration)
+}
+
+// observe records an outbound request in the metrics.
+//
+// Parameters:
+//   - host: The upstream host.
+//   - method: The HTTP method.
+//   - code: The status code, or 0 if the request got no response.
+//   - latency: The time until the response; ignored without a response.
+func observe(host, method string, code int, latency time.Duration) {
+	if code == 0 {
+		requestsTotal.WithLabelValues(host, method, noResponseCode).Inc()
+		return
+	}
+
+	requestsTotal.WithLabelValues(host, method, strconv.Itoa(code)).Inc()
+	requestDuration.WithLabelValues(host, method).Observe(latency.Seconds())
+}
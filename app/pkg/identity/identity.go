@@ -0,0 +1,76 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package identity carries the authenticated caller of a request through its context, so
+// handlers and services read who is calling instead of parsing the token again.
+package identity
+
+import "context"
+
+// identityKey is the context key of the identity.
+type identityKey struct{}
+
+// Identity is the authenticated caller of a request.
+//
+// The callers of the external API are apps: UserID is the app_id and UserName the app name of
+// the token, and apps have no roles.
+type Identity struct {
+	UserID       string   `json:"user_id"`
+	UserName     string   `json:"user_name"`
+	Roles        []string `json:"roles"`
+	IsSuperAdmin bool     `json:"is_super_admin"`
+}
+
+// HasRole reports whether the identity has role; a super admin has every role.
+//
+// Parameters:
+//   - role: The role name.
+//
+// Returns:
+//   - bool: true if the identity has the role.
+func (i Identity) HasRole(role string) bool {
+	if i.IsSuperAdmin {
+		return true
+	}
+
+	for _, r := range i.Roles {
+		if r == role {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WithIdentity returns a copy of ctx carrying id.
+//
+// Parameters:
+//   - ctx: The parent context.
+//   - id: The authenticated caller.
+//
+// Returns:
+//   - context.Context: The context carrying the identity.
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+// CurrentUser returns the identity carried by ctx.
+//
+// Parameters:
+//   - ctx: The context of the request, e.g. h.Context(c) in a handler.
+//
+// Returns:
+//   - Identity: The authenticated caller.
+//   - bool: false if the request isn't authenticated.
+//
+// Example:
+//
+//	user, ok := identity.CurrentUser(ctx)
+//	if !ok {
+//	    return e.New(e.ServerUnauthorized, nil)
+//	}
+func CurrentUser(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
@@ -0,0 +1,31 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package identity
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCurrentUser(t *testing.T) {
+	if _, ok := CurrentUser(context.Background()); ok {
+		t.Error("CurrentUser() ok = true without identity")
+	}
+
+	ctx := WithIdentity(context.Background(), Identity{UserID: "go-api-a", UserName: "a", Roles: []string{"auditor"}})
+
+	user, ok := CurrentUser(ctx)
+	if !ok || user.UserID != "go-api-a" {
+		t.Fatalf("CurrentUser() = %+v, %v, want go-api-a", user, ok)
+	}
+
+	if !user.HasRole("auditor") || user.HasRole("admin") {
+		t.Errorf("HasRole() of %v is wrong", user.Roles)
+	}
+
+	if !(Identity{IsSuperAdmin: true}).HasRole("admin") {
+		t.Error("HasRole() = false for a super admin")
+	}
+}
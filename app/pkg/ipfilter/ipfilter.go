@@ -0,0 +1,121 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package ipfilter restricts access by client IP with CIDR allow and deny lists.
+package ipfilter
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+)
+
+// Filter decides whether a client IP may access the service.
+type Filter struct {
+	allow []netip.Prefix
+	deny  []netip.Prefix
+}
+
+// New creates a Filter from CIDR lists. Single addresses, e.g. "10.0.0.1" or "::1", are accepted
+// as a prefix covering only that address.
+//
+// Parameters:
+//   - allow: The networks allowed; empty allows every address not denied.
+//   - deny: The networks denied; they take precedence over allow.
+//
+// Returns:
+//   - *Filter: The filter.
+//   - error: An error naming the first invalid entry.
+//
+// Example:
+//
+//	filter, err := ipfilter.New([]string{"203.0.113.0/24"}, []string{"203.0.113.66"})
+//	if err != nil {
+//	    return err
+//	}
+func New(allow, deny []string) (*Filter, error) {
+	var (
+		f   Filter
+		err error
+	)
+
+	if f.allow, err = ParsePrefixes(allow); err != nil {
+		return nil, fmt.Errorf("parse allow list failed: %w", err)
+	}
+
+	if f.deny, err = ParsePrefixes(deny); err != nil {
+		return nil, fmt.Errorf("parse deny list failed: %w", err)
+	}
+
+	return &f, nil
+}
+
+// Allowed reports whether ip may access the service. Deny takes precedence over allow, and an
+// invalid address is never allowed.
+//
+// Parameters:
+//   - ip: The client IP.
+//
+// Returns:
+//   - bool: true if the client is allowed.
+func (f *Filter) Allowed(ip netip.Addr) bool {
+	if !ip.IsValid() {
+		return false
+	}
+
+	ip = ip.Unmap()
+
+	if contains(f.deny, ip) {
+		return false
+	}
+
+	return len(f.allow) == 0 || contains(f.allow, ip)
+}
+
+// ParsePrefixes parses a list of CIDRs or single addresses.
+//
+// Parameters:
+//   - values: The CIDRs, e.g. "10.0.0.0/8", "2001:db8::/32" or "127.0.0.1".
+//
+// Returns:
+//   - []netip.Prefix: The parsed prefixes.
+//   - error: An error naming the first invalid entry.
+func ParsePrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+
+		if strings.Contains(value, "/") {
+			prefix, err := netip.ParsePrefix(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CIDR %q: %w", value, err)
+			}
+
+			prefixes = append(prefixes, prefix.Masked())
+			continue
+		}
+
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IP %q: %w", value, err)
+		}
+
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+
+	return prefixes, nil
+}
+
+// contains reports whether any of prefixes contains ip.
+func contains(prefixes []netip.Prefix, ip netip.Addr) bool {
+	ip = ip.Unmap()
+	for _, prefix := range prefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,71 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package ipfilter
+
+import (
+	"net/netip"
+	"testing"
+)
+
+func TestAllowed(t *testing.T) {
+	filter, err := New(
+		[]string{"10.0.0.0/8", "2001:db8::/32", "192.0.2.1"},
+		[]string{"10.0.0.0/24", "2001:db8:dead::/48"},
+	)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"10.1.2.3", true},
+		{"10.0.0.5", false}, // deny takes precedence over allow
+		{"192.0.2.1", true},
+		{"192.0.2.2", false},
+		{"::ffff:10.1.2.3", true}, // IPv4-mapped IPv6 matches the IPv4 lists
+		{"2001:db8::1", true},
+		{"2001:db8:dead::1", false},
+		{"2001:db9::1", false},
+	}
+
+	for _, tt := range tests {
+		if got := filter.Allowed(netip.MustParseAddr(tt.ip)); got != tt.want {
+			t.Errorf("Allowed(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+
+	if filter.Allowed(netip.Addr{}) {
+		t.Error("Allowed(invalid) = true, want false")
+	}
+}
+
+func TestAllowedWithoutAllowList(t *testing.T) {
+	filter, err := New(nil, []string{"203.0.113.0/24"})
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	if !filter.Allowed(netip.MustParseAddr("198.51.100.1")) {
+		t.Error("Allowed(198.51.100.1) = false, want true")
+	}
+
+	if filter.Allowed(netip.MustParseAddr("203.0.113.9")) {
+		t.Error("Allowed(203.0.113.9) = true, want false")
+	}
+}
+
+func TestNewInvalid(t *testing.T) {
+	for _, lists := range [][2][]string{
+		{{"10.0.0.0/33"}, nil},
+		{nil, {"not-an-ip"}},
+		{{"10.0.0.0/8/8"}, nil},
+	} {
+		if _, err := New(lists[0], lists[1]); err == nil {
+			t.Errorf("New(%v) error = nil, want an error", lists)
+		}
+	}
+}
@@ -7,13 +7,23 @@
 package jwt
 
 import (
-	"github.com/seakee/go-api/app/config"
+	"fmt"
 	"time"
 
+	"github.com/seakee/go-api/app/config"
+
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/seakee/go-api/app/model/auth"
 )
 
+const (
+	defaultIssuer = "go-api"        // Issuer used when none is configured
+	defaultLeeway = 5 * time.Second // Clock skew tolerated when none is configured
+
+	// SubjectApp is the "sub" claim of tokens issued to apps for service-to-service calls.
+	SubjectApp = "app"
+)
+
 // ServerClaims represents the custom claims structure for the JWT.
 // It extends the standard RegisteredClaims with application-specific fields.
 type ServerClaims struct {
@@ -41,6 +51,60 @@ type ServerClaims struct {
 //	    log.Fatalf("Failed to generate token: %v", err)
 //	}
 func GenerateAppToken(App *auth.App, expireTime time.Duration) (token string, err error) {
+	return generateToken(App, expireTime, optionsFromConfig())
+}
+
+// ParseAppAuth parses and validates a JWT token string.
+//
+// Parameters:
+//   - token: The JWT token string to be parsed and validated.
+//
+// Returns:
+//   - *ServerClaims: A pointer to the parsed ServerClaims if the token is valid.
+//   - error: An error if parsing fails or the token is invalid, nil otherwise.
+//
+// Example:
+//
+//	tokenString := "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
+//	claims, err := ParseAppAuth(tokenString)
+//	if err != nil {
+//	    log.Fatalf("Failed to parse token: %v", err)
+//	}
+//	fmt.Printf("App ID: %s\n", claims.AppID)
+func ParseAppAuth(token string) (*ServerClaims, error) {
+	return parseToken(token, optionsFromConfig())
+}
+
+// tokenOptions holds the signing secret and the scope claims of app tokens.
+type tokenOptions struct {
+	secret   []byte
+	issuer   string
+	audience string
+	leeway   time.Duration
+}
+
+// optionsFromConfig builds the token options from the system configuration.
+//
+// An empty issuer falls back to "go-api", the issuer of tokens issued before it was configurable,
+// and a zero leeway falls back to a few seconds; a negative leeway disables it.
+func optionsFromConfig() tokenOptions {
+	sys := config.Get().System
+
+	issuer := sys.JwtIssuer
+	if issuer == "" {
+		issuer = defaultIssuer
+	}
+
+	leeway := sys.JwtLeeway * time.Second
+	if leeway == 0 {
+		leeway = defaultLeeway
+	}
+
+	return tokenOptions{secret: []byte(sys.JwtSecret), issuer: issuer, audience: sys.JwtAudience, leeway: leeway}
+}
+
+// generateToken signs a token for App with the given options.
+func generateToken(App *auth.App, expireTime time.Duration, opts tokenOptions) (string, error) {
 	// Calculate the expiration time
 	expTime := time.Now().Add(expireTime * time.Second)
 
@@ -52,46 +116,55 @@ func GenerateAppToken(App *auth.App, expireTime time.Duration) (token string, er
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
-			Issuer:    "go-api",
+			NotBefore: jwt.NewNumericDate(time.Now()),
+			Issuer:    opts.issuer,
+			Subject:   SubjectApp,
 		},
 	}
 
+	if opts.audience != "" {
+		claims.Audience = jwt.ClaimStrings{opts.audience}
+	}
+
 	// Create a new token object, specifying signing method and the claims
 	tokenClaims := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
 	// Sign and get the complete encoded token as a string
-	jwtSecret := []byte(config.Get().System.JwtSecret)
-	return tokenClaims.SignedString(jwtSecret)
+	return tokenClaims.SignedString(opts.secret)
 }
 
-// ParseAppAuth parses and validates a JWT token string.
-//
-// Parameters:
-//   - token: The JWT token string to be parsed and validated.
-//
-// Returns:
-//   - *ServerClaims: A pointer to the parsed ServerClaims if the token is valid.
-//   - error: An error if parsing fails or the token is invalid, nil otherwise.
+// parseToken parses and validates token with the given options.
 //
-// Example:
-//
-//	tokenString := "eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9..."
-//	claims, err := ParseAppAuth(tokenString)
-//	if err != nil {
-//	    log.Fatalf("Failed to parse token: %v", err)
-//	}
-//	fmt.Printf("App ID: %s\n", claims.AppID)
-func ParseAppAuth(token string) (*ServerClaims, error) {
-	jwtSecret := []byte(config.Get().System.JwtSecret)
+// The issuer and audience are only validated when configured, so tokens issued
+// before they were set keep working during a rollout. The leeway applies to exp, nbf and iat.
+// Tokens with a subject other than SubjectApp are rejected; tokens issued before the subject
+// was set carry none and are accepted.
+func parseToken(token string, opts tokenOptions) (*ServerClaims, error) {
+	parserOpts := []jwt.ParserOption{jwt.WithIssuedAt()}
+	if opts.leeway > 0 {
+		parserOpts = append(parserOpts, jwt.WithLeeway(opts.leeway))
+	}
+
+	if opts.issuer != "" {
+		parserOpts = append(parserOpts, jwt.WithIssuer(opts.issuer))
+	}
+
+	if opts.audience != "" {
+		parserOpts = append(parserOpts, jwt.WithAudience(opts.audience))
+	}
 
 	// Parse the token
 	tokenClaims, err := jwt.ParseWithClaims(token, &ServerClaims{}, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
+		return opts.secret, nil
+	}, parserOpts...)
 
 	// Check if the token is valid
 	if tokenClaims != nil {
 		if claims, ok := tokenClaims.Claims.(*ServerClaims); ok && tokenClaims.Valid {
+			if claims.Subject != "" && claims.Subject != SubjectApp {
+				return nil, fmt.Errorf("%w: %s", jwt.ErrTokenInvalidSubject, claims.Subject)
+			}
+
 			return claims, nil
 		}
 	}
@@ -0,0 +1,120 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/seakee/go-api/app/model/auth"
+)
+
+func TestParseTokenAudience(t *testing.T) {
+	app := &auth.App{AppName: "test", AppID: "go-api-test"}
+	secret := []byte("secret")
+
+	token, err := generateToken(app, 60, tokenOptions{secret: secret, issuer: "go-api", audience: "go-api"})
+	if err != nil {
+		t.Fatalf("generateToken() error = %v", err)
+	}
+
+	claims, err := parseToken(token, tokenOptions{secret: secret, issuer: "go-api", audience: "go-api"})
+	if err != nil {
+		t.Fatalf("parseToken() error = %v", err)
+	}
+
+	if claims.AppID != app.AppID {
+		t.Errorf("parseToken() AppID = %q, want %q", claims.AppID, app.AppID)
+	}
+
+	_, err = parseToken(token, tokenOptions{secret: secret, issuer: "go-api", audience: "other-service"})
+	if !errors.Is(err, jwt.ErrTokenInvalidAudience) {
+		t.Errorf("parseToken() with wrong audience error = %v, want %v", err, jwt.ErrTokenInvalidAudience)
+	}
+
+	_, err = parseToken(token, tokenOptions{secret: secret, issuer: "other-issuer"})
+	if !errors.Is(err, jwt.ErrTokenInvalidIssuer) {
+		t.Errorf("parseToken() with wrong issuer error = %v, want %v", err, jwt.ErrTokenInvalidIssuer)
+	}
+}
+
+func TestParseTokenWithoutAudience(t *testing.T) {
+	app := &auth.App{AppName: "test", AppID: "go-api-test"}
+	secret := []byte("secret")
+
+	// Tokens issued before the audience was configured
+	token, err := generateToken(app, 60, tokenOptions{secret: secret, issuer: "go-api"})
+	if err != nil {
+		t.Fatalf("generateToken() error = %v", err)
+	}
+
+	if _, err = parseToken(token, tokenOptions{secret: secret, issuer: "go-api"}); err != nil {
+		t.Errorf("parseToken() without audience error = %v", err)
+	}
+
+	if _, err = parseToken(token, tokenOptions{secret: secret, audience: "go-api"}); !errors.Is(err, jwt.ErrTokenRequiredClaimMissing) {
+		t.Errorf("parseToken() with required audience error = %v, want %v", err, jwt.ErrTokenRequiredClaimMissing)
+	}
+}
+
+func TestParseTokenLeeway(t *testing.T) {
+	secret := []byte("secret")
+
+	// Token issued by a server whose clock runs two seconds ahead
+	issued := time.Now().Add(2 * time.Second)
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, ServerClaims{
+		AppID: "go-api-test",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(issued.Add(time.Minute)),
+			IssuedAt:  jwt.NewNumericDate(issued),
+			NotBefore: jwt.NewNumericDate(issued),
+		},
+	}).SignedString(secret)
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+
+	if _, err = parseToken(token, tokenOptions{secret: secret, leeway: 5 * time.Second}); err != nil {
+		t.Errorf("parseToken() with leeway error = %v", err)
+	}
+
+	if _, err = parseToken(token, tokenOptions{secret: secret}); err == nil {
+		t.Error("parseToken() without leeway accepted a token issued in the future")
+	}
+}
+
+func TestParseTokenSubject(t *testing.T) {
+	secret := []byte("secret")
+	opts := tokenOptions{secret: secret, issuer: "go-api"}
+
+	token, err := generateToken(&auth.App{AppName: "test", AppID: "go-api-test"}, 60, opts)
+	if err != nil {
+		t.Fatalf("generateToken() error = %v", err)
+	}
+
+	claims, err := parseToken(token, opts)
+	if err != nil {
+		t.Fatalf("parseToken() error = %v", err)
+	}
+
+	if claims.Subject != SubjectApp {
+		t.Errorf("parseToken() Subject = %q, want %q", claims.Subject, SubjectApp)
+	}
+
+	userToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.RegisteredClaims{
+		Subject:   "user",
+		Issuer:    "go-api",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	}).SignedString(secret)
+	if err != nil {
+		t.Fatalf("SignedString() error = %v", err)
+	}
+
+	if _, err = parseToken(userToken, opts); !errors.Is(err, jwt.ErrTokenInvalidSubject) {
+		t.Errorf("parseToken() with user subject error = %v, want %v", err, jwt.ErrTokenInvalidSubject)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package lang wraps the i18n manager so error codes never render with a blank message.
+package lang
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seakee/go-api/app/pkg/e"
+	"github.com/sk-pkg/i18n"
+)
+
+// Manager is an i18n.Manager falling back to e.FallbackMessage for untranslated codes.
+type Manager struct {
+	*i18n.Manager
+}
+
+// New wraps an i18n manager and fills the messages its language files lack.
+//
+// Every error code of the e package missing from a language gets the message of the default
+// language, then e.FallbackMessage. The languages are only written here, before the manager
+// serves requests.
+//
+// Parameters:
+//   - manager: *i18n.Manager - The manager loaded from the language files.
+//   - defaultLang: string - The default language, e.g. "zh-CN".
+//
+// Returns:
+//   - *Manager: The wrapped manager.
+//
+// Example:
+//
+//	manager, err := i18n.New(i18n.WithLangDir(langDir))
+//	if err != nil {
+//	    return err
+//	}
+//	a.I18n = lang.New(manager, a.Config.System.DefaultLang)
+func New(manager *i18n.Manager, defaultLang string) *Manager {
+	defaults := manager.LangList[defaultLang]
+
+	for _, messages := range manager.LangList {
+		for _, code := range e.Codes() {
+			key := strconv.Itoa(code)
+			if messages[key] != "" {
+				continue
+			}
+
+			if msg := defaults[key]; msg != "" {
+				messages[key] = msg
+				continue
+			}
+
+			messages[key] = e.FallbackMessage(code)
+		}
+	}
+
+	return &Manager{Manager: manager}
+}
+
+// JSON renders the standard envelope like i18n.Manager.JSON.
+//
+// A code without a message in one of the languages, e.g. a code missing from the e package,
+// is rendered with e.FallbackMessage instead of a blank or bare numeric message.
+//
+// Parameters:
+//   - c: *gin.Context - The gin context to write to.
+//   - code: int - The error code.
+//   - data: interface{} - The response data.
+//   - err: error - The underlying error, reported in the trace in debug mode.
+func (m *Manager) JSON(c *gin.Context, code int, data interface{}, err error) {
+	key := strconv.Itoa(code)
+	if m.translated(key) {
+		m.Manager.JSON(c, code, data, err)
+		return
+	}
+
+	// Render the envelope aside and patch its message before writing it
+	writer := &captureWriter{ResponseWriter: c.Writer, status: http.StatusOK}
+	c.Writer = writer
+	m.Manager.JSON(c, code, data, err)
+	c.Writer = writer.ResponseWriter
+
+	body := writer.body.Bytes()
+
+	var envelope map[string]json.RawMessage
+	if json.Unmarshal(body, &envelope) == nil {
+		var msg string
+		_ = json.Unmarshal(envelope["msg"], &msg)
+
+		if msg == "" || msg == key {
+			envelope["msg"], _ = json.Marshal(e.FallbackMessage(code))
+			if patched, marshalErr := json.Marshal(envelope); marshalErr == nil {
+				body = patched
+			}
+		}
+	}
+
+	c.Writer.WriteHeader(writer.status)
+	_, _ = c.Writer.Write(body)
+}
+
+// translated reports whether every language has a message for key.
+func (m *Manager) translated(key string) bool {
+	for _, messages := range m.LangList {
+		if messages[key] == "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// captureWriter keeps the status and body written to the response aside.
+type captureWriter struct {
+	gin.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+// WriteHeader records the status.
+func (w *captureWriter) WriteHeader(status int) {
+	w.status = status
+}
+
+// WriteHeaderNow does nothing; the status is written with the patched body.
+func (w *captureWriter) WriteHeaderNow() {}
+
+// Write buffers b.
+func (w *captureWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// WriteString buffers s.
+func (w *captureWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
@@ -0,0 +1,69 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package lang
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/seakee/go-api/app/pkg/e"
+	"github.com/sk-pkg/i18n"
+)
+
+func TestManagerJSONFallback(t *testing.T) {
+	// Language files translating SUCCESS only
+	dir := t.TempDir()
+	for _, name := range []string{"en-US.json", "zh-CN.json"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(`{"0": "ok"}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	manager, err := i18n.New(i18n.WithLangDir(dir))
+	if err != nil {
+		t.Fatalf("i18n.New() error = %v", err)
+	}
+
+	m := New(manager, "en-US")
+
+	tests := []struct {
+		name string
+		code int
+		want string
+	}{
+		{name: "translated", code: e.SUCCESS, want: "ok"},
+		{name: "untranslated", code: e.RequestTimeout, want: "Request timed out (code 504)"},
+		{name: "unknown", code: 12345, want: "Unknown error (code 12345)"},
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+			m.JSON(c, tt.code, nil, nil)
+
+			var envelope struct {
+				Code int    `json:"code"`
+				Msg  string `json:"msg"`
+			}
+			if err := json.Unmarshal(w.Body.Bytes(), &envelope); err != nil {
+				t.Fatalf("body %s is not an envelope: %v", w.Body.String(), err)
+			}
+
+			if envelope.Code != tt.code || envelope.Msg != tt.want {
+				t.Errorf("JSON(%d) = %d %q, want %d %q", tt.code, envelope.Code, envelope.Msg, tt.code, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package larkcard builds Lark (Feishu) interactive cards and sends them through the notify manager,
+// so callers never assemble card JSON by hand.
+package larkcard
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Color is the header color template of a card.
+type Color string
+
+// Header colors supported by Lark cards.
+const (
+	Blue   Color = "blue"
+	Green  Color = "green"
+	Orange Color = "orange"
+	Red    Color = "red"
+	Yellow Color = "yellow"
+	Grey   Color = "grey"
+)
+
+// ButtonType is the visual style of an action button.
+type ButtonType string
+
+// Button styles supported by Lark cards.
+const (
+	ButtonDefault ButtonType = "default"
+	ButtonPrimary ButtonType = "primary"
+	ButtonDanger  ButtonType = "danger"
+)
+
+// field is a key-value pair shown in the card body.
+type field struct {
+	key   string
+	value string
+}
+
+// button is an action button opening a URL.
+type button struct {
+	text    string
+	url     string
+	btnType ButtonType
+}
+
+// LarkCard is a typed builder of a Lark interactive card.
+//
+// A card has a colored header with a title, an optional markdown text, key-value fields
+// rendered two per row, and action buttons. The zero color is Blue.
+type LarkCard struct {
+	title   string
+	color   Color
+	text    string
+	fields  []field
+	buttons []button
+}
+
+// New creates a card with the given title.
+//
+// Parameters:
+//   - title: string - The title shown in the card header.
+//
+// Returns:
+//   - *LarkCard: A new card builder.
+//
+// Example:
+//
+//	card := larkcard.New("Login alert").
+//	    Color(larkcard.Orange).
+//	    Field("App ID", appID).
+//	    Field("IP", c.ClientIP()).
+//	    Button("Review", "https://admin.example.com/apps/"+appID, larkcard.ButtonPrimary)
+func New(title string) *LarkCard {
+	return &LarkCard{title: title, color: Blue}
+}
+
+// Title returns the title of the card.
+func (c *LarkCard) Title() string {
+	return c.title
+}
+
+// Clone returns a copy of the card that can be changed without changing c.
+func (c *LarkCard) Clone() *LarkCard {
+	clone := *c
+	clone.fields = append([]field(nil), c.fields...)
+	clone.buttons = append([]button(nil), c.buttons...)
+
+	return &clone
+}
+
+// Color sets the header color of the card.
+func (c *LarkCard) Color(color Color) *LarkCard {
+	if color != "" {
+		c.color = color
+	}
+
+	return c
+}
+
+// Text sets the markdown text shown above the fields.
+func (c *LarkCard) Text(text string) *LarkCard {
+	c.text = text
+	return c
+}
+
+// Field appends a key-value field; fields are rendered two per row in the order added.
+func (c *LarkCard) Field(key, value string) *LarkCard {
+	c.fields = append(c.fields, field{key: key, value: value})
+	return c
+}
+
+// Button appends an action button opening url.
+func (c *LarkCard) Button(text, url string, btnType ButtonType) *LarkCard {
+	if btnType == "" {
+		btnType = ButtonDefault
+	}
+
+	c.buttons = append(c.buttons, button{text: text, url: url, btnType: btnType})
+
+	return c
+}
+
+// Build returns the card content in the format expected by the Lark message API.
+//
+// Returns:
+//   - map[string]any: The card, ready to be used as the content of an "interactive" message.
+func (c *LarkCard) Build() map[string]any {
+	elements := make([]any, 0, 3)
+
+	if c.text != "" {
+		elements = append(elements, map[string]any{
+			"tag":     "markdown",
+			"content": c.text,
+		})
+	}
+
+	if len(c.fields) > 0 {
+		fields := make([]any, len(c.fields))
+		for i, f := range c.fields {
+			fields[i] = map[string]any{
+				"is_short": true,
+				"text": map[string]any{
+					"tag":     "lark_md",
+					"content": fmt.Sprintf("**%s**\n%s", f.key, f.value),
+				},
+			}
+		}
+
+		elements = append(elements, map[string]any{
+			"tag":    "div",
+			"fields": fields,
+		})
+	}
+
+	if len(c.buttons) > 0 {
+		actions := make([]any, len(c.buttons))
+		for i, b := range c.buttons {
+			actions[i] = map[string]any{
+				"tag":  "button",
+				"text": map[string]any{"tag": "plain_text", "content": b.text},
+				"url":  b.url,
+				"type": string(b.btnType),
+			}
+		}
+
+		elements = append(elements, map[string]any{
+			"tag":     "action",
+			"actions": actions,
+		})
+	}
+
+	return map[string]any{
+		"config": map[string]any{"wide_screen_mode": true},
+		"header": map[string]any{
+			"title":    map[string]any{"tag": "plain_text", "content": c.title},
+			"template": string(c.color),
+		},
+		"elements": elements,
+	}
+}
+
+// String renders the card as plain text, used when the card itself cannot be sent.
+func (c *LarkCard) String() string {
+	var b strings.Builder
+
+	b.WriteString(c.title)
+
+	if c.text != "" {
+		b.WriteString("\n")
+		b.WriteString(c.text)
+	}
+
+	for _, f := range c.fields {
+		fmt.Fprintf(&b, "\n%s: %s", f.key, f.value)
+	}
+
+	for _, btn := range c.buttons {
+		fmt.Fprintf(&b, "\n%s: %s", btn.text, btn.url)
+	}
+
+	return b.String()
+}
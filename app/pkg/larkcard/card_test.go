@@ -0,0 +1,127 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package larkcard
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/seakee/go-api/app/config"
+	"github.com/sk-pkg/notify/lark"
+)
+
+// TestLarkCard_Build checks the header, fields and buttons of a built card.
+func TestLarkCard_Build(t *testing.T) {
+	card := New("Login alert").
+		Color(Orange).
+		Text("A new login was detected").
+		Field("App ID", "go-api").
+		Field("IP", "127.0.0.1").
+		Button("Review", "https://example.com", ButtonPrimary).
+		Build()
+
+	header := card["header"].(map[string]any)
+	if header["template"] != "orange" {
+		t.Errorf("header template = %v, want orange", header["template"])
+	}
+
+	if title := header["title"].(map[string]any)["content"]; title != "Login alert" {
+		t.Errorf("header title = %v, want Login alert", title)
+	}
+
+	elements := card["elements"].([]any)
+	if len(elements) != 3 {
+		t.Fatalf("len(elements) = %d, want 3", len(elements))
+	}
+
+	fields := elements[1].(map[string]any)["fields"].([]any)
+	if len(fields) != 2 {
+		t.Fatalf("len(fields) = %d, want 2", len(fields))
+	}
+
+	content := fields[1].(map[string]any)["text"].(map[string]any)["content"]
+	if content != "**IP**\n127.0.0.1" {
+		t.Errorf("field content = %q, want %q", content, "**IP**\n127.0.0.1")
+	}
+
+	action := elements[2].(map[string]any)["actions"].([]any)[0].(map[string]any)
+	if action["url"] != "https://example.com" || action["type"] != "primary" {
+		t.Errorf("button = %v, want primary button to https://example.com", action)
+	}
+}
+
+// TestLarkCard_String checks the plain text rendering used as fallback.
+func TestLarkCard_String(t *testing.T) {
+	got := New("Panic").Text("boom").Field("Path", "/ping").Button("Logs", "https://example.com", "").String()
+	want := "Panic\nboom\nPath: /ping\nLogs: https://example.com"
+
+	if got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+// fakeNotify records submitted messages and fails interactive ones when failCard is set.
+type fakeNotify struct {
+	lark.Notify
+	failCard bool
+	messages []lark.Message
+}
+
+func (f *fakeNotify) SubmitMessage(m lark.Message) (string, error) {
+	if f.failCard && m.MsgType == "interactive" {
+		return "", errors.New("queue closed")
+	}
+
+	f.messages = append(f.messages, m)
+
+	return "msg-id", nil
+}
+
+// TestSender_Send checks the per alert type configuration and the text fallback.
+func TestSender_Send(t *testing.T) {
+	cards := map[string]config.LarkCard{
+		"panic_report": {Enable: true, Color: "red", SendChannelName: "bot_1"},
+		"login_alert":  {Enable: false},
+	}
+
+	tests := []struct {
+		name      string
+		alertType string
+		failCard  bool
+		wantType  string
+	}{
+		{name: "configured card", alertType: "panic_report", wantType: "interactive"},
+		{name: "unconfigured card", alertType: "job_failure", wantType: "interactive"},
+		{name: "card disabled", alertType: "login_alert", wantType: "text"},
+		{name: "card fails", alertType: "panic_report", failCard: true, wantType: "text"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := &fakeNotify{failCard: tt.failCard}
+
+			if _, err := NewSender(n, cards).Send(tt.alertType, New("Alert")); err != nil {
+				t.Fatalf("Send() error = %v", err)
+			}
+
+			if len(n.messages) != 1 {
+				t.Fatalf("submitted %d messages, want 1", len(n.messages))
+			}
+
+			m := n.messages[0]
+			if m.MsgType != tt.wantType {
+				t.Errorf("MsgType = %s, want %s", m.MsgType, tt.wantType)
+			}
+
+			if m.SendChannelName != cards[tt.alertType].SendChannelName {
+				t.Errorf("SendChannelName = %s, want %s", m.SendChannelName, cards[tt.alertType].SendChannelName)
+			}
+		})
+	}
+
+	if _, err := NewSender(nil, cards).Send("panic_report", New("Alert")); !errors.Is(err, ErrLarkDisabled) {
+		t.Errorf("Send() without notifier error = %v, want ErrLarkDisabled", err)
+	}
+}
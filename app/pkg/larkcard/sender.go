@@ -0,0 +1,108 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package larkcard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/seakee/go-api/app/config"
+	"github.com/sk-pkg/notify/lark"
+)
+
+// ErrLarkDisabled is returned when sending through a Sender without a Lark notifier.
+var ErrLarkDisabled = errors.New("lark notify is not enabled")
+
+// Sender sends cards through the Lark notifier, configured per alert type.
+type Sender struct {
+	notify lark.Notify
+	cards  map[string]config.LarkCard
+}
+
+// NewSender creates a Sender.
+//
+// Parameters:
+//   - notify: lark.Notify - The Lark notifier, usually notify.Manager.Lark; nil disables sending.
+//   - cards: map[string]config.LarkCard - The card configuration per alert type.
+//
+// Returns:
+//   - *Sender: A new Sender instance.
+//
+// Example:
+//
+//	sender := larkcard.NewSender(a.Notify.Lark, a.Config.Notify.Lark.Cards)
+//	_, err := sender.Send("login_alert", larkcard.New("Login alert").Field("IP", ip))
+func NewSender(notify lark.Notify, cards map[string]config.LarkCard) *Sender {
+	return &Sender{notify: notify, cards: cards}
+}
+
+// Send sends card as the given alert type.
+//
+// The configuration of alertType may override the header color, the bot or app to send
+// through and the receiver, or turn the card into plain text. Alert types without
+// configuration are sent as cards through the default channel. If the card cannot be
+// encoded or submitted, its text rendering is sent instead.
+//
+// Delivery is asynchronous: a nil error means the message was queued, not delivered.
+//
+// Parameters:
+//   - alertType: string - The alert type, the key of the card configuration.
+//   - card: *LarkCard - The card to send.
+//
+// Returns:
+//   - string: The message ID.
+//   - error: ErrLarkDisabled, or an error if neither the card nor its text could be submitted.
+func (s *Sender) Send(alertType string, card *LarkCard) (string, error) {
+	if s == nil || s.notify == nil {
+		return "", ErrLarkDisabled
+	}
+
+	cfg, ok := s.cards[alertType]
+	if ok && !cfg.Enable {
+		return s.sendText(cfg, card)
+	}
+
+	card.Color(Color(cfg.Color))
+
+	msgID, err := s.sendCard(cfg, card)
+	if err == nil {
+		return msgID, nil
+	}
+
+	msgID, textErr := s.sendText(cfg, card)
+	if textErr != nil {
+		return msgID, fmt.Errorf("send lark card failed: %w, text fallback failed: %w", err, textErr)
+	}
+
+	return msgID, nil
+}
+
+// sendCard submits card as an interactive message.
+func (s *Sender) sendCard(cfg config.LarkCard, card *LarkCard) (string, error) {
+	content := card.Build()
+
+	// Encode up front so a bad card falls back to text instead of failing in the background
+	if _, err := json.Marshal(content); err != nil {
+		return "", fmt.Errorf("marshal lark card failed: %w", err)
+	}
+
+	return s.notify.SubmitMessage(lark.Message{
+		SendChannelName: cfg.SendChannelName,
+		SendTo:          cfg.SendTo,
+		MsgType:         "interactive",
+		Content:         content,
+	})
+}
+
+// sendText submits the text rendering of card.
+func (s *Sender) sendText(cfg config.LarkCard, card *LarkCard) (string, error) {
+	return s.notify.SubmitMessage(lark.Message{
+		SendChannelName: cfg.SendChannelName,
+		SendTo:          cfg.SendTo,
+		MsgType:         "text",
+		Content:         card.String(),
+	})
+}
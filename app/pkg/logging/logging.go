@@ -0,0 +1,163 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package logging wraps logger.Manager for the high-volume and sensitive log paths: lines are
+// sampled per level and the values of sensitive fields are masked before they are emitted.
+package logging
+
+import (
+	"context"
+	"strings"
+	"sync/atomic"
+
+	"github.com/seakee/go-api/app/config"
+	"github.com/seakee/go-api/app/pkg/sanitize"
+	"github.com/sk-pkg/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// sampledLevels are the levels a sampling rate can be configured for.
+var sampledLevels = map[string]zapcore.Level{
+	"debug": zapcore.DebugLevel,
+	"info":  zapcore.InfoLevel,
+	"warn":  zapcore.WarnLevel,
+	"error": zapcore.ErrorLevel,
+}
+
+// Logger logs through a logger.Manager with sampling and field redaction.
+type Logger struct {
+	manager *logger.Manager
+	redact  map[string]struct{}
+	rates   map[zapcore.Level]uint64
+	counts  map[zapcore.Level]*atomic.Uint64
+}
+
+// New creates a Logger.
+//
+// Parameters:
+//   - m: *logger.Manager - The logger the lines are emitted through.
+//   - cfg: config.LogConfig - The log configuration: Sampling keeps 1 in N lines per level and
+//     RedactFields lists the masked field keys, sanitize.DefaultFields when empty.
+//
+// Returns:
+//   - *Logger: A new Logger instance.
+//
+// Example:
+//
+//	log := logging.New(appCtx.Logger, appCtx.Config.Log)
+//	log.Info(ctx, "Token issued", zap.String("app_id", appID), zap.String("token", token))
+func New(m *logger.Manager, cfg config.LogConfig) *Logger {
+	fields := cfg.RedactFields
+	if len(fields) == 0 {
+		fields = sanitize.DefaultFields
+	}
+
+	l := &Logger{
+		// Skip the frame of the wrapper, so the caller of Logger is reported
+		manager: m.CallerSkipMode(2),
+		redact:  make(map[string]struct{}, len(fields)),
+		rates:   make(map[zapcore.Level]uint64),
+		counts:  make(map[zapcore.Level]*atomic.Uint64),
+	}
+
+	for _, field := range fields {
+		l.redact[strings.ToLower(field)] = struct{}{}
+	}
+
+	for name, rate := range cfg.Sampling {
+		level, ok := sampledLevels[strings.ToLower(name)]
+		if !ok || rate <= 1 {
+			continue
+		}
+
+		l.rates[level] = uint64(rate)
+		l.counts[level] = new(atomic.Uint64)
+	}
+
+	return l
+}
+
+// Debug logs a message at debug level.
+//
+// Parameters:
+//   - ctx: context.Context - The context carrying the trace ID.
+//   - msg: string - The message.
+//   - fields: ...zap.Field - The fields; sensitive ones are masked.
+func (l *Logger) Debug(ctx context.Context, msg string, fields ...zap.Field) {
+	if l.sampled(zapcore.DebugLevel) {
+		l.manager.Debug(ctx, msg, l.redactFields(fields)...)
+	}
+}
+
+// Info logs a message at info level.
+//
+// Parameters:
+//   - ctx: context.Context - The context carrying the trace ID.
+//   - msg: string - The message.
+//   - fields: ...zap.Field - The fields; sensitive ones are masked.
+func (l *Logger) Info(ctx context.Context, msg string, fields ...zap.Field) {
+	if l.sampled(zapcore.InfoLevel) {
+		l.manager.Info(ctx, msg, l.redactFields(fields)...)
+	}
+}
+
+// Warn logs a message at warn level.
+//
+// Parameters:
+//   - ctx: context.Context - The context carrying the trace ID.
+//   - msg: string - The message.
+//   - fields: ...zap.Field - The fields; sensitive ones are masked.
+func (l *Logger) Warn(ctx context.Context, msg string, fields ...zap.Field) {
+	if l.sampled(zapcore.WarnLevel) {
+		l.manager.Warn(ctx, msg, l.redactFields(fields)...)
+	}
+}
+
+// Error logs a message at error level.
+//
+// Parameters:
+//   - ctx: context.Context - The context carrying the trace ID.
+//   - msg: string - The message.
+//   - fields: ...zap.Field - The fields; sensitive ones are masked.
+func (l *Logger) Error(ctx context.Context, msg string, fields ...zap.Field) {
+	if l.sampled(zapcore.ErrorLevel) {
+		l.manager.Error(ctx, msg, l.redactFields(fields)...)
+	}
+}
+
+// sampled reports whether the next line of level is emitted: the first of every N when a rate
+// is configured, every line otherwise.
+func (l *Logger) sampled(level zapcore.Level) bool {
+	rate, ok := l.rates[level]
+	if !ok {
+		return true
+	}
+
+	return (l.counts[level].Add(1)-1)%rate == 0
+}
+
+// redactFields returns fields with the values of the sensitive keys masked; fields is not modified.
+func (l *Logger) redactFields(fields []zap.Field) []zap.Field {
+	var redacted []zap.Field
+
+	for i, field := range fields {
+		if _, ok := l.redact[strings.ToLower(field.Key)]; !ok {
+			continue
+		}
+
+		if redacted == nil {
+			redacted = make([]zap.Field, len(fields))
+			copy(redacted, fields)
+		}
+
+		redacted[i] = zap.String(field.Key, sanitize.Mask)
+	}
+
+	if redacted == nil {
+		return fields
+	}
+
+	return redacted
+}
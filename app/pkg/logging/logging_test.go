@@ -0,0 +1,57 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package logging
+
+import (
+	"context"
+	"testing"
+
+	"github.com/seakee/go-api/app/config"
+	"github.com/seakee/go-api/app/pkg/sanitize"
+	"github.com/sk-pkg/logger"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+// newObserved returns a Logger writing to the returned observed logs.
+func newObserved(cfg config.LogConfig) (*Logger, *observer.ObservedLogs) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	return New(&logger.Manager{Zap: zap.New(core)}, cfg), logs
+}
+
+func TestLoggerSampling(t *testing.T) {
+	l, logs := newObserved(config.LogConfig{Sampling: map[string]int{"debug": 3, "info": 1}})
+	ctx := context.Background()
+
+	for i := 0; i < 7; i++ {
+		l.Debug(ctx, "debug line")
+		l.Info(ctx, "info line")
+	}
+
+	if got := logs.FilterMessage("debug line").Len(); got != 3 {
+		t.Errorf("debug lines = %d, want 3 of 7", got)
+	}
+
+	if got := logs.FilterMessage("info line").Len(); got != 7 {
+		t.Errorf("info lines = %d, want 7", got)
+	}
+}
+
+func TestLoggerRedaction(t *testing.T) {
+	l, logs := newObserved(config.LogConfig{})
+
+	fields := []zap.Field{zap.String("app_id", "go-api-a"), zap.String("App_Secret", "s3cr3t")}
+	l.Warn(context.Background(), "auth", fields...)
+
+	got := logs.All()[0].ContextMap()
+	if got["App_Secret"] != sanitize.Mask || got["app_id"] != "go-api-a" {
+		t.Errorf("fields = %v, want App_Secret masked and app_id kept", got)
+	}
+
+	if fields[1].String != "s3cr3t" {
+		t.Error("the fields of the caller were modified")
+	}
+}
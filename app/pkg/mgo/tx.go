@@ -0,0 +1,88 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package mgo provides helpers for working with MongoDB through qmgo.
+package mgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/qiniu/qmgo"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// ErrTxNotSupported is returned by WithMongoTx when the server cannot run transactions,
+// i.e. it is a standalone server or older than MongoDB 4.0.
+var ErrTxNotSupported = errors.New("mongo transactions require a replica set or sharded cluster (MongoDB >= 4.0)")
+
+// txSupported caches the topology check of each client.
+var txSupported sync.Map // map[*qmgo.Client]bool
+
+// WithMongoTx runs fn in a MongoDB transaction.
+//
+// Every operation in fn must use sessCtx as its context to take part in the transaction.
+// The transaction is committed when fn returns nil and aborted otherwise. fn may be retried
+// on transient transaction errors, so it must be idempotent.
+//
+// Parameters:
+//   - ctx: Context for the transaction
+//   - cli: The qmgo client owning the databases used in fn
+//   - fn: The operations to run atomically
+//
+// Returns:
+//   - error: ErrTxNotSupported on servers without transaction support, the error returned by fn,
+//     or an error if the transaction could not be committed
+//
+// Example:
+//
+//	err := mgo.WithMongoTx(ctx, cli, func(sessCtx context.Context) error {
+//	    if _, err := record.Create(sessCtx, db); err != nil {
+//	        return err
+//	    }
+//	    return app.Updates(sessCtx, db, bson.M{"status": 0})
+//	})
+func WithMongoTx(ctx context.Context, cli *qmgo.Client, fn func(sessCtx context.Context) error) error {
+	supported, err := transactionSupported(ctx, cli)
+	if err != nil {
+		return err
+	}
+
+	if !supported {
+		return ErrTxNotSupported
+	}
+
+	_, err = cli.DoTransaction(ctx, func(sessCtx context.Context) (interface{}, error) {
+		return nil, fn(sessCtx)
+	})
+	if errors.Is(err, qmgo.ErrTransactionNotSupported) {
+		return ErrTxNotSupported
+	}
+
+	return err
+}
+
+// transactionSupported reports whether the server behind cli is a replica set member or a mongos router.
+//
+// Standalone servers accept the session but fail the first operation inside the transaction
+// with an obscure error, so the topology is checked up front and cached per client.
+func transactionSupported(ctx context.Context, cli *qmgo.Client) (bool, error) {
+	if supported, ok := txSupported.Load(cli); ok {
+		return supported.(bool), nil
+	}
+
+	var reply bson.M
+	if err := cli.Database("admin").RunCommand(ctx, bson.D{{Key: "isMaster", Value: 1}}).Decode(&reply); err != nil {
+		return false, fmt.Errorf("check mongo topology failed: %w", err)
+	}
+
+	_, replicaSet := reply["setName"]
+	supported := replicaSet || reply["msg"] == "isdbgrid"
+
+	txSupported.Store(cli, supported)
+
+	return supported, nil
+}
@@ -0,0 +1,309 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package query builds gorm list queries from client-supplied filters.
+//
+// Clients may only filter on the fields whitelisted by the endpoint; the whitelist maps the API
+// field names to the real column names, so no client input is ever interpolated into SQL.
+package query
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Operator is a comparison applied by a filter.
+type Operator string
+
+// Supported operators.
+const (
+	OpEq      Operator = "eq"      // column = value
+	OpLike    Operator = "like"    // column LIKE %value%, with wildcards in value escaped
+	OpIn      Operator = "in"      // column IN (values...)
+	OpGte     Operator = "gte"     // column >= value
+	OpLte     Operator = "lte"     // column <= value
+	OpBetween Operator = "between" // column BETWEEN value[0] AND value[1]
+)
+
+var (
+	// ErrUnknownField is returned for a field missing from the whitelist.
+	ErrUnknownField = errors.New("unknown field")
+	// ErrUnknownOperator is returned for an unsupported operator.
+	ErrUnknownOperator = errors.New("unknown operator")
+	// ErrInvalidValue is returned for a value that does not suit the operator.
+	ErrInvalidValue = errors.New("invalid value")
+	// ErrInvalidPage is returned by Paginate for a page or size less than 1.
+	ErrInvalidPage = errors.New("invalid page")
+)
+
+// Filter is a condition on one field.
+//
+// Value is a scalar for eq, like, gte and lte. For in it is a slice or a comma-separated string,
+// and for between a slice of two items or a string "from,to".
+type Filter struct {
+	Op    Operator    `json:"op" form:"op"`
+	Value interface{} `json:"value" form:"value"`
+}
+
+// FieldError reports the field whose filter was rejected.
+type FieldError struct {
+	Field string // The API field name
+	Err   error  // ErrUnknownField, ErrUnknownOperator or ErrInvalidValue
+}
+
+// Error implements the error interface.
+func (e *FieldError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Field, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// Condition is a parsed filter, ready to be passed to gorm's Where.
+type Condition struct {
+	Query string
+	Args  []interface{}
+}
+
+// Builder turns filters into gorm conditions, restricted to a whitelist of fields.
+type Builder struct {
+	fields     map[string]string
+	conditions []Condition
+}
+
+// NewBuilder creates a Builder accepting the given fields.
+//
+// Parameters:
+//   - fields: Maps the API field names to the column names, e.g. {"created_at": "created_at", "name": "app_name"}.
+//     The column names are interpolated into SQL and must never come from user input.
+//
+// Returns:
+//   - *Builder: The builder.
+//
+// Example:
+//
+//	b := query.NewBuilder(map[string]string{"app_name": "app_name", "created_at": "created_at"})
+//	if err := b.Parse(params.Filters); err != nil {
+//	    return err
+//	}
+//	apps, total, err := query.Paginate[auth.App](ctx, db, b, page, size)
+func NewBuilder(fields map[string]string) *Builder {
+	return &Builder{fields: fields}
+}
+
+// Parse validates filters and adds them to the builder.
+//
+// Parameters:
+//   - filters: The filters keyed by API field name.
+//
+// Returns:
+//   - error: A *FieldError for the first rejected filter, in field name order; nothing is added then.
+func (b *Builder) Parse(filters map[string]Filter) error {
+	names := make([]string, 0, len(filters))
+	for name := range filters {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	conditions := make([]Condition, 0, len(filters))
+	for _, name := range names {
+		condition, err := b.condition(name, filters[name])
+		if err != nil {
+			return &FieldError{Field: name, Err: err}
+		}
+
+		conditions = append(conditions, condition)
+	}
+
+	b.conditions = append(b.conditions, conditions...)
+
+	return nil
+}
+
+// Where adds a single filter to the builder.
+//
+// Parameters:
+//   - field: The API field name.
+//   - op: The operator.
+//   - value: The value, see Filter.
+//
+// Returns:
+//   - error: A *FieldError if the filter is rejected.
+func (b *Builder) Where(field string, op Operator, value interface{}) error {
+	return b.Parse(map[string]Filter{field: {Op: op, Value: value}})
+}
+
+// Conditions returns the conditions added so far.
+func (b *Builder) Conditions() []Condition {
+	return b.conditions
+}
+
+// Apply adds the conditions to db.
+//
+// Parameters:
+//   - db: The query to restrict.
+//
+// Returns:
+//   - *gorm.DB: The restricted query.
+func (b *Builder) Apply(db *gorm.DB) *gorm.DB {
+	for _, condition := range b.conditions {
+		db = db.Where(condition.Query, condition.Args...)
+	}
+
+	return db
+}
+
+// Paginate returns a page of the T rows matching the builder, and their total count.
+//
+// Parameters:
+//   - ctx: context.Context for managing request-scoped values, cancellation signals, and deadlines.
+//   - db: *gorm.DB database connection; further scopes such as ordering may already be applied.
+//   - b: The builder holding the filters.
+//   - page: page number for pagination (1-based); normalize it first, e.g. with PageParams.NormalizeFor.
+//   - size: number of rows per page.
+//
+// Returns:
+//   - []T: The rows of the page.
+//   - int64: The total count of matching rows.
+//   - error: ErrInvalidPage if page or size is less than 1, an error if the query fails, otherwise nil.
+func Paginate[T any](ctx context.Context, db *gorm.DB, b *Builder, page, size int) ([]T, int64, error) {
+	if page < 1 || size < 1 {
+		return nil, 0, ErrInvalidPage
+	}
+
+	var (
+		rows  []T
+		total int64
+		model T
+	)
+
+	tx := b.Apply(db.WithContext(ctx).Model(&model))
+
+	if err := tx.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("count failed: %w", err)
+	}
+
+	if total == 0 {
+		return []T{}, 0, nil
+	}
+
+	if err := tx.Offset((page - 1) * size).Limit(size).Find(&rows).Error; err != nil {
+		return nil, 0, fmt.Errorf("find with pagination failed: %w", err)
+	}
+
+	return rows, total, nil
+}
+
+// condition validates a filter and converts it into a Condition.
+func (b *Builder) condition(field string, filter Filter) (Condition, error) {
+	column, ok := b.fields[field]
+	if !ok {
+		return Condition{}, ErrUnknownField
+	}
+
+	switch filter.Op {
+	case OpEq, OpGte, OpLte:
+		if !isScalar(filter.Value) {
+			return Condition{}, ErrInvalidValue
+		}
+
+		return Condition{Query: column + " " + sqlOperators[filter.Op] + " ?", Args: []interface{}{filter.Value}}, nil
+	case OpLike:
+		keyword, ok := filter.Value.(string)
+		if !ok || keyword == "" {
+			return Condition{}, ErrInvalidValue
+		}
+
+		return Condition{Query: column + " LIKE ?", Args: []interface{}{"%" + EscapeLike(keyword) + "%"}}, nil
+	case OpIn:
+		values := listValues(filter.Value)
+		if len(values) == 0 {
+			return Condition{}, ErrInvalidValue
+		}
+
+		return Condition{Query: column + " IN ?", Args: []interface{}{values}}, nil
+	case OpBetween:
+		values := listValues(filter.Value)
+		if len(values) != 2 {
+			return Condition{}, ErrInvalidValue
+		}
+
+		return Condition{Query: column + " BETWEEN ? AND ?", Args: values}, nil
+	default:
+		return Condition{}, ErrUnknownOperator
+	}
+}
+
+// sqlOperators maps the scalar operators to SQL.
+var sqlOperators = map[Operator]string{
+	OpEq:  "=",
+	OpGte: ">=",
+	OpLte: "<=",
+}
+
+// isScalar reports whether value is a non-nil value other than a slice, array or map.
+func isScalar(value interface{}) bool {
+	if value == nil {
+		return false
+	}
+
+	switch reflect.TypeOf(value).Kind() {
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return false
+	default:
+		return true
+	}
+}
+
+// listValues returns the items of a slice or array, or of a comma-separated string.
+func listValues(value interface{}) []interface{} {
+	if s, ok := value.(string); ok {
+		if s == "" {
+			return nil
+		}
+
+		parts := strings.Split(s, ",")
+		values := make([]interface{}, 0, len(parts))
+		for _, part := range parts {
+			values = append(values, strings.TrimSpace(part))
+		}
+
+		return values
+	}
+
+	if value == nil {
+		return nil
+	}
+
+	v := reflect.ValueOf(value)
+	if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+		return nil
+	}
+
+	values := make([]interface{}, 0, v.Len())
+	for i := 0; i < v.Len(); i++ {
+		values = append(values, v.Index(i).Interface())
+	}
+
+	return values
+}
+
+// EscapeLike escapes the LIKE wildcards in s, so they are matched literally.
+//
+// Parameters:
+//   - s: The raw search keyword.
+//
+// Returns:
+//   - string: The escaped keyword, to be wrapped in % for a substring match.
+func EscapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,119 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package query
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestBuilderParse(t *testing.T) {
+	b := NewBuilder(map[string]string{
+		"name":       "app_name",
+		"status":     "status",
+		"created_at": "created_at",
+		"id":         "id",
+	})
+
+	err := b.Parse(map[string]Filter{
+		"name":       {Op: OpLike, Value: "50%_off"},
+		"status":     {Op: OpIn, Value: "1, 2"},
+		"created_at": {Op: OpBetween, Value: []string{"2024-01-01", "2024-02-01"}},
+		"id":         {Op: OpGte, Value: 10},
+	})
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := []Condition{
+		{Query: "created_at BETWEEN ? AND ?", Args: []interface{}{"2024-01-01", "2024-02-01"}},
+		{Query: "id >= ?", Args: []interface{}{10}},
+		{Query: "app_name LIKE ?", Args: []interface{}{`%50\%\_off%`}},
+		{Query: "status IN ?", Args: []interface{}{[]interface{}{"1", "2"}}},
+	}
+	if got := b.Conditions(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Conditions() = %#v, want %#v", got, want)
+	}
+}
+
+func TestBuilderParseRejects(t *testing.T) {
+	tests := []struct {
+		name   string
+		field  string
+		filter Filter
+		want   error
+	}{
+		{name: "field not whitelisted", field: "app_secret", filter: Filter{Op: OpEq, Value: "x"}, want: ErrUnknownField},
+		{name: "column injection", field: "status; DROP TABLE auth_app", filter: Filter{Op: OpEq, Value: 1}, want: ErrUnknownField},
+		{name: "unknown operator", field: "status", filter: Filter{Op: "ne", Value: 1}, want: ErrUnknownOperator},
+		{name: "eq with a list", field: "status", filter: Filter{Op: OpEq, Value: []int{1, 2}}, want: ErrInvalidValue},
+		{name: "between with one value", field: "status", filter: Filter{Op: OpBetween, Value: "1"}, want: ErrInvalidValue},
+		{name: "empty in", field: "status", filter: Filter{Op: OpIn, Value: ""}, want: ErrInvalidValue},
+		{name: "like without a string", field: "status", filter: Filter{Op: OpLike, Value: 1}, want: ErrInvalidValue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := NewBuilder(map[string]string{"status": "status"})
+
+			err := b.Parse(map[string]Filter{tt.field: tt.filter})
+
+			var fieldErr *FieldError
+			if !errors.As(err, &fieldErr) || fieldErr.Field != tt.field {
+				t.Fatalf("Parse() error = %v, want a *FieldError for %q", err, tt.field)
+			}
+
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Parse() error = %v, want %v", err, tt.want)
+			}
+
+			if len(b.Conditions()) != 0 {
+				t.Errorf("Conditions() = %v, want none after a rejected filter", b.Conditions())
+			}
+		})
+	}
+}
+
+func TestPaginateRejectsInvalidPage(t *testing.T) {
+	tests := []struct {
+		name string
+		page int
+		size int
+	}{
+		{name: "page zero", page: 0, size: 20},
+		{name: "negative page", page: -1, size: 20},
+		{name: "size zero", page: 1, size: 0},
+		{name: "negative size", page: 1, size: -5},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// The page is checked before the database is touched, so no connection is needed.
+			_, _, err := Paginate[struct{}](context.Background(), nil, NewBuilder(nil), tt.page, tt.size)
+			if !errors.Is(err, ErrInvalidPage) {
+				t.Errorf("Paginate() error = %v, want %v", err, ErrInvalidPage)
+			}
+		})
+	}
+}
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "plain", want: "plain"},
+		{in: "50%_off", want: `50\%\_off`},
+		{in: `a\b`, want: `a\\b`},
+	}
+
+	for _, tt := range tests {
+		if got := EscapeLike(tt.in); got != tt.want {
+			t.Errorf("EscapeLike(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package query
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrUnsupportedDialect is returned for a JSON query on a database other than MySQL and Postgres.
+var ErrUnsupportedDialect = errors.New("unsupported dialect")
+
+// jsonPathPattern matches the JSON paths WhereJSON supports: "$" followed by object keys and array indexes.
+var jsonPathPattern = regexp.MustCompile(`^\$(\.[A-Za-z_][A-Za-z0-9_]*|\[[0-9]+\])+$`)
+
+// jsonPathStep matches a single key or index of a JSON path.
+var jsonPathStep = regexp.MustCompile(`\.([A-Za-z_][A-Za-z0-9_]*)|\[([0-9]+)\]`)
+
+// jsonEquals is the condition of WhereJSON, written in the dialect of the statement.
+type jsonEquals struct {
+	column string
+	path   string
+	value  string
+}
+
+// WhereJSON returns a scope matching the rows whose JSON column holds value at path.
+//
+// It is meant for the datatypes.JSON fields of the generated models. The condition is written
+// in the dialect of the connection: JSON_EXTRACT for MySQL and the #> operator for Postgres.
+// Values are compared as JSON, so 1, "1" and true are different values.
+//
+// Parameters:
+//   - column: The JSON column, e.g. "metadata"; it is interpolated into SQL and must never come from user input.
+//   - path: The JSON path of the value, in MySQL syntax: "$" followed by keys and indexes, e.g. "$.tags[0].name".
+//   - value: The expected value, encoded as JSON.
+//
+// Returns:
+//   - func(*gorm.DB) *gorm.DB: The scope; the query fails with ErrInvalidValue for an unsupported
+//     path or value and with ErrUnsupportedDialect on other databases.
+//
+// Example:
+//
+//	// type Order struct {
+//	//     gorm.Model
+//	//     Metadata datatypes.JSON `gorm:"column:metadata;type:json" json:"metadata"`
+//	// }
+//	var orders []Order
+//	err := db.WithContext(ctx).
+//	    Scopes(query.WhereJSON("metadata", "$.channel", "wechat"), query.WhereJSON("metadata", "$.vip", true)).
+//	    Find(&orders).Error
+func WhereJSON(column, path string, value interface{}) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if !jsonPathPattern.MatchString(path) {
+			_ = db.AddError(fmt.Errorf("json path %q: %w", path, ErrInvalidValue))
+			return db
+		}
+
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			_ = db.AddError(fmt.Errorf("json value of %q: %w", path, ErrInvalidValue))
+			return db
+		}
+
+		return db.Where(jsonEquals{column: column, path: path, value: string(encoded)})
+	}
+}
+
+// Build writes the condition in the dialect of the statement.
+func (e jsonEquals) Build(builder clause.Builder) {
+	dialect := ""
+	if stmt, ok := builder.(*gorm.Statement); ok {
+		dialect = stmt.Dialector.Name()
+	}
+
+	switch dialect {
+	case "mysql":
+		builder.WriteString("JSON_EXTRACT(")
+		builder.WriteQuoted(e.column)
+		builder.WriteString(", ")
+		builder.AddVar(builder, e.path)
+		builder.WriteString(") = CAST(")
+		builder.AddVar(builder, e.value)
+		builder.WriteString(" AS JSON)")
+	case "postgres":
+		builder.WriteString("(")
+		builder.WriteQuoted(e.column)
+		builder.WriteString(" #> ")
+		builder.AddVar(builder, postgresPath(e.path))
+		builder.WriteString("::text[])::jsonb = ")
+		builder.AddVar(builder, e.value)
+		builder.WriteString("::jsonb")
+	default:
+		_ = builder.AddError(fmt.Errorf("json query on %q: %w", dialect, ErrUnsupportedDialect))
+	}
+}
+
+// postgresPath converts a path matching jsonPathPattern into a Postgres text array, e.g.
+// "$.tags[0].name" into "{tags,0,name}".
+func postgresPath(path string) string {
+	matches := jsonPathStep.FindAllStringSubmatch(path, -1)
+
+	steps := make([]string, 0, len(matches))
+	for _, match := range matches {
+		steps = append(steps, match[1]+match[2])
+	}
+
+	return "{" + strings.Join(steps, ",") + "}"
+}
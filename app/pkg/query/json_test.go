@@ -0,0 +1,93 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package query
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/utils/tests"
+)
+
+// dialector is the dummy dialector of gorm under another name.
+type dialector struct {
+	tests.DummyDialector
+	name string
+}
+
+func (d dialector) Name() string {
+	return d.name
+}
+
+// order is a generated model with a JSON column.
+type order struct {
+	ID       uint
+	Metadata []byte `gorm:"column:metadata;type:json"`
+}
+
+func dryRun(t *testing.T, dialect string, scope func(*gorm.DB) *gorm.DB) *gorm.Statement {
+	t.Helper()
+
+	db, err := gorm.Open(dialector{name: dialect}, &gorm.Config{DryRun: true})
+	if err != nil {
+		t.Fatalf("gorm.Open() error = %v", err)
+	}
+
+	var orders []order
+
+	return db.Scopes(scope).Find(&orders).Statement
+}
+
+func TestWhereJSON(t *testing.T) {
+	tests := []struct {
+		dialect string
+		sql     string
+		vars    []interface{}
+	}{
+		{
+			dialect: "mysql",
+			sql:     "SELECT * FROM `orders` WHERE JSON_EXTRACT(`metadata`, ?) = CAST(? AS JSON)",
+			vars:    []interface{}{"$.tags[0].name", `"vip"`},
+		},
+		{
+			dialect: "postgres",
+			sql:     "SELECT * FROM `orders` WHERE (`metadata` #> ?::text[])::jsonb = ?::jsonb",
+			vars:    []interface{}{"{tags,0,name}", `"vip"`},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.dialect, func(t *testing.T) {
+			stmt := dryRun(t, tt.dialect, WhereJSON("metadata", "$.tags[0].name", "vip"))
+			if stmt.Error != nil {
+				t.Fatalf("Find() error = %v", stmt.Error)
+			}
+
+			if got := stmt.SQL.String(); got != tt.sql {
+				t.Errorf("SQL = %s, want %s", got, tt.sql)
+			}
+
+			if !reflect.DeepEqual(stmt.Vars, tt.vars) {
+				t.Errorf("Vars = %#v, want %#v", stmt.Vars, tt.vars)
+			}
+		})
+	}
+}
+
+func TestWhereJSONRejects(t *testing.T) {
+	if stmt := dryRun(t, "mysql", WhereJSON("metadata", "$.tags[*]", "vip")); !errors.Is(stmt.Error, ErrInvalidValue) {
+		t.Errorf("wildcard path error = %v, want %v", stmt.Error, ErrInvalidValue)
+	}
+
+	if stmt := dryRun(t, "mysql", WhereJSON("metadata", "$.key", func() {})); !errors.Is(stmt.Error, ErrInvalidValue) {
+		t.Errorf("unencodable value error = %v, want %v", stmt.Error, ErrInvalidValue)
+	}
+
+	if stmt := dryRun(t, "sqlite", WhereJSON("metadata", "$.key", "v")); !errors.Is(stmt.Error, ErrUnsupportedDialect) {
+		t.Errorf("sqlite error = %v, want %v", stmt.Error, ErrUnsupportedDialect)
+	}
+}
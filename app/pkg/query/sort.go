@@ -0,0 +1,71 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package query
+
+import (
+	"strings"
+)
+
+// ParseSort converts a client sort parameter into an ORDER BY clause.
+//
+// The parameter lists API field names separated by commas, each optionally followed by ":asc" or
+// ":desc" (ascending by default), e.g. "status,created_at:desc". Only whitelisted fields are
+// accepted and they are replaced with their column names, so the result is safe to pass to Order.
+//
+// Parameters:
+//   - param: The sort parameter; empty means no ordering.
+//   - allowed: Maps the sortable API field names to their column names.
+//
+// Returns:
+//   - string: The ORDER BY clause, e.g. "status ASC, created_at DESC"; empty for an empty param.
+//   - error: A *FieldError naming the offending field: ErrUnknownField for a field not in allowed,
+//     ErrInvalidValue for an unknown direction, an empty item or a repeated field.
+//
+// Example:
+//
+//	order, err := query.ParseSort(c.Query("sort"), map[string]string{"name": "app_name", "created_at": "created_at"})
+//	if err != nil {
+//	    return e.New(e.InvalidParams, err).WithField("sort", err.Error())
+//	}
+func ParseSort(param string, allowed map[string]string) (string, error) {
+	param = strings.TrimSpace(param)
+	if param == "" {
+		return "", nil
+	}
+
+	items := strings.Split(param, ",")
+	clauses := make([]string, 0, len(items))
+	seen := make(map[string]struct{}, len(items))
+
+	for _, item := range items {
+		field, direction, _ := strings.Cut(strings.TrimSpace(item), ":")
+		field = strings.TrimSpace(field)
+
+		if field == "" {
+			return "", &FieldError{Field: item, Err: ErrInvalidValue}
+		}
+
+		column, ok := allowed[field]
+		if !ok {
+			return "", &FieldError{Field: field, Err: ErrUnknownField}
+		}
+
+		if _, ok = seen[field]; ok {
+			return "", &FieldError{Field: field, Err: ErrInvalidValue}
+		}
+		seen[field] = struct{}{}
+
+		switch strings.ToLower(strings.TrimSpace(direction)) {
+		case "", "asc":
+			clauses = append(clauses, column+" ASC")
+		case "desc":
+			clauses = append(clauses, column+" DESC")
+		default:
+			return "", &FieldError{Field: field, Err: ErrInvalidValue}
+		}
+	}
+
+	return strings.Join(clauses, ", "), nil
+}
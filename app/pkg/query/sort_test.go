@@ -0,0 +1,45 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseSort(t *testing.T) {
+	allowed := map[string]string{"name": "app_name", "created_at": "created_at", "id": "id"}
+
+	tests := []struct {
+		param     string
+		want      string
+		wantField string
+		wantErr   error
+	}{
+		{param: "", want: ""},
+		{param: "name", want: "app_name ASC"},
+		{param: "created_at:desc, name:ASC", want: "created_at DESC, app_name ASC"},
+		{param: "app_secret:asc", wantField: "app_secret", wantErr: ErrUnknownField},
+		{param: "id;DROP TABLE auth_app", wantField: "id;DROP TABLE auth_app", wantErr: ErrUnknownField},
+		{param: "id:sideways", wantField: "id", wantErr: ErrInvalidValue},
+		{param: "id,id:desc", wantField: "id", wantErr: ErrInvalidValue},
+		{param: "id,", wantField: "", wantErr: ErrInvalidValue},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseSort(tt.param, allowed)
+		if tt.wantErr == nil {
+			if err != nil || got != tt.want {
+				t.Errorf("ParseSort(%q) = %q, %v, want %q, nil", tt.param, got, err, tt.want)
+			}
+			continue
+		}
+
+		var fieldErr *FieldError
+		if !errors.As(err, &fieldErr) || fieldErr.Field != tt.wantField || !errors.Is(err, tt.wantErr) {
+			t.Errorf("ParseSort(%q) error = %v, want %v on field %q", tt.param, err, tt.wantErr, tt.wantField)
+		}
+	}
+}
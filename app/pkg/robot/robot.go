@@ -0,0 +1,165 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package robot posts text reports to the WeChat Work and Feishu group robots
+// configured under monitor.panic_robot.
+package robot
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/seakee/go-api/app/config"
+	"github.com/sk-pkg/logger"
+	"go.uber.org/zap"
+)
+
+// DefaultThrottle is how long a report with the same key is suppressed after being posted.
+const DefaultThrottle = time.Minute
+
+// Robot posts reports to the configured group robot webhooks.
+//
+// It also throttles reports by key through Allow, so a panic hit in a hot loop is reported
+// once per interval instead of flooding the channels.
+type Robot struct {
+	cfg      config.PanicRobot
+	env      string
+	hostname string
+	client   *http.Client
+	throttle time.Duration
+	logger   *logger.Manager
+
+	mu   sync.Mutex
+	sent map[string]time.Time // Last post time per report key
+}
+
+// New creates a Robot from the panic robot configuration.
+//
+// Parameters:
+//   - cfg: config.PanicRobot - The robot configuration.
+//   - env: string - The running environment, included in every report.
+//   - log: *logger.Manager - The logger of the posts the webhooks fail or reject.
+//
+// Returns:
+//   - *Robot: A new Robot instance; it posts nothing when cfg.Enable is false.
+//
+// Example:
+//
+//	r := robot.New(config.Get().Monitor.PanicRobot, config.Get().System.Env, log)
+//	if r.Allow("ipChanged") {
+//	    r.Report("Server IP changed to 1.2.3.4")
+//	}
+func New(cfg config.PanicRobot, env string, log *logger.Manager) *Robot {
+	hostname, _ := os.Hostname()
+
+	return &Robot{
+		cfg:      cfg,
+		env:      env,
+		hostname: hostname,
+		client:   &http.Client{Timeout: 5 * time.Second},
+		throttle: DefaultThrottle,
+		logger:   log,
+		sent:     make(map[string]time.Time),
+	}
+}
+
+// Enabled reports whether the robot posts anything.
+func (r *Robot) Enabled() bool {
+	return r != nil && r.cfg.Enable && (r.cfg.Wechat.Enable || r.cfg.Feishu.Enable)
+}
+
+// Report posts content to every enabled robot in the background, prefixed with the
+// environment and host name. Call Allow first to throttle identical reports.
+//
+// Parameters:
+//   - content: string - The text to post.
+//
+// Returns:
+//   - bool: False if the robot is disabled, true otherwise.
+func (r *Robot) Report(content string) bool {
+	if !r.Enabled() {
+		return false
+	}
+
+	content = fmt.Sprintf("Env: %s\nHost: %s\nTime: %s\n%s", r.env, r.hostname, time.Now().Format(time.DateTime), content)
+
+	if r.cfg.Wechat.Enable {
+		go r.push(r.cfg.Wechat.PushUrl, map[string]interface{}{
+			"msgtype": "text",
+			"text":    map[string]string{"content": content},
+		})
+	}
+
+	if r.cfg.Feishu.Enable {
+		go r.push(r.cfg.Feishu.PushUrl, map[string]interface{}{
+			"msg_type": "text",
+			"content":  map[string]string{"text": content},
+		})
+	}
+
+	return true
+}
+
+// Allow records a report of key and reports whether it is outside the throttle interval.
+//
+// It throttles whether or not the robot is enabled, so it can guard other channels too.
+//
+// Parameters:
+//   - key: string - Identifies the report, e.g. the panic value and location.
+//
+// Returns:
+//   - bool: False if a report with the same key was allowed within the throttle interval.
+func (r *Robot) Allow(key string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := time.Now()
+	if last, ok := r.sent[key]; ok && now.Sub(last) < r.throttle {
+		return false
+	}
+
+	// Drop expired keys so the map does not grow with every distinct report
+	for k, last := range r.sent {
+		if now.Sub(last) >= r.throttle {
+			delete(r.sent, k)
+		}
+	}
+
+	r.sent[key] = now
+
+	return true
+}
+
+// push posts body as JSON to a robot webhook, logging the posts that fail or are rejected,
+// e.g. because of a wrong push_url.
+func (r *Robot) push(url string, body map[string]interface{}) {
+	ctx := context.Background()
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		r.logger.Error(ctx, "marshal robot report failed", zap.Error(err))
+		return
+	}
+
+	resp, err := r.client.Post(url, "application/json; charset=utf-8", bytes.NewReader(payload))
+	if err != nil {
+		// The URL carries the webhook key, and the error of net/http quotes it
+		r.logger.Error(ctx, "post robot report failed", zap.String("error", strings.ReplaceAll(err.Error(), url, "<push_url>")))
+		return
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < http.StatusOK || resp.StatusCode >= http.StatusMultipleChoices {
+		reply, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		r.logger.Error(ctx, "robot rejected the report", zap.Int("status", resp.StatusCode), zap.ByteString("reply", reply))
+	}
+}
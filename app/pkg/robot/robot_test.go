@@ -0,0 +1,40 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package robot
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRobotAllow(t *testing.T) {
+	r := &Robot{throttle: time.Minute, sent: make(map[string]time.Time)}
+
+	if !r.Allow("nil pointer@/app") {
+		t.Fatal("Allow() of a first report = false, want true")
+	}
+
+	if r.Allow("nil pointer@/app") {
+		t.Error("Allow() of an identical report within the interval = true, want false")
+	}
+
+	if !r.Allow("nil pointer@/token") {
+		t.Error("Allow() of another report = false, want true")
+	}
+
+	// The first report was posted before the interval
+	r.sent["nil pointer@/app"] = time.Now().Add(-2 * time.Minute)
+
+	if !r.Allow("nil pointer@/app") {
+		t.Error("Allow() of an identical report after the interval = false, want true")
+	}
+
+	r.sent["stale"] = time.Now().Add(-2 * time.Minute)
+	r.Allow("nil pointer@/other")
+
+	if _, ok := r.sent["stale"]; ok {
+		t.Error("expired report key still recorded after Allow()")
+	}
+}
@@ -0,0 +1,181 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package sanitize prepares request and response bodies for logging: sensitive fields are
+// masked and large bodies are truncated.
+package sanitize
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Mask replaces the values of sensitive fields.
+const Mask = "******"
+
+// DefaultFields are the sensitive fields masked when no list is configured.
+var DefaultFields = []string{
+	"password", "app_secret", "secret", "token", "access_token", "refresh_token", "credentials", "authorization",
+	"corpsecret", "appsecret", "client_secret",
+}
+
+// Body returns body ready to be logged.
+//
+// The values of fields are masked first, in JSON bodies at any depth and in form-encoded
+// bodies; field names are matched case-insensitively. Other bodies are kept as they are. The
+// result is then truncated to maxSize bytes with an indicator of the original size.
+//
+// Parameters:
+//   - body: The raw body.
+//   - contentType: The Content-Type of the body, used to recognize form-encoded bodies.
+//   - maxSize: The maximum size of the result in bytes, excluding the indicator; 0 or less keeps everything.
+//   - fields: The sensitive field names; nil uses DefaultFields.
+//
+// Returns:
+//   - string: The sanitized body.
+//
+// Example:
+//
+//	logged := sanitize.Body(buf, c.ContentType(), 4096, nil)
+func Body(body []byte, contentType string, maxSize int, fields []string) string {
+	if fields == nil {
+		fields = DefaultFields
+	}
+
+	set := fieldSet(fields)
+
+	if redacted, ok := redactJSON(body, set); ok {
+		body = redacted
+	} else if strings.HasPrefix(contentType, "application/x-www-form-urlencoded") {
+		body = redactForm(body, set)
+	}
+
+	return Truncate(body, maxSize)
+}
+
+// URL returns rawURL with the values of the sensitive query parameters masked.
+//
+// Parameters:
+//   - rawURL: The URL, e.g. "https://qyapi.weixin.qq.com/cgi-bin/gettoken?corpid=ww1&corpsecret=s".
+//   - fields: The sensitive parameter names, matched case-insensitively; nil uses DefaultFields.
+//
+// Returns:
+//   - string: The sanitized URL; the query string is dropped if it can't be parsed.
+//
+// Example:
+//
+//	logged := sanitize.URL(req.URL.String(), nil)
+func URL(rawURL string, fields []string) string {
+	path, rawQuery, ok := strings.Cut(rawURL, "?")
+	if !ok || rawQuery == "" {
+		return rawURL
+	}
+
+	if fields == nil {
+		fields = DefaultFields
+	}
+
+	// Don't risk logging a secret in a query string that can't be parsed
+	if _, err := url.ParseQuery(rawQuery); err != nil {
+		return path
+	}
+
+	return path + "?" + string(redactForm([]byte(rawQuery), fieldSet(fields)))
+}
+
+// Truncate cuts body to maxSize bytes, without splitting a UTF-8 character, and appends an
+// indicator of the original size.
+//
+// Parameters:
+//   - body: The body.
+//   - maxSize: The maximum size in bytes, excluding the indicator; 0 or less keeps everything.
+//
+// Returns:
+//   - string: The possibly truncated body, e.g. `{"name":"a...(truncated, 10240 bytes)`.
+func Truncate(body []byte, maxSize int) string {
+	if maxSize <= 0 || len(body) <= maxSize {
+		return string(body)
+	}
+
+	end := maxSize
+	for end > 0 && !utf8.RuneStart(body[end]) {
+		end--
+	}
+
+	return string(body[:end]) + "...(truncated, " + strconv.Itoa(len(body)) + " bytes)"
+}
+
+// redactJSON masks the sensitive fields of a JSON body; ok is false if body isn't a JSON object or array.
+func redactJSON(body []byte, fields map[string]struct{}) (redacted []byte, ok bool) {
+	trimmed := bytes.TrimSpace(body)
+	if len(trimmed) == 0 || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return nil, false
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(trimmed))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil, false
+	}
+
+	redacted, err := json.Marshal(redactValue(value, fields))
+	if err != nil {
+		return nil, false
+	}
+
+	return redacted, true
+}
+
+// redactValue masks the sensitive fields of a decoded JSON value, recursively.
+func redactValue(value interface{}, fields map[string]struct{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if _, ok := fields[strings.ToLower(key)]; ok {
+				v[key] = Mask
+				continue
+			}
+
+			v[key] = redactValue(item, fields)
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item, fields)
+		}
+	}
+
+	return value
+}
+
+// fieldSet returns the lowercased fields as a set.
+func fieldSet(fields []string) map[string]struct{} {
+	set := make(map[string]struct{}, len(fields))
+	for _, field := range fields {
+		set[strings.ToLower(field)] = struct{}{}
+	}
+
+	return set
+}
+
+// redactForm masks the sensitive fields of a form-encoded body; an unparsable body is kept.
+func redactForm(body []byte, fields map[string]struct{}) []byte {
+	values, err := url.ParseQuery(string(body))
+	if err != nil {
+		return body
+	}
+
+	for key := range values {
+		if _, ok := fields[strings.ToLower(key)]; ok {
+			values[key] = []string{Mask}
+		}
+	}
+
+	return []byte(values.Encode())
+}
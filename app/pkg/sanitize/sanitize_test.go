@@ -0,0 +1,90 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package sanitize
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBodyRedaction(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		fields      []string
+		want        string
+	}{
+		{
+			name: "nested json",
+			body: `{"app_id":"go-api-a","App_Secret":"s3cr3t","auth":{"credentials":{"key":"k"},"items":[{"password":"p"}]},"n":12345678901234567890}`,
+			want: `{"App_Secret":"******","app_id":"go-api-a","auth":{"credentials":"******","items":[{"password":"******"}]},"n":12345678901234567890}`,
+		},
+		{
+			name:        "form",
+			body:        "app_id=go-api-a&app_secret=s3cr3t",
+			contentType: "application/x-www-form-urlencoded; charset=utf-8",
+			want:        "app_id=go-api-a&app_secret=%2A%2A%2A%2A%2A%2A",
+		},
+		{
+			name:   "custom fields",
+			body:   `{"pin":"1234","password":"p"}`,
+			fields: []string{"pin"},
+			want:   `{"password":"p","pin":"******"}`,
+		},
+		{
+			name: "not json",
+			body: "password=p",
+			want: "password=p",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Body([]byte(tt.body), tt.contentType, 0, tt.fields); got != tt.want {
+				t.Errorf("Body() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBodyTruncation(t *testing.T) {
+	body := `{"name":"` + strings.Repeat("a", 100) + `"}`
+
+	got := Body([]byte(body), "application/json", 20, nil)
+	if want := `{"name":"aaaaaaaaaaa...(truncated, 111 bytes)`; got != want {
+		t.Errorf("Body() = %s, want %s", got, want)
+	}
+
+	if got = Body([]byte(body), "application/json", 0, nil); got != body {
+		t.Errorf("Body() without limit = %s, want %s", got, body)
+	}
+
+	// A multi-byte character is never split
+	if got = Truncate([]byte("ab中文"), 4); got != "ab...(truncated, 8 bytes)" {
+		t.Errorf("Truncate() = %s, want ab...(truncated, 8 bytes)", got)
+	}
+}
+
+func TestURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{
+			url:  "https://qyapi.weixin.qq.com/cgi-bin/gettoken?corpid=ww1&corpsecret=s3cr3t",
+			want: "https://qyapi.weixin.qq.com/cgi-bin/gettoken?corpid=ww1&corpsecret=%2A%2A%2A%2A%2A%2A",
+		},
+		{url: "https://open.feishu.cn/open-apis/user?Access_Token=t", want: "https://open.feishu.cn/open-apis/user?Access_Token=%2A%2A%2A%2A%2A%2A"},
+		{url: "https://example.com/path", want: "https://example.com/path"},
+		{url: "https://example.com/path?token=%zz", want: "https://example.com/path"},
+	}
+
+	for _, tt := range tests {
+		if got := URL(tt.url, nil); got != tt.want {
+			t.Errorf("URL(%s) = %s, want %s", tt.url, got, tt.want)
+		}
+	}
+}
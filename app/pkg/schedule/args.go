@@ -0,0 +1,58 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package schedule
+
+import "context"
+
+// argsKey is the context key of the job arguments.
+type argsKey struct{}
+
+// WithArgs sets the arguments passed to the handler of the job, through the context of Exec.
+//
+// It lets one handler type back several jobs, e.g. a cleanup of different collections.
+//
+// Parameters:
+//   - args: The arguments, read in Exec with Args or Arg
+//
+// Returns:
+//   - *Job: The modified Job instance
+//
+// Example:
+//
+//	s.AddJob("CleanupLogs", cleanup).DailyAt("03:00:00").WithArgs(map[string]any{"collection": "logs", "days": 30})
+func (j *Job) WithArgs(args map[string]any) *Job {
+	j.Args = args
+	return j
+}
+
+// Args returns the arguments of the running job.
+//
+// Parameters:
+//   - ctx: The context passed to Exec
+//
+// Returns:
+//   - map[string]any: The arguments set by WithArgs, nil if none
+func Args(ctx context.Context) map[string]any {
+	args, _ := ctx.Value(argsKey{}).(map[string]any)
+	return args
+}
+
+// Arg returns the argument name of the running job.
+//
+// Parameters:
+//   - ctx: The context passed to Exec
+//   - name: The name of the argument
+//
+// Returns:
+//   - T: The argument, the zero value if it is missing or not a T
+//   - bool: Whether the argument is set and is a T
+//
+// Example:
+//
+//	days, ok := schedule.Arg[int](ctx, "days")
+func Arg[T any](ctx context.Context, name string) (T, bool) {
+	value, ok := Args(ctx)[name].(T)
+	return value, ok
+}
@@ -0,0 +1,30 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package schedule
+
+import (
+	"testing"
+
+	"github.com/seakee/go-api/app/pkg/trace"
+)
+
+func TestJobArgs(t *testing.T) {
+	s := New(nil, nil, trace.NewTraceID())
+
+	ctx := s.AddJob("CleanupLogs", testHandler{}).WithArgs(map[string]any{"collection": "logs", "days": 30}).context()
+
+	if days, ok := Arg[int](ctx, "days"); !ok || days != 30 {
+		t.Errorf("Arg[int](days) = %d, %v, want 30, true", days, ok)
+	}
+
+	if _, ok := Arg[int](ctx, "collection"); ok {
+		t.Error("Arg[int](collection) ok = true, want false for a string")
+	}
+
+	// Jobs without arguments keep working
+	if args := Args(s.AddJob("IpMonitor", testHandler{}).context()); args != nil {
+		t.Errorf("Args() = %v, want nil", args)
+	}
+}
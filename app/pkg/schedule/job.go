@@ -6,8 +6,10 @@ package schedule
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/rand"
+	"sync"
 	"time"
 
 	"github.com/seakee/go-api/app/pkg/trace"
@@ -29,6 +31,21 @@ const (
 	ImmediateRunType RunType = "immediate" // Run task immediately
 
 	DefaultServerLockTTL = 600 // Default single-server task lock time (10 minutes)
+	DefaultDailyJitter   = 5   // Default upper bound in seconds of the random delay of a DailyAt job
+
+	// dailyWindow is how late a daily time still matches. The scheduler ticks every second, but a
+	// tick can land late or be skipped, so matching the exact second would miss runs.
+	dailyWindow = 5 * time.Second
+)
+
+// Errors returned by Validate for a misconfigured job.
+var (
+	ErrNoHandler         = errors.New("no handler")
+	ErrNoSchedule        = errors.New("no timing method")
+	ErrMultipleSchedules = errors.New("more than one timing method")
+	ErrInvalidTime       = errors.New("invalid daily time")
+	ErrInvalidInterval   = errors.New("invalid interval")
+	ErrInvalidDelay      = errors.New("invalid random delay")
 )
 
 // Job represents a scheduled task with its properties and execution settings.
@@ -36,11 +53,19 @@ type Job struct {
 	Name                  string          // Name of the job instance
 	Logger                *logger.Manager // Logger for the job
 	Redis                 *redis.Manager  // Redis client for job operations
+	Locker                Locker          // Distributed lock used by OnOneServer
 	Handler               HandlerFunc     // Function to be executed
 	EnableMultipleServers bool            // Allow execution on multiple nodes
 	EnableOverlapping     bool            // Allow job to run even if previous instance is still running
 	RunTime               *RunTime        // Runtime parameters for the job
 	TraceID               *trace.ID       // TraceID for job execution tracking
+	ErrorReporter         ErrorReporter   // Receives the errors and panics of the job, nil to only log them
+	AlertsSilenced        bool            // Skip ErrorReporter, for noisy best-effort jobs
+	Args                  map[string]any  // Arguments passed to the handler, see WithArgs
+
+	errs      []error          // Misuses of the builder methods, reported by Validate
+	now       func() time.Time // Clock of the daily schedule, replaced in tests
+	scheduler *Schedule        // The scheduler tracking the runs of the job
 }
 
 // HandlerFunc interface defines the methods that a job handler must implement.
@@ -52,12 +77,13 @@ type HandlerFunc interface {
 
 // RunTime contains the runtime parameters for a job.
 type RunTime struct {
-	Type          RunType       // Type of schedule (daily, per second, per minute, per hour, immediate)
-	Time          interface{}   // Execution time or interval
-	Locked        bool          // Execution lock for non-overlapping jobs
-	PerTypeLocked bool          // Lock for interval-based job types
-	Done          chan struct{} // Channel to signal job completion
-	RandomDelay   *RandomDelay  // Random delay settings for job execution
+	Type          RunType           // Type of schedule (daily, per second, per minute, per hour, immediate)
+	Time          interface{}       // Execution time or interval
+	Locked        bool              // Execution lock for non-overlapping jobs
+	PerTypeLocked bool              // Lock for interval-based job types
+	Done          chan struct{}     // Channel to signal job completion
+	RandomDelay   *RandomDelay      // Random delay settings for job execution
+	LastRuns      map[string]string // Date ("2006-01-02") of the last run of each daily time, so it runs once a day
 }
 
 // RandomDelay defines the minimum and maximum random delay for job execution.
@@ -82,8 +108,8 @@ func (j *Job) WithoutOverlapping() *Job {
 // RandomDelay sets a random delay range for job execution.
 //
 // Parameters:
-//   - min: Minimum delay in seconds
-//   - max: Maximum delay in seconds
+//   - min: Minimum delay in seconds, at least 0
+//   - max: Maximum delay in seconds, at least min
 //
 // Returns:
 //   - *Job: The modified Job instance
@@ -92,10 +118,6 @@ func (j *Job) WithoutOverlapping() *Job {
 //
 //	job.RandomDelay(30, 60)
 func (j *Job) RandomDelay(min, max int) *Job {
-	if max < min {
-		panic("must max > min")
-	}
-
 	j.RunTime.RandomDelay = &RandomDelay{
 		Min: min,
 		Max: max,
@@ -104,17 +126,26 @@ func (j *Job) RandomDelay(min, max int) *Job {
 	return j
 }
 
+// Immediate schedules the job to run once, as soon as the scheduler starts.
+//
+// Returns:
+//   - *Job: The modified Job instance
+//
+// Example:
+//
+//	job.Immediate()
 func (j *Job) Immediate() *Job {
-	if j.RunTime.Type == "" {
-		j.RunTime.Type = ImmediateRunType
-	}
-
 	j.EnableOverlapping = false
-	return j
+	return j.schedule(ImmediateRunType, nil)
 }
 
 // DailyAt schedules the job to run at specific times each day.
 //
+// A time runs once a day, on the first scheduler tick within 5 seconds after it. So that nodes
+// sharing the schedule don't all hit their dependencies on the same second, the run is delayed
+// by up to DefaultDailyJitter seconds; call RandomDelay to widen the spread, or RandomDelay(0, 0)
+// to run on the tick.
+//
 // Parameters:
 //   - time: One or more time strings in "HH:MM:SS" format
 //
@@ -125,11 +156,11 @@ func (j *Job) Immediate() *Job {
 //
 //	job.DailyAt("07:30:00", "12:00:00", "18:00:00")
 func (j *Job) DailyAt(time ...string) *Job {
-	if j.RunTime.Type == "" {
-		j.RunTime.Type = DailyRunType
-		j.RunTime.Time = time
+	if j.RunTime.RandomDelay == nil {
+		j.RunTime.RandomDelay = &RandomDelay{Max: DefaultDailyJitter}
 	}
-	return j
+
+	return j.schedule(DailyRunType, time)
 }
 
 // PerSeconds schedules the job to run every specified number of seconds.
@@ -144,11 +175,7 @@ func (j *Job) DailyAt(time ...string) *Job {
 //
 //	job.PerSeconds(30)
 func (j *Job) PerSeconds(seconds int) *Job {
-	if j.RunTime.Type == "" {
-		j.RunTime.Type = SecondlyRunType
-		j.RunTime.Time = time.Duration(seconds) * time.Second
-	}
-	return j
+	return j.schedule(SecondlyRunType, time.Duration(seconds)*time.Second)
 }
 
 // PerMinuit schedules the job to run every specified number of minutes.
@@ -163,11 +190,7 @@ func (j *Job) PerSeconds(seconds int) *Job {
 //
 //	job.PerMinuit(15)
 func (j *Job) PerMinuit(minuit int) *Job {
-	if j.RunTime.Type == "" {
-		j.RunTime.Type = MinutelyRunType
-		j.RunTime.Time = time.Duration(minuit) * time.Minute
-	}
-	return j
+	return j.schedule(MinutelyRunType, time.Duration(minuit)*time.Minute)
 }
 
 // PerHour schedules the job to run every specified number of hours.
@@ -182,10 +205,19 @@ func (j *Job) PerMinuit(minuit int) *Job {
 //
 //	job.PerHour(4)
 func (j *Job) PerHour(hour int) *Job {
-	if j.RunTime.Type == "" {
-		j.RunTime.Type = HourlyRunType
-		j.RunTime.Time = time.Duration(hour) * time.Hour
-	}
+	return j.schedule(HourlyRunType, time.Duration(hour)*time.Hour)
+}
+
+// SilenceAlerts stops reporting the errors of the job to the ErrorReporter; they are still logged.
+//
+// Returns:
+//   - *Job: The modified Job instance
+//
+// Example:
+//
+//	job.PerMinuit(1).SilenceAlerts()
+func (j *Job) SilenceAlerts() *Job {
+	j.AlertsSilenced = true
 	return j
 }
 
@@ -202,20 +234,98 @@ func (j *Job) OnOneServer() *Job {
 	return j
 }
 
+// Validate checks that the job has a handler, exactly one valid timing method and a valid random delay.
+//
+// Returns:
+//   - error: nil if the job is valid, otherwise every problem found, wrapping the Err* values
+//
+// Example:
+//
+//	if err := job.Validate(); err != nil {
+//	    log.Fatal(err)
+//	}
+func (j *Job) Validate() error {
+	errs := append([]error(nil), j.errs...)
+
+	if j.Handler == nil {
+		errs = append(errs, ErrNoHandler)
+	}
+
+	switch j.RunTime.Type {
+	case "":
+		errs = append(errs, ErrNoSchedule)
+	case DailyRunType:
+		times := j.RunTime.Time.([]string)
+		if len(times) == 0 {
+			errs = append(errs, fmt.Errorf("%w: no time given", ErrInvalidTime))
+		}
+
+		for _, t := range times {
+			if _, err := time.Parse(time.TimeOnly, t); err != nil {
+				errs = append(errs, fmt.Errorf("%w: %q is not HH:MM:SS", ErrInvalidTime, t))
+			}
+		}
+	case SecondlyRunType, MinutelyRunType, HourlyRunType:
+		if interval := j.RunTime.Time.(time.Duration); interval <= 0 {
+			errs = append(errs, fmt.Errorf("%w: %s", ErrInvalidInterval, interval))
+		}
+	}
+
+	if d := j.RunTime.RandomDelay; d != nil && (d.Min < 0 || d.Max < d.Min) {
+		errs = append(errs, fmt.Errorf("%w: min %d, max %d", ErrInvalidDelay, d.Min, d.Max))
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("job %s: %w", j.Name, errors.Join(errs...))
+}
+
+// schedule sets the timing of the job; a second timing method is recorded as an error for Validate.
+func (j *Job) schedule(runType RunType, value interface{}) *Job {
+	if j.RunTime.Type != "" {
+		j.errs = append(j.errs, fmt.Errorf("%w: %s after %s", ErrMultipleSchedules, runType, j.RunTime.Type))
+		return j
+	}
+
+	j.RunTime.Type = runType
+	j.RunTime.Time = value
+
+	return j
+}
+
 // runWithRecover executes the job handler with panic recovery.
 func (j *Job) runWithRecover() {
-	ctx := context.WithValue(context.Background(), logger.TraceIDKey, j.TraceID.New())
+	// Don't start new runs once the scheduler is stopping
+	if !j.scheduler.begin(j) {
+		return
+	}
+	defer j.scheduler.end(j)
+
+	ctx := j.context()
 
 	defer func() {
 		// Recover from panic and log the error
 		if r := recover(); r != nil {
 			j.Logger.Error(ctx, "job has a panic error", zap.Any("error", r))
+			j.reportError(ctx, fmt.Errorf("panic: %v", r))
 		}
 	}()
 
 	j.handler(ctx)
 }
 
+// context returns the context of a job run, carrying a new trace ID and the job arguments.
+func (j *Job) context() context.Context {
+	ctx := context.WithValue(context.Background(), logger.TraceIDKey, j.TraceID.New())
+	if j.Args != nil {
+		ctx = context.WithValue(ctx, argsKey{}, j.Args)
+	}
+
+	return ctx
+}
+
 // run executes the job based on its schedule type.
 func (j *Job) run() {
 	switch j.RunTime.Type {
@@ -223,12 +333,9 @@ func (j *Job) run() {
 		// Run the job immediately
 		go j.runWithRecover()
 	case DailyRunType:
-		// Check if current time matches any of the scheduled times
-		times := j.RunTime.Time.([]string)
-		for _, t := range times {
-			if time.Now().Format("15:04:05") == t {
-				go j.runWithRecover()
-			}
+		// Run once for each scheduled time that is due
+		for range j.dueTimes(j.now()) {
+			go j.runWithRecover()
 		}
 	case SecondlyRunType, MinutelyRunType, HourlyRunType:
 		// Ensure the job is only started once
@@ -239,18 +346,68 @@ func (j *Job) run() {
 		j.RunTime.PerTypeLocked = true
 		go func() {
 			ticker := time.NewTicker(j.RunTime.Time.(time.Duration))
-			for range ticker.C {
-				go j.runWithRecover()
+			defer ticker.Stop()
+
+			for {
+				select {
+				case <-j.scheduler.stopped():
+					return
+				case <-ticker.C:
+					go j.runWithRecover()
+				}
 			}
 		}()
 	}
 }
 
+// dueTimes returns the daily times due at now, i.e. passed by less than dailyWindow and not run
+// on that day yet, and records them as run. A time just before midnight is still due on the
+// first ticks of the next day.
+//
+// Parameters:
+//   - now: The current time
+//
+// Returns:
+//   - []string: The due times, in the order given to DailyAt
+func (j *Job) dueTimes(now time.Time) []string {
+	var due []string
+	for _, t := range j.RunTime.Time.([]string) {
+		at, err := time.ParseInLocation(time.TimeOnly, t, now.Location())
+		if err != nil {
+			continue // Rejected by Validate
+		}
+
+		// The window of yesterday's occurrence may extend past midnight
+		for _, day := range []time.Time{now, now.AddDate(0, 0, -1)} {
+			target := time.Date(day.Year(), day.Month(), day.Day(), at.Hour(), at.Minute(), at.Second(), 0, now.Location())
+			date := target.Format(time.DateOnly)
+
+			if now.Before(target) || now.Sub(target) >= dailyWindow || j.RunTime.LastRuns[t] == date {
+				continue
+			}
+
+			if j.RunTime.LastRuns == nil {
+				j.RunTime.LastRuns = make(map[string]string)
+			}
+			j.RunTime.LastRuns[t] = date
+
+			due = append(due, t)
+
+			break
+		}
+	}
+
+	return due
+}
+
 // handler manages the job execution process, including locking and error handling.
 //
 // Parameters:
 //   - ctx: Context for the job execution
 func (j *Job) handler(ctx context.Context) {
+	// Tracks the completion watcher and the lock renewal, so the run ends once the lock is released
+	var cleanup sync.WaitGroup
+
 	if !j.EnableOverlapping {
 		// Prevent overlapping executions
 		if j.RunTime.Locked {
@@ -261,12 +418,16 @@ func (j *Job) handler(ctx context.Context) {
 
 	if !j.EnableMultipleServers {
 		// Ensure the job runs on only one server
-		if !j.lock("Server", DefaultServerLockTTL, false) {
+		if !j.lock(ctx, "Server", DefaultServerLockTTL, false) {
 			j.RunTime.Locked = false
 			return
 		}
 
-		go j.renewalServerLock(ctx)
+		cleanup.Add(1)
+		go func() {
+			defer cleanup.Done()
+			j.renewalServerLock(ctx)
+		}()
 	}
 
 	// Apply random delay if set
@@ -275,13 +436,16 @@ func (j *Job) handler(ctx context.Context) {
 	j.Logger.Info(ctx, util.SpliceStr("The scheduled job: ", j.Name, " starts execution."))
 
 	// Handle job execution and potential errors
+	cleanup.Add(1)
 	go func(ctx context.Context) {
+		defer cleanup.Done()
 	Exit:
 		for {
 			select {
 			case err := <-j.Handler.Error():
 				if err != nil {
 					j.Logger.Error(ctx, fmt.Sprintf("An error occurred while executing the %s.", j.Name), zap.Error(err))
+					j.reportError(ctx, err)
 				}
 			case <-j.Handler.Done():
 				// Clean up after job completion
@@ -299,6 +463,16 @@ func (j *Job) handler(ctx context.Context) {
 	}(ctx)
 
 	j.Handler.Exec(ctx)
+	cleanup.Wait()
+}
+
+// reportError passes err to the ErrorReporter unless alerts of the job are silenced.
+func (j *Job) reportError(ctx context.Context, err error) {
+	if j.ErrorReporter == nil || j.AlertsSilenced {
+		return
+	}
+
+	j.ErrorReporter(ctx, j.Name, err)
 }
 
 // randomDelay applies a random delay within the specified range before job execution.
@@ -310,39 +484,43 @@ func (j *Job) randomDelay() {
 	source := rand.NewSource(time.Now().UnixNano())
 	generator := rand.New(source)
 
-	delay := generator.Intn(j.RunTime.RandomDelay.Max) + j.RunTime.RandomDelay.Min
+	delay := generator.Intn(j.RunTime.RandomDelay.Max-j.RunTime.RandomDelay.Min+1) + j.RunTime.RandomDelay.Min
 	time.Sleep(time.Duration(delay) * time.Second)
 }
 
-// lock attempts to acquire or renew a Redis lock for the job.
+// lock attempts to acquire or renew the distributed lock for the job.
 //
 // Parameters:
+//   - ctx: Context for logging
 //   - name: Name of the lock
 //   - ttl: Time-to-live for the lock in seconds
 //   - renewal: Whether this is a lock renewal operation
 //
 // Returns:
 //   - bool: True if the lock was acquired or renewed successfully, false otherwise
-func (j *Job) lock(name string, ttl int, renewal bool) bool {
-	prefix := j.Redis.Prefix
-	key := util.SpliceStr(prefix, "schedule:jobLock:", j.Name, ":", name)
+func (j *Job) lock(ctx context.Context, name string, ttl int, renewal bool) bool {
+	key := util.SpliceStr("schedule:jobLock:", j.Name, ":", name)
+
+	var (
+		ok  bool
+		err error
+	)
 
 	if renewal {
-		_, err := j.Redis.Do("EXPIRE", key, ttl)
-		if err == nil {
-			return true
-		}
+		ok, err = j.Locker.Renew(ctx, key, time.Duration(ttl)*time.Second)
 	} else {
-		ok, err := j.Redis.Do("SET", key, "locked", "EX", ttl, "NX")
-		if ok != nil && err == nil {
-			return true
-		}
+		ok, err = j.Locker.Acquire(ctx, key, time.Duration(ttl)*time.Second)
+	}
+
+	if err != nil {
+		j.Logger.Error(ctx, util.SpliceStr("lock job:", name, " failed"), zap.Error(err))
+		return false
 	}
 
-	return false
+	return ok
 }
 
-// unLock releases the Redis lock for the job.
+// unLock releases the distributed lock for the job.
 //
 // Parameters:
 //   - ctx: Context for logging
@@ -350,8 +528,7 @@ func (j *Job) lock(name string, ttl int, renewal bool) bool {
 func (j *Job) unLock(ctx context.Context, name string) {
 	key := util.SpliceStr("schedule:jobLock:", j.Name, ":", name)
 
-	ok, err := j.Redis.Del(key)
-	if !ok && err != nil {
+	if err := j.Locker.Release(ctx, key); err != nil {
 		j.Logger.Error(ctx, util.SpliceStr("unLock job:", name, "failed"), zap.Error(err))
 	}
 }
@@ -367,7 +544,7 @@ Exit:
 		select {
 		case <-ticker.C:
 			// Renew the lock every second
-			j.lock("Server", DefaultServerLockTTL, true)
+			j.lock(ctx, "Server", DefaultServerLockTTL, true)
 		case <-j.RunTime.Done:
 			// Release the lock when the job is done
 			j.unLock(ctx, "Server")
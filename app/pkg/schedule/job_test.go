@@ -0,0 +1,263 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package schedule
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// testHandler is a HandlerFunc doing nothing.
+type testHandler struct{}
+
+func (testHandler) Exec(context.Context)  {}
+func (testHandler) Error() <-chan error   { return nil }
+func (testHandler) Done() <-chan struct{} { return nil }
+
+func TestJobValidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(s *Schedule) *Job
+		want  []error
+	}{
+		{
+			name:  "valid",
+			build: func(s *Schedule) *Job { return s.AddJob("job", testHandler{}).PerMinuit(5).RandomDelay(0, 10) },
+		},
+		{
+			name:  "no handler",
+			build: func(s *Schedule) *Job { return s.AddJob("job", nil).PerSeconds(1) },
+			want:  []error{ErrNoHandler},
+		},
+		{
+			name:  "no timing method",
+			build: func(s *Schedule) *Job { return s.AddJob("job", testHandler{}) },
+			want:  []error{ErrNoSchedule},
+		},
+		{
+			name:  "two timing methods",
+			build: func(s *Schedule) *Job { return s.AddJob("job", testHandler{}).PerHour(1).DailyAt("07:30:00") },
+			want:  []error{ErrMultipleSchedules},
+		},
+		{
+			name:  "invalid daily time",
+			build: func(s *Schedule) *Job { return s.AddJob("job", testHandler{}).DailyAt("7:30") },
+			want:  []error{ErrInvalidTime},
+		},
+		{
+			name:  "zero interval",
+			build: func(s *Schedule) *Job { return s.AddJob("job", testHandler{}).PerSeconds(0) },
+			want:  []error{ErrInvalidInterval},
+		},
+		{
+			name:  "delay max below min",
+			build: func(s *Schedule) *Job { return s.AddJob("job", nil).PerSeconds(1).RandomDelay(60, 30) },
+			want:  []error{ErrNoHandler, ErrInvalidDelay},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.build(New(nil, nil, nil)).Validate()
+			if len(tt.want) == 0 && err != nil {
+				t.Fatalf("Validate() error = %v, want nil", err)
+			}
+
+			for _, want := range tt.want {
+				if !errors.Is(err, want) {
+					t.Errorf("Validate() error = %v, want %v", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestScheduleStartRejectsInvalidJobs(t *testing.T) {
+	s := New(nil, nil, nil)
+	s.AddJob("valid", testHandler{}).PerMinuit(1)
+	s.AddJob("invalid", testHandler{})
+
+	if err := s.Start(); !errors.Is(err, ErrNoSchedule) {
+		t.Errorf("Start() error = %v, want %v", err, ErrNoSchedule)
+	}
+}
+
+func TestJobDueTimesWindow(t *testing.T) {
+	day := time.Date(2024, 5, 20, 0, 0, 0, 0, time.Local)
+
+	tests := []struct {
+		name string
+		at   string
+		want []string
+	}{
+		{name: "before", at: "07:29:59", want: nil},
+		{name: "exact second", at: "07:30:00", want: []string{"07:30:00"}},
+		{name: "late tick", at: "07:30:03", want: []string{"07:30:00"}},
+		{name: "past the window", at: "07:30:05", want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := New(nil, nil, nil).AddJob("job", testHandler{}).DailyAt("07:30:00", "18:00:00")
+
+			at, _ := time.ParseInLocation(time.TimeOnly, tt.at, time.Local)
+			now := day.Add(time.Duration(at.Hour())*time.Hour + time.Duration(at.Minute())*time.Minute + time.Duration(at.Second())*time.Second)
+
+			if got := j.dueTimes(now); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("dueTimes(%s) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJobDueTimesOncePerDay(t *testing.T) {
+	j := New(nil, nil, nil).AddJob("job", testHandler{}).DailyAt("07:30:00")
+	target := time.Date(2024, 5, 20, 7, 30, 0, 0, time.Local)
+
+	runs := 0
+	for offset := time.Duration(0); offset < dailyWindow; offset += 500 * time.Millisecond {
+		runs += len(j.dueTimes(target.Add(offset)))
+	}
+
+	if runs != 1 {
+		t.Errorf("dueTimes() ran %d times within the window, want 1", runs)
+	}
+
+	if got := j.dueTimes(target.AddDate(0, 0, 1)); len(got) != 1 {
+		t.Errorf("dueTimes() the next day = %v, want [07:30:00]", got)
+	}
+}
+
+func TestJobDailyAtJitter(t *testing.T) {
+	tests := []struct {
+		name  string
+		build func(s *Schedule) *Job
+		want  RandomDelay
+	}{
+		{
+			name:  "default",
+			build: func(s *Schedule) *Job { return s.AddJob("job", testHandler{}).DailyAt("07:30:00") },
+			want:  RandomDelay{Max: DefaultDailyJitter},
+		},
+		{
+			name:  "delay after",
+			build: func(s *Schedule) *Job { return s.AddJob("job", testHandler{}).DailyAt("07:30:00").RandomDelay(0, 0) },
+			want:  RandomDelay{},
+		},
+		{
+			name:  "delay before",
+			build: func(s *Schedule) *Job { return s.AddJob("job", testHandler{}).RandomDelay(10, 60).DailyAt("07:30:00") },
+			want:  RandomDelay{Min: 10, Max: 60},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			j := tt.build(New(nil, nil, nil))
+			if got := *j.RunTime.RandomDelay; got != tt.want {
+				t.Errorf("RandomDelay = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+// fakeClock is a clock advanced by the test.
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+func TestJobDailyAtFakeClock(t *testing.T) {
+	tests := []struct {
+		name  string
+		at    string
+		start time.Time
+		skip  time.Time // The tick that never happens
+	}{
+		{
+			name:  "tick skipped on the target second",
+			at:    "07:30:00",
+			start: time.Date(2024, 5, 20, 7, 29, 55, 0, time.Local),
+			skip:  time.Date(2024, 5, 20, 7, 30, 0, 0, time.Local),
+		},
+		{
+			name:  "window across midnight",
+			at:    "23:59:59",
+			start: time.Date(2024, 5, 20, 23, 59, 55, 0, time.Local),
+			skip:  time.Date(2024, 5, 20, 23, 59, 59, 0, time.Local),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clock := &fakeClock{now: tt.start}
+
+			j := New(nil, nil, nil).AddJob("job", testHandler{}).DailyAt(tt.at)
+			j.now = clock.Now
+
+			runs := 0
+			for i := 0; i < 20; i++ {
+				clock.now = clock.now.Add(time.Second)
+				if clock.now.Equal(tt.skip) {
+					continue
+				}
+
+				runs += len(j.dueTimes(j.now()))
+			}
+
+			if runs != 1 {
+				t.Errorf("DailyAt(%s) ran %d times across the boundary, want 1", tt.at, runs)
+			}
+		})
+	}
+}
+
+func TestScheduleStopWaitsForRunningJobs(t *testing.T) {
+	s := New(nil, nil, nil)
+	j := s.AddJob("report", testHandler{}).PerMinuit(1)
+
+	if !s.begin(j) {
+		t.Fatal("begin() = false before Stop")
+	}
+
+	go func() {
+		time.Sleep(2 * stopPollInterval)
+		s.end(j)
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := s.Stop(ctx); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+
+	if s.begin(j) {
+		t.Error("begin() = true after Stop")
+	}
+}
+
+func TestScheduleStopTimeout(t *testing.T) {
+	s := New(nil, nil, nil)
+	j := s.AddJob("report", testHandler{}).PerMinuit(1)
+	s.begin(j)
+
+	ctx, cancel := context.WithTimeout(context.Background(), stopPollInterval)
+	defer cancel()
+
+	if err := s.Stop(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Stop() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	if got := s.runningJobs(); !reflect.DeepEqual(got, []string{"report"}) {
+		t.Errorf("runningJobs() = %v, want [report]", got)
+	}
+}
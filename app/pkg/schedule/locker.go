@@ -0,0 +1,201 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package schedule
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/qiniu/qmgo"
+	"github.com/qiniu/qmgo/options"
+	"github.com/sk-pkg/redis"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	mgoptions "go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DefaultLockCollection is the MongoDB collection used by MongoLocker.
+const DefaultLockCollection = "schedule_lock"
+
+// Locker is a distributed lock used to run a job on only one server.
+//
+// Implementations must be safe for concurrent use. A lock expires on its own after ttl,
+// so a crashed server never holds it forever; long-running holders keep it with Renew.
+type Locker interface {
+	// Acquire tries to take the lock, returning false if it is held by someone else.
+	Acquire(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Renew extends a held lock by ttl, returning false if the lock is no longer held.
+	Renew(ctx context.Context, key string, ttl time.Duration) (bool, error)
+	// Release releases a held lock.
+	Release(ctx context.Context, key string) error
+}
+
+const (
+	// renewLockScript extends the lock key only while it still holds the owner token.
+	renewLockScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("EXPIRE", KEYS[1], ARGV[2]) end return 0`
+	// releaseLockScript deletes the lock key only while it still holds the owner token.
+	releaseLockScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) end return 0`
+)
+
+// lockStore is the part of redis.Manager used by RedisLocker.
+type lockStore interface {
+	SetNX(key string, value any, sec int) (bool, error)
+	Lua(keyCount int, script string, keysAndArgs []string) (any, error)
+}
+
+// RedisLocker is a Locker backed by Redis SET NX keys.
+//
+// Each lock key holds the owner token of the locker that took it, and Renew and Release
+// compare it in a Lua script before touching the key, so a lock that expired and was taken
+// over by another server is never extended or deleted by its former holder.
+type RedisLocker struct {
+	store  lockStore
+	prefix string
+	owner  string
+}
+
+// NewRedisLocker creates a Locker backed by Redis.
+//
+// Parameters:
+//   - redis: A redis.Manager instance holding the lock keys
+//
+// Returns:
+//   - *RedisLocker: A new RedisLocker instance
+//
+// Example:
+//
+//	locker := NewRedisLocker(redisInstance)
+func NewRedisLocker(redis *redis.Manager) *RedisLocker {
+	return &RedisLocker{
+		store:  redis,
+		prefix: redis.Prefix,
+		owner:  primitive.NewObjectID().Hex(),
+	}
+}
+
+// Acquire tries to take the lock with a Redis SET NX of the owner token.
+func (l *RedisLocker) Acquire(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	return l.store.SetNX(key, l.owner, int(ttl.Seconds()))
+}
+
+// Renew extends the expiry of the lock key if this locker still holds it.
+func (l *RedisLocker) Renew(_ context.Context, key string, ttl time.Duration) (bool, error) {
+	reply, err := l.store.Lua(1, renewLockScript, []string{l.prefix + key, l.owner, strconv.Itoa(int(ttl.Seconds()))})
+	if err != nil {
+		return false, err
+	}
+
+	ok, _ := reply.(int64)
+
+	return ok == 1, nil
+}
+
+// Release deletes the lock key if this locker still holds it.
+func (l *RedisLocker) Release(_ context.Context, key string) error {
+	_, err := l.store.Lua(1, releaseLockScript, []string{l.prefix + key, l.owner})
+	return err
+}
+
+// lockDocument is a lock stored by MongoLocker.
+type lockDocument struct {
+	Key      string    `bson:"_id"`
+	Owner    string    `bson:"owner"`
+	ExpireAt time.Time `bson:"expire_at"`
+}
+
+// MongoLocker is a Locker backed by a MongoDB collection, for deployments without Redis.
+//
+// Each lock is a document keyed by the lock name. Expired locks are taken over on Acquire,
+// and a TTL index on "expire_at" (see EnsureIndexes) removes abandoned ones.
+type MongoLocker struct {
+	collection *qmgo.Collection
+	owner      string
+}
+
+// NewMongoLocker creates a Locker backed by the DefaultLockCollection collection of db.
+//
+// Parameters:
+//   - db: A qmgo.Database instance holding the lock collection
+//
+// Returns:
+//   - *MongoLocker: A new MongoLocker instance
+//
+// Example:
+//
+//	locker := NewMongoLocker(mongoInstance)
+//	if err := locker.EnsureIndexes(ctx); err != nil {
+//	    log.Fatal(err)
+//	}
+func NewMongoLocker(db *qmgo.Database) *MongoLocker {
+	return &MongoLocker{
+		collection: db.Collection(DefaultLockCollection),
+		owner:      primitive.NewObjectID().Hex(),
+	}
+}
+
+// EnsureIndexes creates the TTL index that removes expired locks.
+//
+// Parameters:
+//   - ctx: Context for the database operation
+//
+// Returns:
+//   - error: An error if the index could not be created
+func (l *MongoLocker) EnsureIndexes(ctx context.Context) error {
+	err := l.collection.CreateOneIndex(ctx, options.IndexModel{
+		Key:          []string{"expire_at"},
+		IndexOptions: mgoptions.Index().SetExpireAfterSeconds(0),
+	})
+	if err != nil {
+		return fmt.Errorf("create lock TTL index failed: %w", err)
+	}
+
+	return nil
+}
+
+// Acquire inserts the lock document, or takes over an expired one.
+//
+// A lock that is held and not expired makes the upsert collide on "_id", which reports false.
+func (l *MongoLocker) Acquire(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	now := time.Now()
+	filter := bson.M{"_id": key, "expire_at": bson.M{"$lte": now}}
+
+	_, err := l.collection.Upsert(ctx, filter, lockDocument{Key: key, Owner: l.owner, ExpireAt: now.Add(ttl)})
+	if qmgo.IsDup(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Renew extends the expiry of a lock held by this locker.
+func (l *MongoLocker) Renew(ctx context.Context, key string, ttl time.Duration) (bool, error) {
+	filter := bson.M{"_id": key, "owner": l.owner}
+
+	err := l.collection.UpdateOne(ctx, filter, bson.M{"$set": bson.M{"expire_at": time.Now().Add(ttl)}})
+	if errors.Is(err, qmgo.ErrNoSuchDocuments) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Release removes a lock held by this locker.
+func (l *MongoLocker) Release(ctx context.Context, key string) error {
+	err := l.collection.Remove(ctx, bson.M{"_id": key, "owner": l.owner})
+	if errors.Is(err, qmgo.ErrNoSuchDocuments) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,117 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package schedule
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// fakeLockStore is an in-memory lockStore with a clock advanced by the test.
+//
+// It runs renewLockScript and releaseLockScript with the semantics of the Lua scripts.
+type fakeLockStore struct {
+	now    time.Time
+	values map[string]string
+	expiry map[string]time.Time
+}
+
+func newFakeLockStore() *fakeLockStore {
+	return &fakeLockStore{
+		now:    time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		values: make(map[string]string),
+		expiry: make(map[string]time.Time),
+	}
+}
+
+func (s *fakeLockStore) get(key string) (string, bool) {
+	if !s.now.Before(s.expiry[key]) {
+		delete(s.values, key)
+	}
+
+	value, ok := s.values[key]
+
+	return value, ok
+}
+
+func (s *fakeLockStore) SetNX(key string, value any, sec int) (bool, error) {
+	if _, ok := s.get(key); ok {
+		return false, nil
+	}
+
+	s.values[key] = value.(string)
+	s.expiry[key] = s.now.Add(time.Duration(sec) * time.Second)
+
+	return true, nil
+}
+
+func (s *fakeLockStore) Lua(_ int, script string, keysAndArgs []string) (any, error) {
+	key, owner := keysAndArgs[0], keysAndArgs[1]
+	if value, ok := s.get(key); !ok || value != owner {
+		return int64(0), nil
+	}
+
+	switch script {
+	case renewLockScript:
+		sec, err := strconv.Atoi(keysAndArgs[2])
+		if err != nil {
+			return nil, err
+		}
+
+		s.expiry[key] = s.now.Add(time.Duration(sec) * time.Second)
+	case releaseLockScript:
+		delete(s.values, key)
+	}
+
+	return int64(1), nil
+}
+
+func TestRedisLockerExpiredAndReacquired(t *testing.T) {
+	ctx := context.Background()
+	store := newFakeLockStore()
+	first := &RedisLocker{store: store, owner: "first"}
+	second := &RedisLocker{store: store, owner: "second"}
+
+	if ok, err := first.Acquire(ctx, "lock", 10*time.Second); err != nil || !ok {
+		t.Fatalf("first Acquire() = %v, %v, want true", ok, err)
+	}
+
+	if ok, _ := second.Acquire(ctx, "lock", 10*time.Second); ok {
+		t.Fatal("second Acquire() of a held lock = true, want false")
+	}
+
+	// The first holder outlives its TTL and the second one takes the lock over
+	store.now = store.now.Add(11 * time.Second)
+
+	if ok, err := second.Acquire(ctx, "lock", 10*time.Second); err != nil || !ok {
+		t.Fatalf("second Acquire() of an expired lock = %v, %v, want true", ok, err)
+	}
+
+	if ok, err := first.Renew(ctx, "lock", 10*time.Second); err != nil || ok {
+		t.Errorf("former holder Renew() = %v, %v, want false", ok, err)
+	}
+
+	if err := first.Release(ctx, "lock"); err != nil {
+		t.Fatalf("former holder Release() error = %v", err)
+	}
+
+	if owner, ok := store.get("lock"); !ok || owner != "second" {
+		t.Errorf("lock after former holder Release() = %q, %v, want held by second", owner, ok)
+	}
+
+	if ok, err := second.Renew(ctx, "lock", 10*time.Second); err != nil || !ok {
+		t.Errorf("holder Renew() = %v, %v, want true", ok, err)
+	}
+
+	if err := second.Release(ctx, "lock"); err != nil {
+		t.Fatalf("holder Release() error = %v", err)
+	}
+
+	if _, ok := store.get("lock"); ok {
+		t.Error("lock still held after holder Release()")
+	}
+}
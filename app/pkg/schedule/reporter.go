@@ -0,0 +1,67 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package schedule
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ErrorReporter receives an error of a job, from its Error channel or a recovered panic.
+type ErrorReporter func(ctx context.Context, job string, err error)
+
+// throttleState tracks the reports of one job.
+type throttleState struct {
+	last       time.Time // Time of the last report passed on
+	suppressed int       // Errors dropped since then
+}
+
+// Throttle returns an ErrorReporter passing at most one error per job to report within interval,
+// so a job failing on every run does not flood the operators.
+//
+// Errors dropped in between are counted, and the count is passed with the next error reported
+// for the same job.
+//
+// Parameters:
+//   - interval: The minimum time between two reports of the same job
+//   - report: The function receiving the errors passed on and the number of errors dropped before them
+//
+// Returns:
+//   - ErrorReporter: The throttled reporter
+//
+// Example:
+//
+//	scheduler.WithErrorReporter(Throttle(10*time.Minute, func(ctx context.Context, job string, err error, suppressed int) {
+//	    log.Printf("job %s failed (%d more suppressed): %v", job, suppressed, err)
+//	}))
+func Throttle(interval time.Duration, report func(ctx context.Context, job string, err error, suppressed int)) ErrorReporter {
+	var mu sync.Mutex
+	states := make(map[string]*throttleState)
+
+	return func(ctx context.Context, job string, err error) {
+		mu.Lock()
+
+		state, ok := states[job]
+		if !ok {
+			state = &throttleState{}
+			states[job] = state
+		}
+
+		now := time.Now()
+		if !state.last.IsZero() && now.Sub(state.last) < interval {
+			state.suppressed++
+			mu.Unlock()
+			return
+		}
+
+		suppressed := state.suppressed
+		state.last, state.suppressed = now, 0
+
+		mu.Unlock()
+
+		report(ctx, job, err, suppressed)
+	}
+}
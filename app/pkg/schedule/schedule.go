@@ -8,19 +8,36 @@
 package schedule
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/seakee/go-api/app/pkg/trace"
 	"github.com/sk-pkg/logger"
 	"github.com/sk-pkg/redis"
+	"go.uber.org/zap"
 )
 
+// stopPollInterval is how often Stop checks whether the running jobs are done.
+const stopPollInterval = 100 * time.Millisecond
+
 // Schedule represents the main scheduler structure.
 type Schedule struct {
 	Logger  *logger.Manager // Logger for the scheduler
 	Redis   *redis.Manager  // Redis client for distributed locking
+	Locker  Locker          // Distributed lock for single-server jobs, Redis-backed by default
 	Job     []*Job          // Slice of jobs managed by this scheduler
 	TraceID *trace.ID       // TraceID for logging and tracking
+	// ErrorReporter receives the errors of jobs added afterward, nil to only log them
+	ErrorReporter ErrorReporter
+
+	mu      sync.Mutex
+	running map[*Job]int  // Number of in-flight runs of each job
+	stop    chan struct{} // Closed by Stop
+	closed  bool
 }
 
 // New creates and returns a new Schedule instance.
@@ -37,12 +54,52 @@ type Schedule struct {
 //
 //	scheduler := New(loggerInstance, redisInstance, traceIDInstance)
 func New(logger *logger.Manager, redis *redis.Manager, traceID *trace.ID) *Schedule {
-	return &Schedule{
+	s := &Schedule{
 		Logger:  logger,
 		Redis:   redis,
 		Job:     make([]*Job, 0),
 		TraceID: traceID,
+		running: make(map[*Job]int),
+		stop:    make(chan struct{}),
 	}
+
+	if redis != nil {
+		s.Locker = NewRedisLocker(redis)
+	}
+
+	return s
+}
+
+// WithLocker replaces the distributed lock used by single-server jobs added afterward.
+//
+// Parameters:
+//   - locker: A Locker implementation, e.g. a MongoLocker for deployments without Redis
+//
+// Returns:
+//   - *Schedule: The modified Schedule instance
+//
+// Example:
+//
+//	scheduler := New(loggerInstance, nil, traceIDInstance).WithLocker(NewMongoLocker(mongoInstance))
+func (s *Schedule) WithLocker(locker Locker) *Schedule {
+	s.Locker = locker
+	return s
+}
+
+// WithErrorReporter sets the ErrorReporter of jobs added afterward.
+//
+// Parameters:
+//   - reporter: An ErrorReporter, e.g. one alerting the operators wrapped in Throttle
+//
+// Returns:
+//   - *Schedule: The modified Schedule instance
+//
+// Example:
+//
+//	scheduler.WithErrorReporter(Throttle(10*time.Minute, alertJobError))
+func (s *Schedule) WithErrorReporter(reporter ErrorReporter) *Schedule {
+	s.ErrorReporter = reporter
+	return s
 }
 
 // AddJob adds a new job to the scheduler.
@@ -75,11 +132,15 @@ func (s *Schedule) addJob(name string, handlerFunc HandlerFunc) *Job {
 		Name:                  name,
 		Logger:                s.Logger,
 		Redis:                 s.Redis,
+		Locker:                s.Locker,
 		Handler:               handlerFunc,
 		EnableMultipleServers: true,
 		EnableOverlapping:     true,
 		RunTime:               &RunTime{Done: make(chan struct{})},
 		TraceID:               s.TraceID,
+		ErrorReporter:         s.ErrorReporter,
+		now:                   time.Now,
+		scheduler:             s,
 	}
 
 	// Add the new job to the scheduler's job slice
@@ -90,17 +151,140 @@ func (s *Schedule) addJob(name string, handlerFunc HandlerFunc) *Job {
 
 // Start begins the scheduling process for all added jobs.
 //
-// This method starts a goroutine that ticks every second and
-// attempts to run each job in the scheduler.
-func (s *Schedule) Start() {
+// This method validates every job, then starts a goroutine that ticks every second and
+// attempts to run each job in the scheduler. Nothing is started if a job is invalid.
+//
+// Returns:
+//   - error: The Validate errors of the invalid jobs
+func (s *Schedule) Start() error {
+	var errs []error
+	for _, j := range s.Job {
+		if err := j.Validate(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
 	go func() {
 		// Create a ticker that fires every second
 		ticker := time.NewTicker(time.Second)
-		for range ticker.C {
-			// Attempt to run each job in the scheduler
-			for _, j := range s.Job {
-				j.run()
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-s.stop:
+				return
+			case <-ticker.C:
+				// Attempt to run each job in the scheduler
+				for _, j := range s.Job {
+					j.run()
+				}
 			}
 		}
 	}()
+
+	return nil
+}
+
+// Stop stops starting new job runs and waits for the in-flight ones to finish.
+//
+// The runs are not cancelled: each finishes and releases its single-server lock as usual.
+// If ctx is done first, the jobs still running are logged and left to the process exit.
+//
+// Parameters:
+//   - ctx: The context bounding the wait, e.g. with the shutdown timeout
+//
+// Returns:
+//   - error: The error of ctx if jobs were still running when it was done
+//
+// Example:
+//
+//	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+//	defer cancel()
+//	err := scheduler.Stop(ctx)
+func (s *Schedule) Stop(ctx context.Context) error {
+	s.mu.Lock()
+	if !s.closed {
+		s.closed = true
+		close(s.stop)
+	}
+	s.mu.Unlock()
+
+	ticker := time.NewTicker(stopPollInterval)
+	defer ticker.Stop()
+
+	for {
+		running := s.runningJobs()
+		if len(running) == 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			if s.Logger != nil {
+				s.Logger.Warn(ctx, "Scheduled jobs still running at shutdown", zap.Strings("jobs", running))
+			}
+
+			return fmt.Errorf("wait for scheduled jobs failed: %w", ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// begin records the start of a run of j; it reports false once the scheduler is stopped.
+func (s *Schedule) begin(j *Job) bool {
+	if s == nil {
+		return true
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return false
+	}
+
+	s.running[j]++
+
+	return true
+}
+
+// end records the end of a run of j.
+func (s *Schedule) end(j *Job) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.running[j]--; s.running[j] <= 0 {
+		delete(s.running, j)
+	}
+}
+
+// stopped returns a channel closed when the scheduler is stopped.
+func (s *Schedule) stopped() <-chan struct{} {
+	if s == nil {
+		return nil
+	}
+
+	return s.stop
+}
+
+// runningJobs returns the names of the jobs with in-flight runs, sorted.
+func (s *Schedule) runningJobs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := make([]string, 0, len(s.running))
+	for j := range s.running {
+		names = append(names, j.Name)
+	}
+	sort.Strings(names)
+
+	return names
 }
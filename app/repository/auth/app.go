@@ -8,8 +8,15 @@ package auth
 
 import (
 	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"time"
 
 	"github.com/seakee/go-api/app/model/auth"
+	"github.com/seakee/go-api/app/pkg/cache"
+	"github.com/sk-pkg/logger"
 	"github.com/sk-pkg/redis"
 	"gorm.io/gorm"
 )
@@ -24,12 +31,62 @@ type Repo interface {
 
 	// ExistAppByName checks if an application with the given name exists.
 	ExistAppByName(ctx context.Context, name string) (bool, error)
+
+	// ListApps retrieves all applications matching the non-zero fields of app.
+	ListApps(ctx context.Context, app *auth.App) ([]auth.App, error)
+
+	// PaginateApps retrieves a page of the applications matching the non-zero fields of app, with their total count.
+	PaginateApps(ctx context.Context, app *auth.App, order string, page, size int) ([]auth.App, int64, error)
+
+	// GetAppByCredentials retrieves an enabled application by its app_id and secret, through a cache.
+	GetAppByCredentials(ctx context.Context, appID, secret string) (*auth.App, error)
+
+	// UpdateApp applies updates to an application and invalidates its cached credentials.
+	UpdateApp(ctx context.Context, app *auth.App, updates map[string]interface{}) error
+
+	// DeleteApp deletes an application and invalidates its cached credentials.
+	DeleteApp(ctx context.Context, app *auth.App) error
+
+	// UpdateSecret replaces the secret of an application.
+	UpdateSecret(ctx context.Context, app *auth.App, secret string) error
+
+	// RetireSecret keeps a replaced secret of an application valid for a grace period.
+	RetireSecret(ctx context.Context, appID, secret string, ttl time.Duration) error
+
+	// IsRetiredSecret reports whether secret is a replaced secret of an application still in its grace period.
+	IsRetiredSecret(ctx context.Context, appID, secret string) (bool, error)
 }
 
+// credentialsKey prefixes the app_id in the Redis key caching the credentials of an app.
+const credentialsKey = "auth:app:credentials:"
+
+// credentialsTTL is the lifetime in seconds of cached credentials. It is kept short so changes
+// made outside this repository are picked up quickly.
+const credentialsTTL = 60
+
+// cachedCredentials is the cached form of an app; the secret is only stored as a hash.
+type cachedCredentials struct {
+	ID         uint   `json:"id"`
+	AppID      string `json:"app_id"`
+	AppName    string `json:"app_name"`
+	SecretHash string `json:"secret_hash"`
+	Status     int8   `json:"status"`
+}
+
+// retiredSecretKey prefixes the app_id in the Redis key holding the retired secret hash of an app.
+const retiredSecretKey = "auth:app:retired_secret:"
+
+// errUnknownApp is returned by the credentials loader for an unknown app_id, so it isn't cached.
+var errUnknownApp = errors.New("unknown app")
+
+// ErrRedisNotConfigured is returned by the retired secret operations when the repository has no Redis.
+var ErrRedisNotConfigured = errors.New("redis is not configured")
+
 // repo implements the Repo interface.
 type repo struct {
-	redis *redis.Manager
-	db    *gorm.DB
+	redis  *redis.Manager
+	db     *gorm.DB
+	logger *logger.Manager
 }
 
 // ExistAppByName checks if an application with the given name exists in the database.
@@ -108,11 +165,269 @@ func (r repo) GetApp(ctx context.Context, app *auth.App) (*auth.App, error) {
 	return app.First(ctx, r.db)
 }
 
+// ListApps retrieves all applications matching the non-zero fields of app.
+//
+// Parameters:
+//   - ctx: A context.Context for handling cancellation and timeouts.
+//   - app: A pointer to an auth.App holding the filter conditions.
+//
+// Returns:
+//   - []auth.App: The matching applications.
+//   - error: An error if the database operation fails.
+func (r repo) ListApps(ctx context.Context, app *auth.App) ([]auth.App, error) {
+	return app.List(ctx, r.db)
+}
+
+// PaginateApps retrieves a page of the applications matching the non-zero fields of app.
+//
+// Parameters:
+//   - ctx: A context.Context for handling cancellation and timeouts.
+//   - app: A pointer to an auth.App holding the filter conditions.
+//   - order: The ORDER BY clause, e.g. built by query.ParseSort; empty keeps the database order.
+//     It is interpolated into SQL and must never come from user input directly.
+//   - page: The page number (1-based).
+//   - size: The number of applications per page.
+//
+// Returns:
+//   - []auth.App: The applications of the page.
+//   - int64: The total number of matching applications.
+//   - error: An error if the database operation fails.
+//
+// Example:
+//
+//	apps, total, err := r.PaginateApps(ctx, &auth.App{Status: 1}, "id DESC", 1, 20)
+func (r repo) PaginateApps(ctx context.Context, app *auth.App, order string, page, size int) ([]auth.App, int64, error) {
+	db := r.db
+	if order != "" {
+		db = db.Order(order)
+	}
+
+	return app.Paginate(ctx, db, page, size)
+}
+
+// UpdateSecret replaces the secret of an application.
+//
+// Parameters:
+//   - ctx: A context.Context for handling cancellation and timeouts.
+//   - app: A pointer to the auth.App to update; its ID must be set.
+//   - secret: The new secret.
+//
+// Returns:
+//   - error: An error if the database operation fails.
+func (r repo) UpdateSecret(ctx context.Context, app *auth.App, secret string) error {
+	return r.UpdateApp(ctx, app, map[string]interface{}{"app_secret": secret})
+}
+
+// GetAppByCredentials retrieves an enabled application by its app_id and secret.
+//
+// The application is cached by app_id for a short time with its secret hashed, and the secret
+// is compared in code, so frequent service-to-service authentication does not hit the database.
+// Unknown app_ids are not cached, so an app can be used as soon as it is created, by this
+// repository or by anything writing to the table, e.g. a seeder; concurrent lookups of the same
+// unknown app_id still share one query. Without Redis the database is queried directly.
+//
+// Parameters:
+//   - ctx: A context.Context for handling cancellation and timeouts.
+//   - appID: The app_id of the application.
+//   - secret: The secret to check.
+//
+// Returns:
+//   - *auth.App: The application with its ID, app_id, name and status, or nil if the app_id is
+//     unknown, the secret does not match or the application is disabled. AppSecret is not set.
+//   - error: An error if the database operation fails.
+//
+// Example:
+//
+//	app, err := r.GetAppByCredentials(ctx, "go-api-abcdefgh", secret)
+//	if err == nil && app == nil {
+//	    // Invalid credentials
+//	}
+func (r repo) GetAppByCredentials(ctx context.Context, appID, secret string) (*auth.App, error) {
+	load := func(ctx context.Context) (*cachedCredentials, error) {
+		app, err := (&auth.App{AppID: appID}).First(ctx, r.db)
+		if err != nil {
+			return nil, err
+		}
+
+		// Fail the build so the miss isn't cached
+		if app == nil {
+			return nil, errUnknownApp
+		}
+
+		return &cachedCredentials{
+			ID:         app.ID,
+			AppID:      app.AppID,
+			AppName:    app.AppName,
+			SecretHash: hashSecret(app.AppSecret),
+			Status:     app.Status,
+		}, nil
+	}
+
+	var (
+		creds *cachedCredentials
+		err   error
+	)
+
+	if r.redis != nil {
+		creds, err = cache.Remember(ctx, r.logger, r.redis, credentialsKey+appID, credentialsTTL, load)
+	} else {
+		creds, err = load(ctx)
+	}
+
+	if errors.Is(err, errUnknownApp) {
+		return nil, nil
+	}
+
+	if err != nil || creds == nil || creds.Status != 1 {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(creds.SecretHash), []byte(hashSecret(secret))) != 1 {
+		return nil, nil
+	}
+
+	app := &auth.App{AppID: creds.AppID, AppName: creds.AppName, Status: creds.Status}
+	app.ID = creds.ID
+
+	return app, nil
+}
+
+// UpdateApp applies updates to an application and invalidates its cached credentials,
+// so secret and status changes take effect immediately.
+//
+// The cache is invalidated before the write, so a Redis failure leaves the app untouched, and
+// again after it, to drop credentials cached in between. Once the write is committed, a failure
+// of the second invalidation is not reported: the update took effect and a stale entry expires
+// within credentialsTTL.
+//
+// Parameters:
+//   - ctx: A context.Context for handling cancellation and timeouts.
+//   - app: A pointer to the auth.App to update; its ID must be set.
+//   - updates: The columns to update.
+//
+// Returns:
+//   - error: An error if the database operation or the first invalidation fails.
+//
+// Example:
+//
+//	err := r.UpdateApp(ctx, app, map[string]interface{}{"status": 2})
+func (r repo) UpdateApp(ctx context.Context, app *auth.App, updates map[string]interface{}) error {
+	if err := r.forgetCredentials(ctx, app); err != nil {
+		return err
+	}
+
+	if err := app.Updates(ctx, r.db, updates); err != nil {
+		return err
+	}
+
+	_ = r.forgetCredentials(ctx, app)
+
+	return nil
+}
+
+// DeleteApp deletes an application and invalidates its cached credentials, before and after
+// the delete like UpdateApp.
+//
+// Parameters:
+//   - ctx: A context.Context for handling cancellation and timeouts.
+//   - app: A pointer to the auth.App to delete; its ID must be set.
+//
+// Returns:
+//   - error: An error if the database operation or the first invalidation fails.
+func (r repo) DeleteApp(ctx context.Context, app *auth.App) error {
+	// Also resolves the app_id of the cache key while the row still exists
+	if err := r.forgetCredentials(ctx, app); err != nil {
+		return err
+	}
+
+	if err := app.Delete(ctx, r.db); err != nil {
+		return err
+	}
+
+	_ = r.forgetCredentials(ctx, app)
+
+	return nil
+}
+
+// forgetCredentials removes the cached credentials of app, resolving and setting its app_id
+// from its ID if needed.
+func (r repo) forgetCredentials(ctx context.Context, app *auth.App) error {
+	if r.redis == nil {
+		return nil
+	}
+
+	if app.AppID == "" {
+		found, err := r.GetApp(ctx, &auth.App{Model: gorm.Model{ID: app.ID}})
+		if err != nil || found == nil {
+			return err
+		}
+
+		app.AppID = found.AppID
+	}
+
+	return cache.Forget(r.redis, credentialsKey+app.AppID)
+}
+
+// RetireSecret keeps a replaced secret valid for ttl after a rotation.
+//
+// Only the SHA-256 hash of the secret is stored, in Redis with ttl as expiration; a later
+// rotation replaces it.
+//
+// Parameters:
+//   - ctx: A context.Context for handling cancellation and timeouts.
+//   - appID: The app_id of the application.
+//   - secret: The replaced secret.
+//   - ttl: The grace period.
+//
+// Returns:
+//   - error: An error if Redis is not configured or the write fails.
+func (r repo) RetireSecret(ctx context.Context, appID, secret string, ttl time.Duration) error {
+	if r.redis == nil {
+		return ErrRedisNotConfigured
+	}
+
+	return r.redis.SetString(retiredSecretKey+appID, hashSecret(secret), int(ttl.Seconds()))
+}
+
+// IsRetiredSecret reports whether secret is the retired secret of an application still in its grace period.
+//
+// Parameters:
+//   - ctx: A context.Context for handling cancellation and timeouts.
+//   - appID: The app_id of the application.
+//   - secret: The secret to check.
+//
+// Returns:
+//   - bool: True if secret matches the retired secret.
+//   - error: An error if Redis is not configured or the read fails.
+//
+// Example:
+//
+//	ok, err := r.IsRetiredSecret(ctx, "go-api-abcdefgh", secret)
+func (r repo) IsRetiredSecret(ctx context.Context, appID, secret string) (bool, error) {
+	if r.redis == nil {
+		return false, ErrRedisNotConfigured
+	}
+
+	hash, err := r.redis.GetString(retiredSecretKey + appID)
+	if err != nil || hash == "" {
+		return false, err
+	}
+
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(hashSecret(secret))) == 1, nil
+}
+
+// hashSecret returns the hex encoded SHA-256 hash of secret.
+func hashSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
 // NewAppRepo creates a new instance of the application repository.
 //
 // Parameters:
 //   - db: A pointer to the gorm.DB instance for database operations.
 //   - redis: A pointer to the redis.Manager for caching operations.
+//   - logger: A pointer to the logger.Manager logging the cache problems.
 //
 // Returns:
 //   - Repo: An implementation of the Repo interface.
@@ -121,7 +436,7 @@ func (r repo) GetApp(ctx context.Context, app *auth.App) (*auth.App, error) {
 //
 //	db := // initialize gorm.DB
 //	redisManager := // initialize redis.Manager
-//	appRepo := NewAppRepo(db, redisManager)
-func NewAppRepo(db *gorm.DB, redis *redis.Manager) Repo {
-	return &repo{redis: redis, db: db}
+//	appRepo := NewAppRepo(db, redisManager, logger)
+func NewAppRepo(db *gorm.DB, redis *redis.Manager, logger *logger.Manager) Repo {
+	return &repo{redis: redis, db: db, logger: logger}
 }
@@ -0,0 +1,52 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package seeder seeds sample and reference data into the database.
+//
+// Seed functions are generated per table by "go run ./command/codegen -seeder" and must be
+// added to Register to run. They upsert their rows, so running them again is safe.
+package seeder
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Seeder is a named seed function.
+type Seeder struct {
+	Name string                                       // Name of the seeder, usually the table it seeds
+	Seed func(ctx context.Context, db *gorm.DB) error // Function seeding the table
+}
+
+// Register returns the seeders of the application in the order they run.
+//
+// Only add generated seeders once their rows are filled in, e.g.
+// {Name: "auth_app", Seed: SeedAuthApp}.
+//
+// Returns:
+//   - []Seeder: The seeders to run; seeders of referenced tables must come first.
+func Register() []Seeder {
+	return []Seeder{}
+}
+
+// Run runs the given seeders in order, stopping at the first failure.
+//
+// Parameters:
+//   - ctx: context.Context for managing request-scoped values, cancellation signals, and deadlines.
+//   - db: *gorm.DB database connection.
+//   - seeders: The seeders to run.
+//
+// Returns:
+//   - error: error naming the failing seeder, otherwise nil.
+func Run(ctx context.Context, db *gorm.DB, seeders []Seeder) error {
+	for _, s := range seeders {
+		if err := s.Seed(ctx, db); err != nil {
+			return fmt.Errorf("seed %s failed: %w", s.Name, err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,138 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package audit records the semantic changes made by sensitive operations.
+//
+// Unlike the request log, which records every request, an audit record says who changed what
+// and how, e.g. "app go-api-a rotated the secret of app go-api-b". Services call Record after
+// a sensitive change succeeds; the actor and trace ID are taken from the context.
+package audit
+
+import (
+	"context"
+
+	"github.com/qiniu/qmgo"
+	"github.com/seakee/go-api/app/model/audit"
+	"github.com/sk-pkg/logger"
+	"go.uber.org/zap"
+)
+
+// Actions recorded by the services.
+const (
+	ActionAppCreate       = "app.create"
+	ActionAppRotateSecret = "app.rotate_secret"
+)
+
+// actorKey is the context key of the actor.
+type actorKey struct{}
+
+// Entry describes a change to record.
+type Entry struct {
+	Action string                 // What was done, one of the Action constants
+	Target string                 // What was changed, e.g. "app:go-api-abcdefgh"
+	Before map[string]interface{} // Summary of the state before the change; never include secrets
+	After  map[string]interface{} // Summary of the state after the change; never include secrets
+}
+
+// Service defines the audit operations.
+type Service interface {
+	// Record writes an audit record of a change. Failures are logged, never returned, so the
+	// audited operation is not failed after it succeeded.
+	Record(ctx context.Context, entry Entry)
+
+	// List returns a page of the audit records matching filter, newest first, and their total count.
+	List(ctx context.Context, filter audit.Filter, page, size int) ([]audit.Record, int64, error)
+}
+
+// service implements the Service interface.
+type service struct {
+	db     *qmgo.Database
+	logger *logger.Manager
+}
+
+// NewService creates a new instance of the audit service.
+//
+// Parameters:
+//   - db: The MongoDB database storing the records; nil disables auditing.
+//   - logger: The logger reporting records that could not be written.
+//
+// Returns:
+//   - Service: An implementation of the Service interface.
+//
+// Example:
+//
+//	auditService := audit.NewService(appCtx.MongoDB["go-api"], appCtx.Logger)
+func NewService(db *qmgo.Database, logger *logger.Manager) Service {
+	return &service{db: db, logger: logger}
+}
+
+// WithActor returns a copy of ctx carrying the actor of the changes made with it.
+//
+// Parameters:
+//   - ctx: The parent context.
+//   - actor: The actor, e.g. the app_id of the authenticated caller.
+//
+// Returns:
+//   - context.Context: The context carrying the actor.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// Actor returns the actor carried by ctx, or "" if there is none.
+func Actor(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// Record writes an audit record of a change.
+//
+// Parameters:
+//   - ctx: A context.Context carrying the actor and trace ID.
+//   - entry: The change to record.
+//
+// Example:
+//
+//	s.audit.Record(ctx, audit.Entry{Action: audit.ActionAppRotateSecret, Target: "app:" + app.AppID})
+func (s *service) Record(ctx context.Context, entry Entry) {
+	if s.db == nil {
+		return
+	}
+
+	traceID, _ := ctx.Value(logger.TraceIDKey).(string)
+
+	record := &audit.Record{
+		Actor:   Actor(ctx),
+		Action:  entry.Action,
+		Target:  entry.Target,
+		Before:  entry.Before,
+		After:   entry.After,
+		TraceID: traceID,
+	}
+
+	// Write the record even if the request is cancelled right after the change
+	if _, err := record.Create(context.WithoutCancel(ctx), s.db); err != nil && s.logger != nil {
+		s.logger.Error(ctx, "write audit record failed",
+			zap.String("action", entry.Action), zap.String("target", entry.Target), zap.Error(err))
+	}
+}
+
+// List returns a page of the audit records matching filter, newest first.
+//
+// Parameters:
+//   - ctx: A context.Context for handling cancellation and timeouts.
+//   - filter: The query conditions.
+//   - page: The page number (1-based).
+//   - size: The number of records per page.
+//
+// Returns:
+//   - []audit.Record: The records of the page.
+//   - int64: The total number of matching records.
+//   - error: An error if the query fails; with auditing disabled, the result is always empty.
+func (s *service) List(ctx context.Context, filter audit.Filter, page, size int) ([]audit.Record, int64, error) {
+	if s.db == nil {
+		return []audit.Record{}, 0, nil
+	}
+
+	return (&audit.Record{}).PaginationWithTotal(ctx, s.db, filter, page, size)
+}
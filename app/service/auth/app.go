@@ -0,0 +1,382 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package auth provides the business logic of app authentication.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	jwtlib "github.com/golang-jwt/jwt/v5"
+	"github.com/seakee/go-api/app/config"
+	"github.com/seakee/go-api/app/model/auth"
+	"github.com/seakee/go-api/app/pkg/e"
+	"github.com/seakee/go-api/app/pkg/jwt"
+	repo "github.com/seakee/go-api/app/repository/auth"
+	"github.com/seakee/go-api/app/service/audit"
+	"github.com/sk-pkg/util"
+	"gorm.io/gorm"
+)
+
+// defaultTokenExpire is the lifetime of app tokens in seconds when System.TokenExpire is unset (7 days).
+const defaultTokenExpire = 168 * 3600
+
+// AppService defines the app authentication operations.
+type AppService interface {
+	// IssueAppToken validates app credentials and mints a token for the app itself.
+	IssueAppToken(ctx context.Context, appID, appSecret string) (token string, expiresIn int64, err error)
+
+	// VerifyAppToken validates an app token and returns its claims.
+	VerifyAppToken(ctx context.Context, token string) (*jwt.ServerClaims, error)
+
+	// CreateApp creates an app with generated credentials.
+	CreateApp(ctx context.Context, params *CreateAppParams) (*auth.App, error)
+
+	// RotateSecret replaces the secret of an app and returns the new one.
+	RotateSecret(ctx context.Context, id uint) (newSecret string, err error)
+
+	// GetApp returns the app with the given ID.
+	GetApp(ctx context.Context, id uint) (*auth.App, error)
+
+	// ListApps returns all apps matching params.
+	ListApps(ctx context.Context, params *ListAppParams) ([]auth.App, error)
+
+	// PaginateApps returns a page of the apps matching params and their total count.
+	PaginateApps(ctx context.Context, params *ListAppParams, page, size int) ([]auth.App, int64, error)
+}
+
+// CreateAppParams defines the attributes of a new app.
+type CreateAppParams struct {
+	AppName     string // Unique app name
+	Description string // Free-form description
+	RedirectUri string // Redirect URI of the app
+}
+
+// ListAppParams defines the filters of app listings; zero values don't filter.
+type ListAppParams struct {
+	AppName string // Exact app name
+	Status  int8   // 1: Active; 2: Disabled
+	Order   string // ORDER BY clause of PaginateApps, built with query.ParseSort; empty keeps the database order
+}
+
+// toModel converts the filters into the model used as query conditions.
+func (p *ListAppParams) toModel() *auth.App {
+	if p == nil {
+		return &auth.App{}
+	}
+
+	return &auth.App{AppName: p.AppName, Status: p.Status}
+}
+
+// appService implements the AppService interface.
+type appService struct {
+	repo  repo.Repo
+	audit audit.Service
+}
+
+// NewAppService creates a new instance of the app service.
+//
+// Parameters:
+//   - repo: The app repository used to look up credentials.
+//   - audit: The audit service recording sensitive changes.
+//
+// Returns:
+//   - AppService: An implementation of the AppService interface.
+//
+// Example:
+//
+//	appService := NewAppService(auth.NewAppRepo(db, redisManager, logger), audit.NewService(mongoDB, logger))
+func NewAppService(repo repo.Repo, audit audit.Service) AppService {
+	return &appService{repo: repo, audit: audit}
+}
+
+// IssueAppToken validates app credentials and mints a JWT for service-to-service calls.
+//
+// The token has the "app" subject and carries the app's ID, app_id and name; it is signed with
+// the configured JWT secret and nothing is stored server-side. Only enabled apps get a token.
+// A secret replaced by RotateSecret is accepted until its grace period ends.
+//
+// Parameters:
+//   - ctx: A context.Context for handling cancellation and timeouts.
+//   - appID: The app_id of the app.
+//   - appSecret: The app_secret of the app.
+//
+// Returns:
+//   - token: The signed token.
+//   - expiresIn: The lifetime of the token in seconds.
+//   - err: An *e.APIError with e.InvalidParams, e.ServerAppNotFound or e.ServerAuthorizationFail,
+//     or the repository error.
+//
+// Example:
+//
+//	token, expiresIn, err := appService.IssueAppToken(ctx, "go-api-abcdefgh", secret)
+//	if err != nil {
+//	    return err
+//	}
+func (s *appService) IssueAppToken(ctx context.Context, appID, appSecret string) (string, int64, error) {
+	if appID == "" || appSecret == "" {
+		return "", 0, e.New(e.InvalidParams, nil)
+	}
+
+	app, err := s.getAppByCredentials(ctx, appID, appSecret)
+	if err != nil {
+		return "", 0, err
+	}
+
+	expire := tokenExpire()
+
+	token, err := jwt.GenerateAppToken(app, time.Duration(expire))
+	if err != nil {
+		return "", 0, e.New(e.ServerAuthorizationFail, err)
+	}
+
+	return token, expire, nil
+}
+
+// VerifyAppToken validates an app token issued by IssueAppToken.
+//
+// Parameters:
+//   - ctx: A context.Context for handling cancellation and timeouts.
+//   - token: The token to validate.
+//
+// Returns:
+//   - *jwt.ServerClaims: The claims of the token.
+//   - error: An *e.APIError with e.InvalidParams for an empty token, e.ServerAuthorizationExpired
+//     for an expired token, or e.ServerUnauthorized for any other invalid token.
+func (s *appService) VerifyAppToken(_ context.Context, token string) (*jwt.ServerClaims, error) {
+	if token == "" {
+		return nil, e.New(e.InvalidParams, nil)
+	}
+
+	claims, err := jwt.ParseAppAuth(token)
+	if err != nil {
+		if errors.Is(err, jwtlib.ErrTokenExpired) {
+			return nil, e.New(e.ServerAuthorizationExpired, err)
+		}
+
+		return nil, e.New(e.ServerUnauthorized, err)
+	}
+
+	return claims, nil
+}
+
+// CreateApp creates an enabled app with a generated app_id and secret.
+//
+// The creation is audited, without the secret, which is only returned to the caller.
+//
+// Parameters:
+//   - ctx: A context.Context for handling cancellation and timeouts.
+//   - params: The attributes of the app.
+//
+// Returns:
+//   - *auth.App: The created app, carrying its secret.
+//   - err: An *e.APIError with e.ServerAppAlreadyExists if the name is taken, or e.BUSY if the
+//     app can't be saved; or the repository error.
+//
+// Example:
+//
+//	app, err := appService.CreateApp(ctx, &CreateAppParams{AppName: "billing"})
+//	if err != nil {
+//	    return err
+//	}
+func (s *appService) CreateApp(ctx context.Context, params *CreateAppParams) (*auth.App, error) {
+	exists, err := s.repo.ExistAppByName(ctx, params.AppName)
+	if err != nil {
+		return nil, err
+	}
+
+	if exists {
+		return nil, e.New(e.ServerAppAlreadyExists, nil)
+	}
+
+	app := &auth.App{
+		AppName:     params.AppName,
+		AppID:       "go-api-" + util.RandLowStr(8),
+		AppSecret:   util.RandUpStr(32),
+		RedirectUri: params.RedirectUri,
+		Description: params.Description,
+		Status:      1,
+	}
+
+	if _, err = s.repo.Create(ctx, app); err != nil {
+		return nil, e.New(e.BUSY, fmt.Errorf("create app failed: %w", err))
+	}
+
+	s.audit.Record(ctx, audit.Entry{
+		Action: audit.ActionAppCreate,
+		Target: "app:" + app.AppID,
+		After:  map[string]interface{}{"app_name": app.AppName, "status": app.Status},
+	})
+
+	return app, nil
+}
+
+// RotateSecret replaces the secret of an app with a freshly generated one.
+//
+// When System.AppSecretGrace is set, the old secret stays valid for that long so clients can be
+// updated without downtime; otherwise it stops working at once. The cached credentials of the
+// app are invalidated, and the new secret is returned once and cannot be read back later.
+// The rotation is audited, without any secret.
+//
+// Parameters:
+//   - ctx: A context.Context for handling cancellation and timeouts.
+//   - id: The ID of the app.
+//
+// Returns:
+//   - newSecret: The new secret.
+//   - err: An *e.APIError with e.ServerAppNotFound, or the repository error.
+//
+// Example:
+//
+//	secret, err := appService.RotateSecret(ctx, 1)
+//	if err != nil {
+//	    return err
+//	}
+func (s *appService) RotateSecret(ctx context.Context, id uint) (string, error) {
+	app, err := s.repo.GetApp(ctx, &auth.App{Model: gorm.Model{ID: id}})
+	if err != nil {
+		return "", err
+	}
+
+	if app == nil {
+		return "", e.New(e.ServerAppNotFound, nil)
+	}
+
+	// Retire the old secret first, so a failure leaves the app untouched
+	grace := secretGrace()
+	if grace > 0 {
+		if err = s.repo.RetireSecret(ctx, app.AppID, app.AppSecret, grace); err != nil {
+			return "", fmt.Errorf("retire app secret failed: %w", err)
+		}
+	}
+
+	newSecret := util.RandUpStr(32)
+	if err = s.repo.UpdateSecret(ctx, app, newSecret); err != nil {
+		return "", fmt.Errorf("update app secret failed: %w", err)
+	}
+
+	s.audit.Record(ctx, audit.Entry{
+		Action: audit.ActionAppRotateSecret,
+		Target: "app:" + app.AppID,
+		After:  map[string]interface{}{"old_secret_grace_seconds": int64(grace / time.Second)},
+	})
+
+	return newSecret, nil
+}
+
+// GetApp returns the app with the given ID.
+//
+// The returned model carries the app secret; handlers must convert it into a response type
+// without it before rendering.
+//
+// Parameters:
+//   - ctx: A context.Context for handling cancellation and timeouts.
+//   - id: The ID of the app.
+//
+// Returns:
+//   - *auth.App: The app.
+//   - error: An *e.APIError with e.ServerAppNotFound, or the repository error.
+func (s *appService) GetApp(ctx context.Context, id uint) (*auth.App, error) {
+	app, err := s.repo.GetApp(ctx, &auth.App{Model: gorm.Model{ID: id}})
+	if err != nil {
+		return nil, err
+	}
+
+	if app == nil {
+		return nil, e.New(e.ServerAppNotFound, nil)
+	}
+
+	return app, nil
+}
+
+// ListApps returns all apps matching params, without pagination.
+//
+// Prefer PaginateApps for anything exposed to clients; this is meant for internal callers
+// that genuinely need every app.
+//
+// Parameters:
+//   - ctx: A context.Context for handling cancellation and timeouts.
+//   - params: The filters; nil lists every app.
+//
+// Returns:
+//   - []auth.App: The matching apps.
+//   - error: The repository error, if any.
+func (s *appService) ListApps(ctx context.Context, params *ListAppParams) ([]auth.App, error) {
+	return s.repo.ListApps(ctx, params.toModel())
+}
+
+// PaginateApps returns a page of the apps matching params and their total count.
+//
+// Parameters:
+//   - ctx: A context.Context for handling cancellation and timeouts.
+//   - params: The filters; nil matches every app.
+//   - page: The page number (1-based).
+//   - size: The number of apps per page.
+//
+// Returns:
+//   - []auth.App: The apps of the page.
+//   - int64: The total number of matching apps.
+//   - error: The repository error, if any.
+//
+// Example:
+//
+//	apps, total, err := appService.PaginateApps(ctx, &ListAppParams{Status: 1}, 1, 20)
+//	if err != nil {
+//	    return err
+//	}
+func (s *appService) PaginateApps(ctx context.Context, params *ListAppParams, page, size int) ([]auth.App, int64, error) {
+	var order string
+	if params != nil {
+		order = params.Order
+	}
+
+	return s.repo.PaginateApps(ctx, params.toModel(), order, page, size)
+}
+
+// getAppByCredentials returns the enabled app matching appID and appSecret, accepting a
+// retired secret still in its grace period.
+func (s *appService) getAppByCredentials(ctx context.Context, appID, appSecret string) (*auth.App, error) {
+	app, err := s.repo.GetAppByCredentials(ctx, appID, appSecret)
+	if err != nil || app != nil {
+		return app, err
+	}
+
+	if secretGrace() > 0 {
+		// Without Redis no secret is ever retired; checkConfig rejects a grace period without it
+		retired, err := s.repo.IsRetiredSecret(ctx, appID, appSecret)
+		if err != nil && !errors.Is(err, repo.ErrRedisNotConfigured) {
+			return nil, err
+		}
+
+		if retired {
+			app, err = s.repo.GetApp(ctx, &auth.App{AppID: appID, Status: 1})
+			if err != nil || app != nil {
+				return app, err
+			}
+		}
+	}
+
+	return nil, e.New(e.ServerAppNotFound, nil)
+}
+
+// secretGrace returns the configured grace period of rotated secrets.
+func secretGrace() time.Duration {
+	if cfg := config.Get(); cfg != nil {
+		return cfg.System.AppSecretGrace * time.Second
+	}
+
+	return 0
+}
+
+// tokenExpire returns the configured token lifetime in seconds.
+func tokenExpire() int64 {
+	if cfg := config.Get(); cfg != nil && cfg.System.TokenExpire > 0 {
+		return int64(cfg.System.TokenExpire)
+	}
+
+	return defaultTokenExpire
+}
@@ -0,0 +1,18 @@
+CREATE TABLE `auth_app`
+(
+    `id`           int                                                           NOT NULL AUTO_INCREMENT COMMENT 'id',
+    `app_id`       varchar(30) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci  NOT NULL COMMENT '应用ID',
+    `app_name`     varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci           DEFAULT NULL COMMENT '应用名称',
+    `app_secret`   varchar(256) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci NOT NULL COMMENT '应用的凭证密钥',
+    `redirect_uri` varchar(500) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci          DEFAULT NULL COMMENT '授权后重定向的回调链接地址',
+    `description`  text CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci COMMENT '描述信息',
+    `status`       tinyint(1)                                                    NOT NULL DEFAULT '0' COMMENT '0表示未开通；1表示正常使用；2表示已被禁用',
+    `created_at`   timestamp                                                     NULL     DEFAULT CURRENT_TIMESTAMP,
+    `updated_at`   timestamp                                                     NULL     DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    `deleted_at`   timestamp                                                     NULL     DEFAULT NULL,
+    PRIMARY KEY (`id`),
+    KEY `app_id` (`app_id`),
+    KEY `app_name` (`app_name`)
+) ENGINE = InnoDB
+  DEFAULT CHARSET = utf8mb4
+  COLLATE = utf8mb4_0900_ai_ci COMMENT ='接入的客户端信息表';
@@ -9,36 +9,61 @@ package bootstrap
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/seakee/go-api/app/config"
 	"github.com/sk-pkg/notify/lark"
+	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-resty/resty/v2"
 	"github.com/qiniu/qmgo"
 	"github.com/seakee/go-api/app/http/middleware"
+	"github.com/seakee/go-api/app/http/ws"
+	"github.com/seakee/go-api/app/pkg/alert"
+	"github.com/seakee/go-api/app/pkg/featureflag"
+	"github.com/seakee/go-api/app/pkg/health"
+	"github.com/seakee/go-api/app/pkg/httpclient"
+	"github.com/seakee/go-api/app/pkg/lang"
+	"github.com/seakee/go-api/app/pkg/larkcard"
+	"github.com/seakee/go-api/app/pkg/schedule"
 	"github.com/seakee/go-api/app/pkg/trace"
 	"github.com/sk-pkg/i18n"
 	"github.com/sk-pkg/kafka"
 	"github.com/sk-pkg/logger"
 	"github.com/sk-pkg/notify"
 	"github.com/sk-pkg/redis"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
 )
 
+// wsAlertChannel is the name routing alerts to the WebSocket connections in the notify configuration.
+const wsAlertChannel = "websocket"
+
 // App represents the main application structure, containing all necessary components and configurations.
 type App struct {
 	Config        *config.Config
 	Logger        *logger.Manager
 	Redis         map[string]*redis.Manager
-	I18n          *i18n.Manager
+	I18n          *lang.Manager
 	MysqlDB       map[string]*gorm.DB
 	MongoDB       map[string]*qmgo.Database
+	MongoClient   map[string]*qmgo.Client
 	Middleware    middleware.Middleware
 	KafkaProducer *kafka.Manager
 	KafkaConsumer *kafka.Manager
 	Mux           *gin.Engine
 	Notify        *notify.Manager
+	Alert         *alert.Alerter
 	TraceID       *trace.ID
+	HTTPClient    *resty.Client
+	WSHub         *ws.Hub
+	Health        *health.Checker // Readiness checks of the dependencies
+	Flags         *featureflag.Flags
+	server        *http.Server
+	schedule      *schedule.Schedule
+	kafkaProbe    *health.Kafka
 }
 
 // NewApp creates and initializes a new App instance.
@@ -51,10 +76,12 @@ type App struct {
 //   - error: An error if any initialization step fails.
 func NewApp(config *config.Config) (*App, error) {
 	a := &App{
-		Config:  config,
-		MysqlDB: map[string]*gorm.DB{},
-		MongoDB: map[string]*qmgo.Database{},
-		Redis:   map[string]*redis.Manager{},
+		Config:      config,
+		MysqlDB:     map[string]*gorm.DB{},
+		MongoDB:     map[string]*qmgo.Database{},
+		MongoClient: map[string]*qmgo.Client{},
+		Redis:       map[string]*redis.Manager{},
+		Health:      health.New(0),
 	}
 
 	// Initialize components
@@ -72,11 +99,22 @@ func NewApp(config *config.Config) (*App, error) {
 		return nil, err
 	}
 
+	a.loadFeatureFlags(ctx)
+
 	err = a.loadNotify()
 	if err != nil {
 		return a, err
 	}
 
+	a.loadWSHub()
+
+	err = a.loadAlert()
+	if err != nil {
+		return nil, err
+	}
+
+	a.loadHTTPClient(ctx)
+
 	err = a.loadI18n(ctx)
 	if err != nil {
 		return nil, err
@@ -87,6 +125,11 @@ func NewApp(config *config.Config) (*App, error) {
 		return nil, err
 	}
 
+	err = a.runSeeders(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	a.loadHTTPMiddlewares(ctx)
 	a.loadMux(ctx)
 
@@ -103,11 +146,45 @@ func NewApp(config *config.Config) (*App, error) {
 func (a *App) Start() {
 	ctx := context.WithValue(context.Background(), logger.TraceIDKey, a.TraceID.New())
 	// Start HTTP server
+	a.loadHTTPServer()
 	go a.startHTTPServer(ctx)
 	// Start Kafka consumer
 	go a.startKafkaConsumer(ctx)
 	// Start scheduled tasks
-	go a.startSchedule(ctx)
+	a.startSchedule(ctx)
+}
+
+// Shutdown gracefully stops the application.
+//
+// The HTTP server stops accepting connections, closes the WebSocket connections and waits for
+// the in-flight requests; the scheduler stops starting jobs and waits for the running ones.
+// Both wait until ctx is done at most.
+//
+// Parameters:
+//   - ctx: The context bounding the wait.
+//
+// Returns:
+//   - error: An error if requests or jobs didn't finish before ctx was done.
+func (a *App) Shutdown(ctx context.Context) error {
+	var errs []error
+
+	if a.server != nil {
+		if err := a.server.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("shutdown http server failed: %w", err))
+		}
+	}
+
+	if a.schedule != nil {
+		if err := a.schedule.Stop(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if a.kafkaProbe != nil {
+		_ = a.kafkaProbe.Close()
+	}
+
+	return errors.Join(errs...)
 }
 
 // loadTrace initializes the TraceID component.
@@ -166,6 +243,23 @@ func (a *App) loadRedis(ctx context.Context) error {
 	return nil
 }
 
+// loadFeatureFlags initializes the feature flags, with their overrides in the configured Redis.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+func (a *App) loadFeatureFlags(ctx context.Context) {
+	var store featureflag.Store
+	if r, ok := a.Redis[a.Config.Features.Redis]; ok {
+		store = r
+	} else if a.Config.Features.Redis != "" {
+		a.Logger.Warn(ctx, "Feature flag overrides disabled, unknown redis", zap.String("redis", a.Config.Features.Redis))
+	}
+
+	a.Flags = featureflag.New(a.Config.Features, store)
+
+	a.Logger.Info(ctx, "Feature flags loaded successfully")
+}
+
 // loadI18n initializes the internationalization component.
 //
 // Parameters:
@@ -174,8 +268,7 @@ func (a *App) loadRedis(ctx context.Context) error {
 // Returns:
 //   - error: An error if the i18n initialization fails.
 func (a *App) loadI18n(ctx context.Context) error {
-	var err error
-	a.I18n, err = i18n.New(
+	manager, err := i18n.New(
 		i18n.WithDebugMode(a.Config.System.DebugMode),
 		i18n.WithEnvKey(a.Config.System.EnvKey),
 		i18n.WithDefaultLang(a.Config.System.DefaultLang),
@@ -183,12 +276,23 @@ func (a *App) loadI18n(ctx context.Context) error {
 	)
 
 	if err == nil {
+		// Untranslated error codes fall back to the default language, then to the built-in message and code
+		a.I18n = lang.New(manager, a.Config.System.DefaultLang)
 		a.Logger.Info(ctx, "I18n loaded successfully")
 	}
 
 	return err
 }
 
+// loadHTTPClient initializes the shared outbound HTTP client.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+func (a *App) loadHTTPClient(ctx context.Context) {
+	a.HTTPClient = httpclient.Instrument(httpclient.New(a.Config.HTTPClient), a.Logger)
+	a.Logger.Info(ctx, "HTTP client loaded successfully")
+}
+
 // loadNotify initializes the notification component.
 func (a *App) loadNotify() error {
 	larksCount := len(a.Config.Notify.Lark.Larks)
@@ -221,3 +325,31 @@ func (a *App) loadNotify() error {
 
 	return nil
 }
+
+// loadWSHub initializes the WebSocket hub when WebSocket is enabled.
+func (a *App) loadWSHub() {
+	if a.Config.WebSocket.Enable {
+		a.WSHub = ws.NewHub(a.Config.WebSocket, a.Logger)
+	}
+}
+
+// loadAlert initializes the alert facade on top of the notify channels.
+func (a *App) loadAlert() error {
+	channels := make(map[string]alert.Channel)
+	if a.Config.Notify.Lark.Enable {
+		channels[string(notify.LarkChan)] = alert.NewLarkChannel(larkcard.NewSender(a.Notify.Lark, a.Config.Notify.Lark.Cards))
+	}
+
+	if a.WSHub != nil {
+		channels[wsAlertChannel] = ws.NewAlertChannel(a.WSHub)
+	}
+
+	alerter, err := alert.New(a.Config.Notify, channels)
+	if err != nil {
+		return err
+	}
+
+	a.Alert = alerter
+
+	return nil
+}
@@ -109,6 +109,7 @@ func (a *App) initMongo(ctx context.Context, db config.Database) error {
 	}
 
 	a.MongoDB[db.DbName] = cli.Database(db.DbName)
+	a.MongoClient[db.DbName] = cli
 
 	a.Logger.Info(ctx, fmt.Sprintf("MongoDB %s loaded successfully", db.DbName))
 
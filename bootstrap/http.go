@@ -8,25 +8,18 @@ import (
 	"context"
 	"errors"
 	"net/http"
-	"os"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	appHttp "github.com/seakee/go-api/app/http"
 	"github.com/seakee/go-api/app/http/middleware"
 	"github.com/seakee/go-api/app/http/router"
-	"github.com/sk-pkg/monitor"
+	"github.com/seakee/go-api/app/pkg/robot"
 	"go.uber.org/zap"
 )
 
-// startHTTPServer initializes and starts the HTTP server.
-//
-// Parameters:
-//   - ctx: The context for the operation.
-//
-// This function sets up the Gin engine, configures the server,
-// and starts listening for incoming HTTP requests.
-func (a *App) startHTTPServer(ctx context.Context) {
+// loadHTTPServer registers the routes and configures the HTTP server.
+func (a *App) loadHTTPServer() {
 	gin.SetMode(a.Config.System.RunMode)
 
 	appCtx := &appHttp.Context{
@@ -35,10 +28,16 @@ func (a *App) startHTTPServer(ctx context.Context) {
 		I18n:          a.I18n,
 		MysqlDB:       a.MysqlDB,
 		MongoDB:       a.MongoDB,
+		MongoClient:   a.MongoClient,
 		Middleware:    a.Middleware,
 		KafkaProducer: a.KafkaProducer,
 		Notify:        a.Notify,
+		Alert:         a.Alert,
 		Config:        a.Config,
+		HTTPClient:    a.HTTPClient,
+		WSHub:         a.WSHub,
+		Health:        a.Health,
+		Flags:         a.Flags,
 	}
 
 	router.Register(a.Mux, appCtx)
@@ -55,8 +54,20 @@ func (a *App) startHTTPServer(ctx context.Context) {
 		MaxHeaderBytes: maxHeaderBytes,
 	}
 
-	// Start listening for HTTP requests
-	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+	// Shutdown doesn't track hijacked connections, so close the WebSocket ones explicitly
+	if a.WSHub != nil {
+		server.RegisterOnShutdown(a.WSHub.Close)
+	}
+
+	a.server = server
+}
+
+// startHTTPServer starts listening for incoming HTTP requests.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+func (a *App) startHTTPServer(ctx context.Context) {
+	if err := a.server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
 		a.Logger.Fatal(ctx, "http server startup err", zap.Error(err))
 	}
 }
@@ -67,10 +78,16 @@ func (a *App) startHTTPServer(ctx context.Context) {
 //   - ctx: The context for the operation.
 //
 // This function configures the Gin engine with various middleware
-// and sets up panic monitoring if enabled.
+// including panic recovery and reporting.
 func (a *App) loadMux(ctx context.Context) {
 	mux := gin.New()
 
+	// Only trust X-Forwarded-For and X-Real-IP from the configured proxies. Without any, the
+	// headers are ignored and the client IP is the address of the peer, so it can't be spoofed.
+	if err := mux.SetTrustedProxies(a.Config.System.TrustedProxies); err != nil {
+		a.Logger.Fatal(ctx, "invalid trusted proxies", zap.Error(err))
+	}
+
 	mux.Use(a.Middleware.SetTraceID())
 
 	if a.Config.System.DebugMode {
@@ -78,37 +95,15 @@ func (a *App) loadMux(ctx context.Context) {
 	}
 
 	mux.Use(a.Middleware.Cors())
-	mux.Use(gin.Recovery())
-
-	a.loadPanicRobot(mux) // Setup panic monitoring
+	mux.Use(a.Middleware.Recovery()) // Recover from panics and report them to the panic robots and alert channels
+	mux.Use(a.Middleware.Timeout())  // Set the deadline of the request context
+	mux.Use(a.Middleware.IPFilter()) // Reject clients outside the configured IP lists
 
 	a.Mux = mux
 
 	a.Logger.Info(ctx, "Mux loaded successfully")
 }
 
-// loadPanicRobot sets up the panic monitoring robot.
-//
-// Parameters:
-//   - mux: The Gin engine to attach the panic robot middleware to.
-//
-// This function initializes the panic monitoring robot with the
-// configured settings and attaches its middleware to the Gin engine.
-func (a *App) loadPanicRobot(mux *gin.Engine) {
-	panicRobot, err := monitor.NewPanicRobot(
-		monitor.PanicRobotEnable(a.Config.Monitor.PanicRobot.Enable),
-		monitor.PanicRobotEnv(os.Getenv(a.Config.System.EnvKey)),
-		monitor.PanicRobotWechatEnable(a.Config.Monitor.PanicRobot.Wechat.Enable),
-		monitor.PanicRobotWechatPushUrl(a.Config.Monitor.PanicRobot.Wechat.PushUrl),
-		monitor.PanicRobotFeishuEnable(a.Config.Monitor.PanicRobot.Feishu.Enable),
-		monitor.PanicRobotFeishuPushUrl(a.Config.Monitor.PanicRobot.Feishu.PushUrl),
-	)
-
-	if err == nil {
-		mux.Use(panicRobot.Middleware())
-	}
-}
-
 // loadHTTPMiddlewares initializes the HTTP middleware.
 //
 // Parameters:
@@ -117,6 +112,7 @@ func (a *App) loadPanicRobot(mux *gin.Engine) {
 // This function sets up the middleware with various components
 // such as logger, i18n, databases, and Redis.
 func (a *App) loadHTTPMiddlewares(ctx context.Context) {
-	a.Middleware = middleware.New(a.Logger, a.I18n, a.MysqlDB, a.Redis, a.TraceID)
+	panicRobot := robot.New(a.Config.Monitor.PanicRobot, a.Config.System.Env, a.Logger)
+	a.Middleware = middleware.New(a.Logger, a.I18n, a.MysqlDB, a.Redis, a.TraceID, panicRobot, a.Alert)
 	a.Logger.Info(ctx, "Middlewares loaded successfully")
 }
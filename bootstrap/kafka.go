@@ -8,6 +8,7 @@ import (
 	"context"
 
 	"github.com/seakee/go-api/app/consumer"
+	"github.com/seakee/go-api/app/pkg/health"
 	"github.com/sk-pkg/kafka"
 )
 
@@ -47,7 +48,8 @@ func (a *App) startKafkaConsumer(ctx context.Context) {
 // Returns:
 //   - error: An error if any occurred during the initialization process, nil otherwise.
 //
-// This function sets up both Kafka producer and consumer if they are enabled in the configuration.
+// This function sets up both Kafka producer and consumer if they are enabled in the configuration,
+// and registers the Kafka readiness check when either is.
 // It uses the kafka package to create new instances with the specified options.
 func (a *App) loadKafka(ctx context.Context) error {
 	var err error
@@ -87,5 +89,15 @@ func (a *App) loadKafka(ctx context.Context) error {
 		a.Logger.Info(ctx, "Kafka Consumer loaded successfully")
 	}
 
+	// Report a broker outage on the readiness endpoint, unless Kafka isn't used
+	if a.Config.Kafka.ProducerEnable || a.Config.Kafka.ConsumerEnable {
+		a.kafkaProbe, err = health.NewKafka(a.Config.Kafka)
+		if err != nil {
+			return err
+		}
+
+		a.Health.Register("kafka", a.kafkaProbe.Ping)
+	}
+
 	return err
 }
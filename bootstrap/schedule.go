@@ -6,30 +6,119 @@ package bootstrap
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	"github.com/seakee/go-api/app/job"
+	"github.com/seakee/go-api/app/pkg/alert"
+	"github.com/seakee/go-api/app/pkg/larkcard"
 	"github.com/seakee/go-api/app/pkg/schedule"
+	"go.uber.org/zap"
 )
 
+// defaultJobAlertInterval is the minimum time between two failure alerts of the same job.
+const defaultJobAlertInterval = 10 * time.Minute
+
 // startSchedule initializes and starts the application's scheduling system.
 //
 // Parameters:
 //   - ctx: A context.Context for handling cancellation and timeouts.
 //
-// This function creates a new scheduler, registers jobs, and starts the scheduler.
+// This function creates a new scheduler, registers jobs, and starts the scheduler, which runs
+// the jobs in the background until App.Shutdown stops it.
 // It uses the application's logger, Redis connection, and TraceID for creating the scheduler.
 func (a *App) startSchedule(ctx context.Context) {
 	// Create a new scheduler instance
 	s := schedule.New(a.Logger, a.Redis["go-api"], a.TraceID)
+	if err := a.loadScheduleLocker(ctx, s); err != nil {
+		a.Logger.Fatal(ctx, "Schedule locker loading failed", zap.Error(err))
+	}
+
+	// Alert the operators when a job fails, at most once per interval and job
+	interval := defaultJobAlertInterval
+	if a.Config.Schedule.AlertInterval > 0 {
+		interval = time.Duration(a.Config.Schedule.AlertInterval) * time.Second
+	}
+
+	s.WithErrorReporter(schedule.Throttle(interval, a.alertJobError))
 
 	// Register jobs with the scheduler
 	// This function call sets up all the scheduled jobs for the application
-	job.Register(a.Logger, a.Redis, a.MysqlDB, a.Notify, s)
+	job.Register(a.Logger, a.Redis, a.MysqlDB, a.Notify, a.HTTPClient, s)
 
 	// Start the scheduler
 	// This will begin executing the registered jobs according to their schedules
-	s.Start()
+	if err := s.Start(); err != nil {
+		a.Logger.Fatal(ctx, "Invalid scheduled jobs", zap.Error(err))
+	}
+
+	a.schedule = s
 
 	// Log successful loading of the scheduler
 	a.Logger.Info(ctx, "Schedule loaded successfully")
 }
+
+// loadScheduleLocker selects the distributed lock backend of the scheduler from the configuration.
+//
+// Parameters:
+//   - ctx: A context.Context for handling cancellation and timeouts.
+//   - s: The scheduler whose locker is configured.
+//
+// Returns:
+//   - error: An error if the configured backend is unknown or its connection is not loaded.
+func (a *App) loadScheduleLocker(ctx context.Context, s *schedule.Schedule) error {
+	cfg := a.Config.Schedule
+
+	conn := cfg.LockConn
+	if conn == "" {
+		conn = a.Config.System.Name
+	}
+
+	switch cfg.LockDriver {
+	case "", "redis":
+		r, ok := a.Redis[conn]
+		if !ok {
+			return fmt.Errorf("redis %s is not loaded", conn)
+		}
+
+		s.WithLocker(schedule.NewRedisLocker(r))
+	case "mongo":
+		db, ok := a.MongoDB[conn]
+		if !ok {
+			return fmt.Errorf("mongo database %s is not loaded", conn)
+		}
+
+		locker := schedule.NewMongoLocker(db)
+		if err := locker.EnsureIndexes(ctx); err != nil {
+			return err
+		}
+
+		s.WithLocker(locker)
+	default:
+		return fmt.Errorf("unsupported schedule lock driver: %s", cfg.LockDriver)
+	}
+
+	return nil
+}
+
+// alertJobError sends a job failure alert through the alert facade.
+//
+// Parameters:
+//   - ctx: A context.Context carrying the trace ID of the job run.
+//   - job: The name of the failed job.
+//   - err: The error of the job.
+//   - suppressed: The number of failures of the job not alerted since the previous alert.
+func (a *App) alertJobError(ctx context.Context, job string, err error, suppressed int) {
+	card := larkcard.New("Scheduled job failed").
+		Text(err.Error()).
+		Field("Job", job).
+		Field("Env", a.Config.System.Env)
+
+	if suppressed > 0 {
+		card.Field("Suppressed", fmt.Sprintf("%d failures since the last alert", suppressed))
+	}
+
+	if alertErr := a.Alert.AlertCard(ctx, alert.ErrorLevel, "job_failure", card); alertErr != nil {
+		a.Logger.Error(ctx, "send job failure alert failed", zap.String("job", job), zap.Error(alertErr))
+	}
+}
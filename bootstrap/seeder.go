@@ -0,0 +1,50 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package bootstrap
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/seakee/go-api/app/seeder"
+)
+
+// seedEnvKey is the environment variable that triggers seeding at startup when set to "true".
+const seedEnvKey = "SEED"
+
+// runSeeders runs the registered seeders once at startup.
+//
+// Seeding only happens when the SEED environment variable is "true" and seeder.enable
+// is set in the configuration, so a stray variable cannot seed a production database.
+//
+// Parameters:
+//   - ctx: The context for the operation.
+//
+// Returns:
+//   - error: An error if the seeding database is not loaded or a seeder fails.
+func (a *App) runSeeders(ctx context.Context) error {
+	if os.Getenv(seedEnvKey) != "true" {
+		return nil
+	}
+
+	if !a.Config.Seeder.Enable {
+		a.Logger.Info(ctx, "Seeding skipped: seeder is disabled in config")
+		return nil
+	}
+
+	db, ok := a.MysqlDB[a.Config.Seeder.DbName]
+	if !ok {
+		return fmt.Errorf("seeder database %s is not loaded", a.Config.Seeder.DbName)
+	}
+
+	if err := seeder.Run(ctx, db, seeder.Register()); err != nil {
+		return err
+	}
+
+	a.Logger.Info(ctx, "Seeders ran successfully")
+
+	return nil
+}
@@ -0,0 +1,28 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package codegen
+
+import (
+	"fmt"
+	"go/format"
+)
+
+// formatGoCode formats the generated Go code like gofmt, using the standard library
+// so no external gofmt binary is needed.
+//
+// Parameters:
+//   - code: A string containing the generated Go code.
+//
+// Returns:
+//   - A string containing the formatted Go code, or the original code if it is not valid Go.
+//   - An error if the code cannot be parsed.
+func formatGoCode(code string) (string, error) {
+	formatted, err := format.Source([]byte(code))
+	if err != nil {
+		return code, fmt.Errorf("error formatting generated code: %w", err)
+	}
+
+	return string(formatted), nil
+}
@@ -5,12 +5,11 @@
 package codegen
 
 import (
-	"bytes"
 	"fmt"
 	"log"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -19,13 +18,47 @@ import (
 
 const defaultModelOutPath = "app/model"
 
+// Naming is a convention for deriving package, struct and file names from a table name.
+type Naming string
+
+const (
+	// NamingPackagePrefix uses the first segment of the table name as the package and the
+	// remaining segments, singularized, as the struct: auth_user_tokens → package auth,
+	// struct UserToken, file auth/user_token.go.
+	NamingPackagePrefix Naming = "prefix"
+	// NamingLastTwo uses the last two segments of the table name as package and struct,
+	// and every segment as a directory: auth_user_tokens → package user, struct Tokens,
+	// file auth/user/tokens.go. This was the only convention before Naming was added.
+	NamingLastTwo Naming = "last-two"
+)
+
 // Field represents a field in a database table.
 type Field struct {
-	Name     string // The name of the field in Go struct
-	Type     string // The Go type of the field
-	JsonName string // The JSON name of the field
-	GormTag  string // The GORM tag for the field
-	Comment  string // Comment associated with the field
+	Name            string   // The name of the field in Go struct
+	Type            string   // The Go type of the field
+	JsonName        string   // The JSON name of the field
+	GormTag         string   // The GORM tag for the field
+	Comment         string   // Comment associated with the field
+	SQLType         string   // The SQL column type as declared, e.g. "varchar(30)" or "int unsigned"
+	Size            int      // The declared length or precision, e.g. 30 for varchar(30); 0 if none
+	Scale           int      // The declared number of decimal places, e.g. 2 for decimal(10,2)
+	IsUnsigned      bool     // Whether the column is an unsigned number
+	IsAutoIncrement bool     // Whether the column is AUTO_INCREMENT
+	IsNullable      bool     // Whether the column accepts NULL
+	DefaultValue    string   // The column default without quotes; empty if there is none or it is NULL
+	EnumValues      []string // The allowed values of an enum column, in declaration order
+}
+
+// Enum describes the named string type generated for an enum column.
+type Enum struct {
+	TypeName string      // The name of the Go type, e.g. "AppStatus"
+	Values   []EnumValue // The constants of the type
+}
+
+// EnumValue is one constant of an Enum.
+type EnumValue struct {
+	Name  string // The name of the Go constant, e.g. "AppStatusActive"
+	Value string // The enum value as declared in SQL
 }
 
 // Model represents the structure of a database table.
@@ -35,6 +68,10 @@ type Model struct {
 	StructName  string              // The name of the Go struct
 	TableName   string              // The name of the database table
 	TableFields []Field             // The fields of the table
+	DryRun      bool                // Print the generated code to stdout instead of writing files
+	Naming      Naming              // The naming convention; defaults to NamingPackagePrefix
+	Enums       bool                // Generate a named string type with constants for enum columns
+	fileName    string              // The output file path relative to the model directory
 }
 
 // NewModel creates a new instance of Model.
@@ -105,8 +142,10 @@ func (m *Model) parseSQL(sql string) error {
 
 	// Process each line of the SQL schema.
 	for _, line := range lines {
+		// Keep the original line for values whose case matters, such as comments and defaults.
+		rawLine := strings.TrimSpace(line)
 		// Trim whitespace and convert the line to lowercase.
-		line = strings.TrimSpace(strings.ToLower(line))
+		line = strings.ToLower(rawLine)
 		// Split the line into parts based on spaces.
 		parts := strings.Fields(line)
 
@@ -130,34 +169,16 @@ func (m *Model) parseSQL(sql string) error {
 			}
 		default:
 			// Process lines that define fields.
-			name := strings.Trim(parts[0], "`")
+			field, importPath := m.parseColumn(rawLine)
 			// Skip certain predefined field names.
-			if name == "id" || name == "created_at" || name == "updated_at" || name == "deleted_at" {
+			if field.JsonName == "id" || field.JsonName == "created_at" || field.JsonName == "updated_at" || field.JsonName == "deleted_at" {
 				continue
 			}
 
-			// Extract the comment if it exists.
-			comment := ""
-			if strings.Trim(parts[len(parts)-2], "") == "comment" {
-				comment = strings.Trim(parts[len(parts)-1], "'`,")
-				comment = "// " + comment
-			}
-
-			// Determine the Go type and any required import for the field.
-			fieldType, importPath := m.getGoType(parts[1])
 			if importPath != "" {
 				m.Imports[importPath] = struct{}{}
 			}
 
-			// Create a Field struct with the extracted information.
-			field := Field{
-				Name:     strcase.ToCamel(name),          // Convert the field name to CamelCase.
-				Type:     fieldType,                      // Set the field type.
-				JsonName: name,                           // Set the JSON name for the field.
-				GormTag:  fmt.Sprintf("column:%s", name), // Set the GORM tag.
-				Comment:  comment,                        // Set the associated comment.
-			}
-
 			// Add the field to the Model's list of table fields.
 			m.TableFields = append(m.TableFields, field)
 		}
@@ -166,6 +187,257 @@ func (m *Model) parseSQL(sql string) error {
 	return nil
 }
 
+// parseColumn parses a column definition line of a CREATE TABLE statement.
+//
+// Parameters:
+//   - line: A string containing the column definition, e.g.
+//     "`status` tinyint(1) unsigned NOT NULL DEFAULT '0' COMMENT 'status',".
+//
+// Returns:
+//   - The Field describing the column, with its GORM tag generated.
+//   - A string representing the import path required for the Go type, if any.
+func (m *Model) parseColumn(line string) (Field, string) {
+	tokens := splitColumnTokens(strings.TrimSuffix(strings.TrimSpace(line), ","))
+
+	name := strings.Trim(tokens[0], "`")
+	field := Field{
+		Name:       strcase.ToCamel(name), // Convert the field name to CamelCase.
+		JsonName:   name,                  // Set the JSON name for the field.
+		IsNullable: true,                  // MySQL columns are nullable unless declared NOT NULL.
+	}
+
+	sqlType := strings.ToLower(tokens[1])
+	field.SQLType = sqlType
+	field.Size, field.Scale = parseTypeSize(sqlType)
+
+	if strings.HasPrefix(sqlType, "enum(") {
+		// Keep the case of the values from the original token.
+		field.SQLType = "enum" + tokens[1][len("enum"):]
+		field.EnumValues = parseEnumValues(tokens[1])
+	}
+
+	for i := 2; i < len(tokens); i++ {
+		next := ""
+		if i+1 < len(tokens) {
+			next = tokens[i+1]
+		}
+
+		switch strings.ToLower(tokens[i]) {
+		case "unsigned":
+			field.IsUnsigned = true
+			field.SQLType += " unsigned"
+		case "not":
+			if strings.ToLower(next) == "null" {
+				field.IsNullable = false
+				i++
+			}
+		case "default":
+			if strings.ToLower(next) != "null" {
+				field.DefaultValue = strings.Trim(next, "'\"")
+			}
+			i++
+		case "auto_increment":
+			field.IsAutoIncrement = true
+		case "comment":
+			// Set the associated comment.
+			field.Comment = "// " + strings.Trim(next, "'`")
+			i++
+		case "collate":
+			i++
+		case "character", "on":
+			// Skip "CHARACTER SET x" and "ON UPDATE x".
+			i += 2
+		}
+	}
+
+	// Determine the Go type and any required import for the field.
+	goType, importPath := m.getGoType(strings.Fields(sqlType)[0])
+	if field.IsUnsigned && strings.HasPrefix(goType, "int") {
+		goType = "u" + goType
+	}
+
+	field.Type = goType
+	field.GormTag = field.generateGormTag()
+
+	return field, importPath
+}
+
+// parseEnumValues extracts the allowed values from an enum type such as "enum('a','b')".
+//
+// Parameters:
+//   - sqlType: A string representing the enum type as declared.
+//
+// Returns:
+//   - A slice of the unquoted values, in declaration order.
+func parseEnumValues(sqlType string) []string {
+	open, end := strings.Index(sqlType, "("), strings.LastIndex(sqlType, ")")
+	if open < 0 || end < open {
+		return nil
+	}
+
+	var (
+		values  []string
+		current strings.Builder
+		quoted  bool
+	)
+
+	list := sqlType[open+1 : end]
+	for i := 0; i < len(list); i++ {
+		c := list[i]
+
+		switch {
+		case c == '\'' && quoted && i+1 < len(list) && list[i+1] == '\'':
+			// An escaped quote inside a value.
+			current.WriteByte(c)
+			i++
+		case c == '\'':
+			quoted = !quoted
+			if !quoted {
+				values = append(values, current.String())
+				current.Reset()
+			}
+		case quoted:
+			current.WriteByte(c)
+		}
+	}
+
+	return values
+}
+
+// buildEnums turns the enum columns of the model into named string types when Enums is enabled,
+// setting the type of each enum field to its generated type.
+//
+// Returns:
+//   - A slice of the Enum types to generate.
+func (m *Model) buildEnums() []Enum {
+	if !m.Enums {
+		return nil
+	}
+
+	var enums []Enum
+	for i, field := range m.TableFields {
+		if len(field.EnumValues) == 0 {
+			continue
+		}
+
+		enum := Enum{TypeName: m.StructName + field.Name}
+		for _, value := range field.EnumValues {
+			name := strcase.ToCamel(value)
+			if name == "" {
+				name = "Empty"
+			}
+
+			enum.Values = append(enum.Values, EnumValue{Name: enum.TypeName + name, Value: value})
+		}
+
+		m.TableFields[i].Type = enum.TypeName
+		enums = append(enums, enum)
+	}
+
+	return enums
+}
+
+// generateGormTag builds the GORM tag of the field from its column definition,
+// e.g. "column:status;type:tinyint(1) unsigned;not null;default:0".
+//
+// Returns:
+//   - A string containing the GORM tag without the surrounding gorm:"".
+func (f *Field) generateGormTag() string {
+	tags := []string{"column:" + f.JsonName}
+
+	if f.SQLType != "" {
+		tags = append(tags, "type:"+f.SQLType)
+	}
+
+	if !f.IsNullable {
+		tags = append(tags, "not null")
+	}
+
+	if f.DefaultValue != "" {
+		tags = append(tags, "default:"+f.DefaultValue)
+	}
+
+	if f.IsAutoIncrement {
+		tags = append(tags, "autoIncrement")
+	}
+
+	return strings.Join(tags, ";")
+}
+
+// splitColumnTokens splits a column definition on whitespace outside quotes and parentheses,
+// so "varchar(30)", "enum('a', 'b')" and "'a comment'" stay single tokens.
+//
+// Parameters:
+//   - line: A string containing the column definition.
+//
+// Returns:
+//   - A slice of the tokens of the definition.
+func splitColumnTokens(line string) []string {
+	var (
+		tokens  []string
+		current strings.Builder
+		quote   rune
+		depth   int
+	)
+
+	for _, r := range line {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case (r == ' ' || r == '\t') && depth == 0:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+			continue
+		}
+
+		current.WriteRune(r)
+	}
+
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+
+	return tokens
+}
+
+// parseTypeSize extracts the length/precision and scale from a SQL type such as
+// "varchar(30)" or "decimal(10,2)".
+//
+// Parameters:
+//   - sqlType: A string representing the SQL type.
+//
+// Returns:
+//   - The declared size, or 0 if there is none.
+//   - The declared scale, or 0 if there is none.
+func parseTypeSize(sqlType string) (int, int) {
+	open, end := strings.Index(sqlType, "("), strings.Index(sqlType, ")")
+	if open < 0 || end < open {
+		return 0, 0
+	}
+
+	sizeStr, scaleStr, _ := strings.Cut(sqlType[open+1:end], ",")
+
+	size, err := strconv.Atoi(strings.TrimSpace(sizeStr))
+	if err != nil {
+		// Not a number, e.g. the values of enum('a','b').
+		return 0, 0
+	}
+
+	scale, _ := strconv.Atoi(strings.TrimSpace(scaleStr))
+
+	return size, scale
+}
+
 // generateCode generates Go code for the model based on the parsed SQL schema.
 //
 // The function sets the package name and struct name based on the table name,
@@ -176,15 +448,13 @@ func (m *Model) parseSQL(sql string) error {
 //   - An error if there is an issue generating the code.
 func (m *Model) generateCode() (string, error) {
 	// Determine the package and struct names based on the table name.
-	parts := strings.Split(m.TableName, "_")
-	if len(parts) > 1 {
-		m.PackageName = parts[len(parts)-2]
-		m.StructName = strcase.ToCamel(parts[len(parts)-1])
-	} else {
-		m.PackageName = m.TableName
-		m.StructName = strcase.ToCamel(m.TableName)
+	if err := m.generateNames(); err != nil {
+		return "", err
 	}
 
+	// Generate named types for enum columns.
+	enums := m.buildEnums()
+
 	// Parse the model template.
 	tmpl := template.Must(template.New("model").Parse(modelTemplate))
 	var result strings.Builder
@@ -197,6 +467,7 @@ func (m *Model) generateCode() (string, error) {
 		"TableName":             m.TableName,
 		"TableFields":           m.TableFields,
 		"Imports":               m.Imports,
+		"Enums":                 enums,
 	})
 	if err != nil {
 		return "", err
@@ -205,6 +476,56 @@ func (m *Model) generateCode() (string, error) {
 	return result.String(), nil
 }
 
+// generateNames sets the package name, struct name and output file of the model
+// from the table name, following the configured naming convention.
+//
+// Returns:
+//   - An error if the naming convention is unknown.
+func (m *Model) generateNames() error {
+	parts := strings.Split(m.TableName, "_")
+
+	if len(parts) == 1 {
+		m.PackageName = m.TableName
+		m.StructName = strcase.ToCamel(m.TableName)
+		m.fileName = filepath.Join(m.TableName, m.TableName+".go")
+
+		return nil
+	}
+
+	switch m.Naming {
+	case "", NamingPackagePrefix:
+		rest := parts[1:]
+		rest[len(rest)-1] = singular(rest[len(rest)-1])
+
+		m.PackageName = parts[0]
+		m.StructName = strcase.ToCamel(strings.Join(rest, "_"))
+		m.fileName = filepath.Join(parts[0], strings.Join(rest, "_")+".go")
+	case NamingLastTwo:
+		m.PackageName = parts[len(parts)-2]
+		m.StructName = strcase.ToCamel(parts[len(parts)-1])
+		m.fileName = strings.ReplaceAll(m.TableName, "_", string(os.PathSeparator)) + ".go"
+	default:
+		return fmt.Errorf("unknown naming convention: %s", m.Naming)
+	}
+
+	return nil
+}
+
+// singular returns the singular form of a plural English word, covering the regular
+// plurals common in table names (tokens, categories, addresses); other words are returned as is.
+func singular(word string) string {
+	switch {
+	case strings.HasSuffix(word, "ies") && len(word) > 3:
+		return strings.TrimSuffix(word, "ies") + "y"
+	case strings.HasSuffix(word, "sses"), strings.HasSuffix(word, "xes"), strings.HasSuffix(word, "ches"), strings.HasSuffix(word, "shes"):
+		return strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") && !strings.HasSuffix(word, "us") && !strings.HasSuffix(word, "is"):
+		return strings.TrimSuffix(word, "s")
+	default:
+		return word
+	}
+}
+
 // readSQLFile reads the content of the specified SQL file.
 //
 // Parameters:
@@ -252,15 +573,19 @@ func (m *Model) WriteModelFile(force bool, outputPath, content string) error {
 	}
 
 	// Determine the output file path based on the table name.
-	parts := strings.Split(m.TableName, "_")
-	if len(parts) > 1 {
-		outputPath = filepath.Join(outputPath, strings.ReplaceAll(m.TableName, "_", string(os.PathSeparator))+".go")
-	} else {
-		outputPath = filepath.Join(outputPath, m.TableName, m.TableName+".go")
+	if m.fileName == "" {
+		if err := m.generateNames(); err != nil {
+			return err
+		}
 	}
+	outputPath = filepath.Join(outputPath, m.fileName)
 
 	log.Printf("Starting to write Model file: %s\n", outputPath)
 
+	if m.DryRun {
+		return m.previewModelFile(force, outputPath, content)
+	}
+
 	// Check if the file already exists and handle overwriting based on the force flag.
 	if !force {
 		if _, err := os.Stat(outputPath); err == nil {
@@ -281,6 +606,38 @@ func (m *Model) WriteModelFile(force bool, outputPath, content string) error {
 	return os.WriteFile(outputPath, []byte(content), 0644)
 }
 
+// previewModelFile prints what WriteModelFile would do, without touching the file system.
+//
+// It reports whether the file would be created, overwritten or skipped because it exists,
+// and prints the generated code unless the file would be skipped.
+//
+// Parameters:
+//   - force: A boolean indicating whether existing files would be overwritten.
+//   - outputPath: A string representing the path of the file that would be written.
+//   - content: A string containing the generated code.
+//
+// Returns:
+//   - An error if the existence of the file cannot be checked.
+func (m *Model) previewModelFile(force bool, outputPath, content string) error {
+	action := "create"
+	if _, err := os.Stat(outputPath); err == nil {
+		if !force {
+			log.Printf("[dry-run] %s already exists, would skip\n", outputPath)
+			return nil
+		}
+
+		action = "overwrite"
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	log.Printf("[dry-run] would %s %s\n", action, outputPath)
+
+	_, err := fmt.Fprintf(os.Stdout, "// ----- %s -----\n%s", outputPath, content)
+
+	return err
+}
+
 // Generate orchestrates the model generation process.
 //
 // It reads the SQL schema file, parses the schema, generates the Go code,
@@ -325,7 +682,7 @@ func (m *Model) Generate(force bool, sqlPath, outputPath string) error {
 	log.Printf("Starting to format %s Model...\n", m.StructName)
 
 	// Format the generated Go code.
-	formattedContent, err := m.formatGoCode(code)
+	formattedContent, err := formatGoCode(code)
 	if err != nil {
 		return err
 	}
@@ -342,28 +699,6 @@ func (m *Model) Generate(force bool, sqlPath, outputPath string) error {
 	return nil
 }
 
-// formatGoCode formats the generated Go code using 'gofmt'.
-//
-// Parameters:
-//   - code: A string containing the generated Go code.
-//
-// Returns:
-//   - A string containing the formatted Go code.
-//   - An error if there is an issue formatting the code.
-func (m *Model) formatGoCode(code string) (string, error) {
-	// Execute the gofmt command to format the code.
-	cmd := exec.Command("gofmt")
-	cmd.Stdin = strings.NewReader(code)
-
-	var out bytes.Buffer
-	cmd.Stdout = &out
-	if err := cmd.Run(); err != nil {
-		return "", err
-	}
-
-	return out.String(), nil
-}
-
 const modelTemplate = `// Copyright 2024 Seakee.  All rights reserved.
 // Use of this source code is governed by a MIT style
 // license that can be found in the LICENSE file.
@@ -374,12 +709,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	
 	{{- range $import, $value := .Imports}}
 	"{{$import}}"
 	{{- end}}
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type {{.StructName}} struct {
@@ -389,6 +726,28 @@ type {{.StructName}} struct {
 	{{.Name}} {{.Type}} ` + "`gorm:\"{{.GormTag}}\" json:\"{{.JsonName}}\"`" + ` {{.Comment}}
 	{{- end}}
 }
+{{- range .Enums}}
+{{$typeName := .TypeName}}
+// {{.TypeName}} is the type of the values allowed in its enum column.
+type {{.TypeName}} string
+
+// Allowed values of {{.TypeName}}.
+const (
+	{{- range .Values}}
+	{{.Name}} {{$typeName}} = {{printf "%q" .Value}}
+	{{- end}}
+)
+
+// Valid reports whether the value is one of the allowed values of {{.TypeName}}.
+func (v {{.TypeName}}) Valid() bool {
+	switch v {
+	case {{range $i, $value := .Values}}{{if $i}}, {{end}}{{$value.Name}}{{end}}:
+		return true
+	default:
+		return false
+	}
+}
+{{- end}}
 
 // TableName specifies the table name for the {{.StructName}} model.
 func ({{.StructNameFirstLetter}} *{{.StructName}}) TableName() string {
@@ -476,6 +835,33 @@ func ({{.StructNameFirstLetter}} *{{.StructName}}) Delete(ctx context.Context, d
 	return db.WithContext(ctx).Where({{.StructNameFirstLetter}}).Delete({{.StructNameFirstLetter}}).Error
 }
 
+// DeleteByIDs deletes the {{.StructNameLower}}s with the given IDs in a single statement.
+//
+// The rows are soft-deleted, as {{.StructName}} embeds gorm.Model.
+// An empty ids slice deletes nothing and doesn't query the database.
+//
+// Parameters:
+// 	- ctx: context.Context for managing request-scoped values, cancellation signals, and deadlines.
+// 	- db: *gorm.DB database connection.
+// 	- ids: IDs of the {{.StructNameLower}}s to delete.
+//
+// Returns:
+// 	- int64: number of {{.StructNameLower}}s deleted; IDs that don't exist or are already deleted aren't counted.
+// 	- error: error if the delete operation fails, otherwise nil.
+func ({{.StructNameFirstLetter}} *{{.StructName}}) DeleteByIDs(ctx context.Context, db *gorm.DB, ids []uint) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	// Perform the database delete operation with context.
+	result := db.WithContext(ctx).Where("id IN ?", ids).Delete(&{{.StructName}}{})
+	if result.Error != nil {
+		return 0, fmt.Errorf("delete by ids failed: %w", result.Error)
+	}
+
+	return result.RowsAffected, nil
+}
+
 // Updates applies the specified updates to the {{.StructNameLower}} in the database.
 //
 // Parameters:
@@ -532,6 +918,48 @@ func ({{.StructNameFirstLetter}} *{{.StructName}}) ListByArgs(ctx context.Contex
 	return {{.StructNameLower}}s, nil
 }
 
+// ListByIDs retrieves the {{.StructNameLower}}s with the given IDs from the database.
+//
+// The result follows the order of ids; IDs that don't exist are skipped.
+// An empty ids slice returns an empty result without querying the database.
+//
+// Parameters:
+// 	- ctx: context.Context for managing request-scoped values, cancellation signals, and deadlines.
+// 	- db: *gorm.DB database connection.
+// 	- ids: IDs of the {{.StructNameLower}}s to retrieve.
+//
+// Returns:
+// 	- []{{.StructName}}: slice of retrieved {{.StructNameLower}}s, ordered as ids.
+// 	- error: error if the query fails, otherwise nil.
+func ({{.StructNameFirstLetter}} *{{.StructName}}) ListByIDs(ctx context.Context, db *gorm.DB, ids []uint) ([]{{.StructName}}, error) {
+	if len(ids) == 0 {
+		return []{{.StructName}}{}, nil
+	}
+
+	var {{.StructNameLower}}s []{{.StructName}}
+
+	// Perform the database query with context.
+	if err := db.WithContext(ctx).Where("id IN ?", ids).Find(&{{.StructNameLower}}s).Error; err != nil {
+		return nil, fmt.Errorf("list by ids failed: %w", err)
+	}
+
+	// Restore the order of the input ids.
+	{{.StructNameLower}}Map := make(map[uint]{{.StructName}}, len({{.StructNameLower}}s))
+	for _, item := range {{.StructNameLower}}s {
+		{{.StructNameLower}}Map[item.ID] = item
+	}
+
+	ordered := make([]{{.StructName}}, 0, len({{.StructNameLower}}s))
+	for _, id := range ids {
+		if item, ok := {{.StructNameLower}}Map[id]; ok {
+			ordered = append(ordered, item)
+			delete({{.StructNameLower}}Map, id)
+		}
+	}
+
+	return ordered, nil
+}
+
 // CountByArgs counts the number of {{.StructNameLower}}s matching the specified query and arguments in the database.
 //
 // Parameters:
@@ -588,6 +1016,70 @@ func ({{.StructNameFirstLetter}} *{{.StructName}}) BatchInsert(ctx context.Conte
 	return db.WithContext(ctx).Create(&{{.StructNameLower}}s).Error
 }
 
+// Upsert inserts the {{.StructNameLower}}, or resolves a conflict on the given columns when a matching row already exists.
+//
+// Parameters:
+// 	- ctx: context.Context for managing request-scoped values, cancellation signals, and deadlines.
+// 	- db: *gorm.DB database connection.
+// 	- conflictColumns: columns forming the unique key that detects the conflict.
+// 	- updateColumns: columns to overwrite on conflict; if empty, the conflicting row is left untouched (DO NOTHING).
+//
+// Returns:
+// 	- error: error if the upsert operation fails, otherwise nil.
+func ({{.StructNameFirstLetter}} *{{.StructName}}) Upsert(ctx context.Context, db *gorm.DB, conflictColumns []string, updateColumns []string) error {
+	// Perform the database upsert operation with context.
+	if err := db.WithContext(ctx).Clauses({{.StructNameFirstLetter}}.onConflict(conflictColumns, updateColumns)).Create({{.StructNameFirstLetter}}).Error; err != nil {
+		return fmt.Errorf("upsert failed: %w", err)
+	}
+
+	return nil
+}
+
+// BatchUpsert inserts multiple {{.StructNameLower}}s in a single batch operation, resolving conflicts on the given columns.
+//
+// Parameters:
+// 	- ctx: context.Context for managing request-scoped values, cancellation signals, and deadlines.
+// 	- db: *gorm.DB database connection.
+// 	- {{.StructNameLower}}s: slice of {{.StructName}} instances to be inserted.
+// 	- conflictColumns: columns forming the unique key that detects the conflict.
+// 	- updateColumns: columns to overwrite on conflict; if empty, conflicting rows are skipped (DO NOTHING).
+//
+// Returns:
+// 	- error: error if the batch upsert operation fails, otherwise nil.
+func ({{.StructNameFirstLetter}} *{{.StructName}}) BatchUpsert(ctx context.Context, db *gorm.DB, {{.StructNameLower}}s []{{.StructName}}, conflictColumns []string, updateColumns []string) error {
+	if len({{.StructNameLower}}s) == 0 {
+		return nil
+	}
+
+	// Perform the database batch upsert operation with context.
+	if err := db.WithContext(ctx).Clauses({{.StructNameFirstLetter}}.onConflict(conflictColumns, updateColumns)).Create(&{{.StructNameLower}}s).Error; err != nil {
+		return fmt.Errorf("batch upsert failed: %w", err)
+	}
+
+	return nil
+}
+
+// onConflict builds the ON CONFLICT / ON DUPLICATE KEY clause used by Upsert and BatchUpsert.
+//
+// Parameters:
+// 	- conflictColumns: columns forming the unique key that detects the conflict.
+// 	- updateColumns: columns to overwrite on conflict; if empty, the clause becomes DO NOTHING.
+//
+// Returns:
+// 	- clause.OnConflict: the conflict resolution clause.
+func ({{.StructNameFirstLetter}} *{{.StructName}}) onConflict(conflictColumns []string, updateColumns []string) clause.OnConflict {
+	columns := make([]clause.Column, len(conflictColumns))
+	for i, name := range conflictColumns {
+		columns[i] = clause.Column{Name: name}
+	}
+
+	if len(updateColumns) == 0 {
+		return clause.OnConflict{Columns: columns, DoNothing: true}
+	}
+
+	return clause.OnConflict{Columns: columns, DoUpdates: clause.AssignmentColumns(updateColumns)}
+}
+
 // FindWithPagination retrieves {{.StructNameLower}}s matching the criteria from the database with pagination support.
 //
 // Parameters:
@@ -611,6 +1103,108 @@ func ({{.StructNameFirstLetter}} *{{.StructName}}) FindWithPagination(ctx contex
 	return {{.StructNameLower}}s, nil
 }
 
+// Paginate retrieves {{.StructNameLower}}s matching the criteria with pagination support, along with their total count.
+//
+// The count and the page are built from the same query, so the total always matches the
+// conditions of the page.
+//
+// Parameters:
+// 	- ctx: context.Context for managing request-scoped values, cancellation signals, and deadlines.
+// 	- db: *gorm.DB database connection.
+// 	- page: page number for pagination (1-based).
+// 	- size: number of {{.StructNameLower}}s per page.
+//
+// Returns:
+// 	- []{{.StructName}}: slice of retrieved {{.StructNameLower}}s.
+// 	- int64: total count of matching {{.StructNameLower}}s.
+// 	- error: error if the query fails, otherwise nil.
+func ({{.StructNameFirstLetter}} *{{.StructName}}) Paginate(ctx context.Context, db *gorm.DB, page, size int) ([]{{.StructName}}, int64, error) {
+	var (
+		{{.StructNameLower}}s []{{.StructName}}
+		total int64
+	)
+
+	query := db.WithContext(ctx).Model(&{{.StructName}}{}).Where({{.StructNameFirstLetter}})
+
+	// Count the matching {{.StructNameLower}}s before applying pagination.
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("paginate count failed: %w", err)
+	}
+
+	if total == 0 {
+		return []{{.StructName}}{}, 0, nil
+	}
+
+	// Perform the database query, applying offset and limit for pagination.
+	if err := query.Offset((page - 1) * size).Limit(size).Find(&{{.StructNameLower}}s).Error; err != nil {
+		return nil, 0, fmt.Errorf("paginate failed: %w", err)
+	}
+
+	return {{.StructNameLower}}s, total, nil
+}
+
+// Search retrieves {{.StructNameLower}}s whose given fields fuzzy-match the keyword, with pagination support.
+//
+// The keyword is matched with LIKE '%keyword%' against each field, combined with OR,
+// on top of the non-zero fields of the receiver. Wildcards in the keyword are escaped,
+// so '%' and '_' are matched literally. An empty keyword or fields list only applies
+// the receiver's conditions.
+//
+// Parameters:
+// 	- ctx: context.Context for managing request-scoped values, cancellation signals, and deadlines.
+// 	- db: *gorm.DB database connection.
+// 	- fields: column names to search; these are interpolated into SQL and must never come from user input.
+// 	- keyword: the search keyword.
+// 	- page: page number for pagination (1-based).
+// 	- size: number of {{.StructNameLower}}s per page.
+//
+// Returns:
+// 	- []{{.StructName}}: slice of retrieved {{.StructNameLower}}s.
+// 	- int64: total count of matching {{.StructNameLower}}s.
+// 	- error: error if the query fails, otherwise nil.
+func ({{.StructNameFirstLetter}} *{{.StructName}}) Search(ctx context.Context, db *gorm.DB, fields []string, keyword string, page, size int) ([]{{.StructName}}, int64, error) {
+	var (
+		{{.StructNameLower}}s []{{.StructName}}
+		total int64
+	)
+
+	query := db.WithContext(ctx).Model(&{{.StructName}}{}).Where({{.StructNameFirstLetter}})
+	if keyword != "" && len(fields) > 0 {
+		conditions := make([]string, len(fields))
+		args := make([]interface{}, len(fields))
+		pattern := "%" + {{.StructNameFirstLetter}}.escapeLike(keyword) + "%"
+		for i, field := range fields {
+			conditions[i] = field + " LIKE ?"
+			args[i] = pattern
+		}
+
+		query = query.Where("("+strings.Join(conditions, " OR ")+")", args...)
+	}
+
+	// Count the matching {{.StructNameLower}}s before applying pagination.
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, fmt.Errorf("search count failed: %w", err)
+	}
+
+	// Perform the database query, applying offset and limit for pagination.
+	if err := query.Offset((page - 1) * size).Limit(size).Find(&{{.StructNameLower}}s).Error; err != nil {
+		return nil, 0, fmt.Errorf("search failed: %w", err)
+	}
+
+	return {{.StructNameLower}}s, total, nil
+}
+
+// escapeLike escapes the LIKE wildcards in keyword so they are matched literally.
+//
+// Parameters:
+// 	- keyword: the raw search keyword.
+//
+// Returns:
+// 	- string: the escaped keyword.
+func ({{.StructNameFirstLetter}} *{{.StructName}}) escapeLike(keyword string) string {
+	return strings.NewReplacer(` + "`\\`, `\\\\`" + `, "%", ` + "`\\%`" + `, "_", ` + "`\\_`" + `).Replace(keyword)
+}
+
 // FindWithSort retrieves {{.StructNameLower}}s matching the criteria from the database with sorting support.
 //
 // Parameters:
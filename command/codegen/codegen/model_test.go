@@ -5,6 +5,9 @@
 package codegen
 
 import (
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -16,3 +19,132 @@ func TestModel_Generate(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestModel_generateNames(t *testing.T) {
+	tests := []struct {
+		table      string
+		naming     Naming
+		pkg        string
+		structName string
+		fileName   string
+	}{
+		{"auth_app", "", "auth", "App", filepath.Join("auth", "app.go")},
+		{"auth_user_tokens", NamingPackagePrefix, "auth", "UserToken", filepath.Join("auth", "user_token.go")},
+		{"shop_categories", NamingPackagePrefix, "shop", "Category", filepath.Join("shop", "category.go")},
+		{"sys_status", NamingPackagePrefix, "sys", "Status", filepath.Join("sys", "status.go")},
+		{"auth_user_tokens", NamingLastTwo, "user", "Tokens", filepath.Join("auth", "user", "tokens.go")},
+		{"user", NamingPackagePrefix, "user", "User", filepath.Join("user", "user.go")},
+	}
+
+	for _, tt := range tests {
+		m := &Model{TableName: tt.table, Naming: tt.naming}
+		if err := m.generateNames(); err != nil {
+			t.Fatalf("generateNames(%s) error = %v", tt.table, err)
+		}
+
+		if m.PackageName != tt.pkg || m.StructName != tt.structName || m.fileName != tt.fileName {
+			t.Errorf("generateNames(%s, %q) = (%s, %s, %s), want (%s, %s, %s)",
+				tt.table, tt.naming, m.PackageName, m.StructName, m.fileName, tt.pkg, tt.structName, tt.fileName)
+		}
+	}
+}
+
+func TestModel_parseColumn(t *testing.T) {
+	tests := []struct {
+		line   string
+		want   Field
+		goType string
+	}{
+		{
+			line: "`id` int unsigned NOT NULL AUTO_INCREMENT COMMENT 'id',",
+			want: Field{
+				Name: "Id", JsonName: "id", SQLType: "int unsigned", IsUnsigned: true, IsAutoIncrement: true,
+				Comment: "// id", GormTag: "column:id;type:int unsigned;not null;autoIncrement",
+			},
+			goType: "uint",
+		},
+		{
+			line: "`app_name` varchar(50) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_ai_ci DEFAULT NULL COMMENT 'App Name',",
+			want: Field{
+				Name: "AppName", JsonName: "app_name", SQLType: "varchar(50)", Size: 50, IsNullable: true,
+				Comment: "// App Name", GormTag: "column:app_name;type:varchar(50)",
+			},
+			goType: "string",
+		},
+		{
+			line: "`price` decimal(10,2) NOT NULL DEFAULT '0.00' COMMENT '价格',",
+			want: Field{
+				Name: "Price", JsonName: "price", SQLType: "decimal(10,2)", Size: 10, Scale: 2, DefaultValue: "0.00",
+				Comment: "// 价格", GormTag: "column:price;type:decimal(10,2);not null;default:0.00",
+			},
+			goType: "decimal.Decimal",
+		},
+		{
+			line: "`status` tinyint(1) unsigned NOT NULL DEFAULT '1'",
+			want: Field{
+				Name: "Status", JsonName: "status", SQLType: "tinyint(1) unsigned", Size: 1, IsUnsigned: true, DefaultValue: "1",
+				GormTag: "column:status;type:tinyint(1) unsigned;not null;default:1",
+			},
+			goType: "uint8",
+		},
+		{
+			line: "`login_at` timestamp NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,",
+			want: Field{
+				Name: "LoginAt", JsonName: "login_at", SQLType: "timestamp", IsNullable: true, DefaultValue: "CURRENT_TIMESTAMP",
+				GormTag: "column:login_at;type:timestamp;default:CURRENT_TIMESTAMP",
+			},
+			goType: "time.Time",
+		},
+	}
+
+	m := NewModel()
+	for _, tt := range tests {
+		tt.want.Type = tt.goType
+
+		got, _ := m.parseColumn(tt.line)
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("parseColumn(%q)\n got = %+v\nwant = %+v", tt.line, got, tt.want)
+		}
+	}
+}
+
+func TestModel_generateCodeEnums(t *testing.T) {
+	sql := "CREATE TABLE `shop_orders` (\n" +
+		"  `id` int unsigned NOT NULL AUTO_INCREMENT,\n" +
+		"  `state` enum('Pending','paid','it''s done') NOT NULL DEFAULT 'Pending' COMMENT 'order state',\n" +
+		"  PRIMARY KEY (`id`)\n" +
+		") ENGINE=InnoDB;"
+
+	m := NewModel()
+	m.Enums = true
+	if err := m.parseSQL(sql); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"Pending", "paid", "it's done"}; !reflect.DeepEqual(m.TableFields[0].EnumValues, want) {
+		t.Fatalf("EnumValues = %q, want %q", m.TableFields[0].EnumValues, want)
+	}
+
+	code, err := m.generateCode()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	code, err = formatGoCode(code)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []string{
+		"State OrderState `gorm:",
+		"type OrderState string",
+		"type:enum('Pending','paid','it''s done');not null;default:Pending",
+		`OrderStatePending OrderState = "Pending"`,
+		`OrderStateItsDone OrderState = "it's done"`,
+		"case OrderStatePending, OrderStatePaid, OrderStateItsDone:",
+	} {
+		if !strings.Contains(code, want) {
+			t.Errorf("generated code does not contain %q", want)
+		}
+	}
+}
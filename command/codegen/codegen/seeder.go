@@ -0,0 +1,212 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package codegen
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/iancoleman/strcase"
+)
+
+const defaultSeederOutPath = "app/seeder"
+
+// Seeder generates the seed function skeleton of a model.
+type Seeder struct {
+	Model     *Model // The model to seed; Generate must have been called on it
+	ModelPath string // The model directory the model was generated into, e.g. "app/model"
+	DryRun    bool   // Print the generated code to stdout instead of writing files
+}
+
+// NewSeeder creates a new instance of Seeder for a generated model.
+//
+// Parameters:
+//   - m: A pointer to the Model, after its Generate has been called.
+//   - modelPath: A string representing the model directory relative to the module root.
+func NewSeeder(m *Model, modelPath string) *Seeder {
+	if modelPath == "" {
+		modelPath = defaultModelOutPath
+	}
+
+	return &Seeder{Model: m, ModelPath: modelPath, DryRun: m.DryRun}
+}
+
+// FuncName returns the name of the generated seed function, e.g. "SeedAuthApp" for auth_app.
+func (s *Seeder) FuncName() string {
+	return "Seed" + strcase.ToCamel(s.Model.TableName)
+}
+
+// generateCode generates the Go code of the seed function.
+//
+// Parameters:
+//   - modulePath: A string representing the Go module path of the project.
+//
+// Returns:
+//   - A string containing the generated Go code.
+//   - An error if there is an issue generating the code.
+func (s *Seeder) generateCode(modulePath string) (string, error) {
+	m := s.Model
+
+	var updateColumns, secretColumns []string
+	for _, field := range m.TableFields {
+		if isSecretColumn(field.JsonName) {
+			secretColumns = append(secretColumns, field.JsonName)
+			continue
+		}
+
+		updateColumns = append(updateColumns, fmt.Sprintf("%q", field.JsonName))
+	}
+
+	modelImport := path.Join(modulePath, filepath.ToSlash(s.ModelPath), filepath.ToSlash(filepath.Dir(m.fileName)))
+
+	tmpl := template.Must(template.New("seeder").Parse(seederTemplate))
+	var result strings.Builder
+	err := tmpl.Execute(&result, map[string]interface{}{
+		"FuncName":        s.FuncName(),
+		"ModelImport":     modelImport,
+		"Package":         m.PackageName,
+		"StructName":      m.StructName,
+		"StructNameLower": strcase.ToLowerCamel(m.StructName),
+		"TableName":       m.TableName,
+		"UpdateColumns":   strings.Join(updateColumns, ", "),
+		"SecretColumns":   strings.Join(secretColumns, ", "),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	return result.String(), nil
+}
+
+// Generate generates the seed function of the model into outputPath/<table>.go.
+//
+// Parameters:
+//   - force: A boolean indicating whether to overwrite existing files.
+//   - outputPath: A string representing the seeder directory; defaults to app/seeder.
+//
+// Returns:
+//   - An error if there is an issue during the generation process.
+func (s *Seeder) Generate(force bool, outputPath string) error {
+	if outputPath == "" {
+		outputPath = defaultSeederOutPath
+	}
+
+	modulePath, err := readModulePath("go.mod")
+	if err != nil {
+		return err
+	}
+
+	code, err := s.generateCode(modulePath)
+	if err != nil {
+		return fmt.Errorf("error generating seeder: %w", err)
+	}
+
+	formattedContent, err := formatGoCode(code)
+	if err != nil {
+		return err
+	}
+
+	outputPath = filepath.Join(outputPath, s.Model.TableName+".go")
+
+	log.Printf("Starting to write Seeder file: %s\n", outputPath)
+
+	if s.DryRun {
+		return s.Model.previewModelFile(force, outputPath, formattedContent)
+	}
+
+	if !force {
+		if _, err = os.Stat(outputPath); err == nil {
+			log.Printf("%s already exists, not overwriting\n", outputPath)
+			return nil
+		} else if !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	if err = os.MkdirAll(filepath.Dir(outputPath), os.ModePerm); err != nil {
+		return err
+	}
+
+	if err = os.WriteFile(outputPath, []byte(formattedContent), 0644); err != nil {
+		return fmt.Errorf("error writing file: %w", err)
+	}
+
+	log.Printf("%s has been successfully generated, add it to seeder.Register to run it\n", s.FuncName())
+
+	return nil
+}
+
+// isSecretColumn reports whether column holds a credential, e.g. app_secret or password.
+//
+// Seeders never write these columns: rows would store them in clear in the source tree and
+// bypass the repositories, which own credentials and their caches.
+func isSecretColumn(column string) bool {
+	column = strings.ToLower(column)
+	return strings.Contains(column, "secret") || strings.Contains(column, "password")
+}
+
+// readModulePath returns the module path declared in a go.mod file.
+//
+// Parameters:
+//   - goModPath: A string representing the path of the go.mod file.
+//
+// Returns:
+//   - A string containing the module path.
+//   - An error if the file cannot be read or declares no module.
+func readModulePath(goModPath string) (string, error) {
+	content, err := os.ReadFile(goModPath)
+	if err != nil {
+		return "", fmt.Errorf("error reading go.mod: %w", err)
+	}
+
+	for _, line := range strings.Split(string(content), "\n") {
+		if modulePath, ok := strings.CutPrefix(strings.TrimSpace(line), "module "); ok {
+			return strings.Trim(strings.TrimSpace(modulePath), `"`), nil
+		}
+	}
+
+	return "", fmt.Errorf("no module declared in %s", goModPath)
+}
+
+const seederTemplate = `// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package seeder
+
+import (
+	"context"
+
+	"{{.ModelImport}}"
+	"gorm.io/gorm"
+)
+
+// {{.FuncName}} seeds the {{.TableName}} table.
+//
+// Rows are upserted on their primary key, so running it again updates them instead of duplicating them.
+{{- if .SecretColumns}}
+// The secret columns ({{.SecretColumns}}) are never seeded: leave them empty in the rows and set
+// them through the repository, e.g. by rotating the secret.
+{{- end}}
+//
+// Parameters:
+//   - ctx: context.Context for managing request-scoped values, cancellation signals, and deadlines.
+//   - db: *gorm.DB database connection.
+//
+// Returns:
+//   - error: error if seeding fails, otherwise nil.
+func {{.FuncName}}(ctx context.Context, db *gorm.DB) error {
+	{{.StructNameLower}}s := []{{.Package}}.{{.StructName}}{
+		// TODO: add the rows to seed, e.g. {Model: gorm.Model{ID: 1}, ...}
+	}
+
+	return (&{{.Package}}.{{.StructName}}{}).BatchUpsert(ctx, db, {{.StructNameLower}}s, []string{"id"}, []string{ {{- .UpdateColumns -}} })
+}
+`
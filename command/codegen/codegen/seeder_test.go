@@ -0,0 +1,34 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package codegen
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSeeder_generateCodeSkipsSecrets(t *testing.T) {
+	m := &Model{
+		TableName:   "auth_app",
+		PackageName: "auth",
+		StructName:  "App",
+		fileName:    filepath.Join("auth", "app.go"),
+		TableFields: []Field{{JsonName: "app_id"}, {JsonName: "app_name"}, {JsonName: "app_secret"}, {JsonName: "status"}},
+	}
+
+	code, err := NewSeeder(m, "").generateCode("github.com/seakee/go-api")
+	if err != nil {
+		t.Fatalf("generateCode() error = %v", err)
+	}
+
+	if want := `[]string{"app_id", "app_name", "status"}`; !strings.Contains(code, want) {
+		t.Errorf("generated seeder doesn't update %s:\n%s", want, code)
+	}
+
+	if strings.Contains(code, `"app_secret"`) {
+		t.Errorf("generated seeder upserts app_secret:\n%s", code)
+	}
+}
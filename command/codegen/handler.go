@@ -17,21 +17,26 @@ import (
 func main() {
 	// Define command line flags
 	force := flag.Bool("force", false, "force overwrite existing files")
+	dryRun := flag.Bool("dry-run", false, "print the generated code instead of writing files")
+	enums := flag.Bool("enums", false, "generate a named string type with constants for enum columns")
+	naming := flag.String("naming", string(codegen.NamingPackagePrefix), "naming convention: \"prefix\" (auth_user_tokens → auth.UserToken) or \"last-two\" (auth_user_tokens → user.Tokens)")
 	name := flag.String("name", "", "SQL file name (without .sql extension) in the bin/data/sql directory to generate code for")
 	sqlPath := flag.String("sql", "bin/data/sql", "SQL directory")
 	modelOutputPath := flag.String("model", "app/model", "Model directory")
 	repoOutputPath := flag.String("repo", "app/repository", "Repository directory")
 	serviceOutputPath := flag.String("service", "app/service", "Service directory")
+	seeder := flag.Bool("seeder", false, "also generate a seed function skeleton for each model")
+	seederOutputPath := flag.String("seeder-dir", "app/seeder", "Seeder directory")
 
 	// Parse command line flags
 	flag.Parse()
 
 	if *name != "" {
 		// If the name parameter is provided, process a single SQL file
-		processSingleSQLFile(*force, *name, *sqlPath, *modelOutputPath, *repoOutputPath, *serviceOutputPath)
+		processSingleSQLFile(*force, *dryRun, codegen.Naming(*naming), *enums, *name, *sqlPath, *modelOutputPath, *repoOutputPath, *serviceOutputPath, seederPath(*seeder, *seederOutputPath))
 	} else {
 		// Otherwise, process all SQL files in the sqlPath directory
-		processSQLDirectory(*force, *sqlPath, *modelOutputPath, *repoOutputPath, *serviceOutputPath)
+		processSQLDirectory(*force, *dryRun, codegen.Naming(*naming), *enums, *sqlPath, *modelOutputPath, *repoOutputPath, *serviceOutputPath, seederPath(*seeder, *seederOutputPath))
 	}
 }
 
@@ -39,14 +44,21 @@ func main() {
 //
 // Parameters:
 //   - force: whether to force overwrite existing files
+//   - dryRun: whether to print the generated code instead of writing files
+//   - naming: the convention deriving package and struct names from table names
+//   - enums: whether to generate named types with constants for enum columns
 //   - name: SQL file name (without .sql extension)
 //   - sqlPath: directory where the SQL file is located
 //   - modelOutputPath: directory to output the generated model code
 //   - repoOutputPath: directory to output the generated repository code
 //   - serviceOutputPath: directory to output the generated service code
-func processSingleSQLFile(force bool, name, sqlPath, modelOutputPath, repoOutputPath, serviceOutputPath string) {
+//   - seederOutputPath: directory to output the generated seeder code; empty to skip seeders
+func processSingleSQLFile(force, dryRun bool, naming codegen.Naming, enums bool, name, sqlPath, modelOutputPath, repoOutputPath, serviceOutputPath, seederOutputPath string) {
 	// Create a new Model instance
 	m := codegen.NewModel()
+	m.DryRun = dryRun
+	m.Naming = naming
+	m.Enums = enums
 
 	// Construct the full path to the SQL file
 	sqlFilePath := filepath.Join(sqlPath, name+".sql")
@@ -55,6 +67,12 @@ func processSingleSQLFile(force bool, name, sqlPath, modelOutputPath, repoOutput
 		log.Fatalf("Failed to generate model from %s: %v", sqlFilePath, err)
 	}
 
+	if seederOutputPath != "" {
+		if err := codegen.NewSeeder(m, modelOutputPath).Generate(force, seederOutputPath); err != nil {
+			log.Fatalf("Failed to generate seeder from %s: %v", sqlFilePath, err)
+		}
+	}
+
 	// The following code generates repository and service code, currently commented out
 	// repo := NewRepo(m)
 	// if err := repo.Generate(m, repoOutputPath); err != nil {
@@ -71,11 +89,15 @@ func processSingleSQLFile(force bool, name, sqlPath, modelOutputPath, repoOutput
 //
 // Parameters:
 //   - force: whether to force overwrite existing files
+//   - dryRun: whether to print the generated code instead of writing files
+//   - naming: the convention deriving package and struct names from table names
+//   - enums: whether to generate named types with constants for enum columns
 //   - sqlPath: directory where the SQL files are located
 //   - modelOutputPath: directory to output the generated model code
 //   - repoOutputPath: directory to output the generated repository code
 //   - serviceOutputPath: directory to output the generated service code
-func processSQLDirectory(force bool, sqlPath, modelOutputPath, repoOutputPath, serviceOutputPath string) {
+//   - seederOutputPath: directory to output the generated seeder code; empty to skip seeders
+func processSQLDirectory(force, dryRun bool, naming codegen.Naming, enums bool, sqlPath, modelOutputPath, repoOutputPath, serviceOutputPath, seederOutputPath string) {
 	// Walk through all files in the sqlPath directory
 	err := filepath.Walk(sqlPath, func(path string, info os.FileInfo, err error) error {
 		if err != nil {
@@ -85,11 +107,20 @@ func processSQLDirectory(force bool, sqlPath, modelOutputPath, repoOutputPath, s
 		if !info.IsDir() && filepath.Ext(path) == ".sql" {
 			// Create a new Model instance
 			m := codegen.NewModel()
+			m.DryRun = dryRun
+			m.Naming = naming
+			m.Enums = enums
 			// Generate the model code
 			if err = m.Generate(force, path, modelOutputPath); err != nil {
 				return err
 			}
 
+			if seederOutputPath != "" {
+				if err = codegen.NewSeeder(m, modelOutputPath).Generate(force, seederOutputPath); err != nil {
+					return err
+				}
+			}
+
 			// The following code generates repository and service code, currently commented out
 			// repo := NewRepo(m)
 			// if err = repo.Generate(m, repoOutputPath); err != nil {
@@ -108,3 +139,16 @@ func processSQLDirectory(force bool, sqlPath, modelOutputPath, repoOutputPath, s
 		log.Fatalf("Failed to process SQL directory %s: %v", sqlPath, err)
 	}
 }
+
+// seederPath returns the seeder output directory, or an empty string when seeders are not generated
+//
+// Parameters:
+//   - enabled: whether seeders are generated
+//   - outputPath: directory to output the generated seeder code
+func seederPath(enabled bool, outputPath string) string {
+	if !enabled {
+		return ""
+	}
+
+	return outputPath
+}
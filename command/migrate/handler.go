@@ -0,0 +1,116 @@
+// Copyright 2024 Seakee. All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/seakee/go-api/app/config"
+	"github.com/seakee/go-api/command/migrate/migrate"
+	"github.com/sk-pkg/mysql"
+)
+
+// main is the entry point of the program
+// It loads the application configuration, connects to the configured MySQL database and applies
+// or rolls back the migrations in the migration directory.
+//
+// Run it from the project root so the configuration is found, e.g.:
+//
+//	RUN_ENV=dev go run ./command/migrate
+//	RUN_ENV=dev go run ./command/migrate -rollback -steps 2
+func main() {
+	// Define command line flags
+	dir := flag.String("dir", "bin/data/migrations", "Migration directory")
+	dbName := flag.String("db", "", "Name of the MySQL database to migrate; defaults to the first enabled one")
+	rollback := flag.Bool("rollback", false, "roll back applied migrations instead of applying new ones")
+	steps := flag.Int("steps", 1, "number of migrations to roll back")
+
+	// Parse command line flags
+	flag.Parse()
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+
+	db, err := openMySQL(cfg, *dbName)
+	if err != nil {
+		log.Fatalf("Failed to connect to MySQL: %v", err)
+	}
+
+	m, err := migrate.NewMigrator(db, *dir)
+	if err != nil {
+		log.Fatalf("Failed to load migrations from %s: %v", *dir, err)
+	}
+
+	ctx := context.Background()
+
+	if *rollback {
+		done, err := m.Rollback(ctx, *steps)
+		for _, migration := range done {
+			log.Printf("Rolled back %s_%s", migration.Version, migration.Name)
+		}
+
+		if err != nil {
+			log.Fatalf("Failed to roll back migrations: %v", err)
+		}
+
+		return
+	}
+
+	done, err := m.Up(ctx)
+	for _, migration := range done {
+		log.Printf("Applied %s_%s", migration.Version, migration.Name)
+	}
+
+	if err != nil {
+		log.Fatalf("Failed to apply migrations: %v", err)
+	}
+
+	if len(done) == 0 {
+		log.Println("No new migrations to apply")
+	}
+}
+
+// openMySQL connects to the named MySQL database of the configuration
+//
+// Parameters:
+//   - cfg: the application configuration
+//   - name: the database name; the first enabled MySQL database is used when empty
+//
+// Returns:
+//   - *sql.DB: the database connection
+//   - error: an error if the database is not configured or the connection fails
+func openMySQL(cfg *config.Config, name string) (*sql.DB, error) {
+	for _, db := range cfg.Databases {
+		if !db.Enable || db.DbType != "mysql" || (name != "" && db.DbName != name) {
+			continue
+		}
+
+		d, err := mysql.New(mysql.WithConfigs(
+			mysql.Config{
+				User:     db.DbUsername,
+				Password: db.DbPassword,
+				Host:     db.DbHost,
+				DBName:   db.DbName,
+			}),
+			mysql.WithConnMaxLifetime(db.DbMaxLifetime*time.Hour),
+			mysql.WithMaxIdleConn(db.DbMaxIdleConn),
+			mysql.WithMaxOpenConn(db.DbMaxOpenConn),
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		return d.DB()
+	}
+
+	return nil, fmt.Errorf("no enabled MySQL database %q in config", name)
+}
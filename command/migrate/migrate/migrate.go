@@ -0,0 +1,346 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package migrate applies and rolls back versioned SQL migrations.
+//
+// A migration is a pair of files in the migration directory named
+// "<version>_<name>.up.sql" and "<version>_<name>.down.sql", e.g.
+// "20240801000000_create_auth_app.up.sql". Versions are numbers, usually the
+// creation time, applied in ascending numeric order and recorded in the
+// schema_migrations table.
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultMigrationPath = "bin/data/migrations"
+	migrationTable       = "schema_migrations"
+	upSuffix             = ".up.sql"
+	downSuffix           = ".down.sql"
+)
+
+// Migration represents one versioned schema change.
+type Migration struct {
+	Version string // The version of the migration, taken from the file name prefix
+	Name    string // The descriptive name of the migration
+	Up      string // The SQL applying the migration
+	Down    string // The SQL reverting the migration; empty if there is no .down.sql file
+}
+
+// Migrator applies and rolls back migrations on a database.
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+// NewMigrator creates a Migrator for the migrations found in dir.
+//
+// Parameters:
+//   - db: The database to migrate.
+//   - dir: The migration directory; defaults to bin/data/migrations when empty.
+//
+// Returns:
+//   - *Migrator: A new Migrator instance.
+//   - error: An error if the migrations could not be loaded.
+func NewMigrator(db *sql.DB, dir string) (*Migrator, error) {
+	if dir == "" {
+		dir = defaultMigrationPath
+	}
+
+	migrations, err := Load(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Load reads the migrations in dir, sorted by numeric version, so "10_x" comes after "9_x".
+//
+// Parameters:
+//   - dir: The migration directory.
+//
+// Returns:
+//   - []Migration: The migrations sorted by version.
+//   - error: An error if the directory could not be read, a version is not a number, two
+//     versions are the same number, e.g. "9" and "09", or a migration has no .up.sql file.
+func Load(dir string) ([]Migration, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("read migration directory failed: %w", err)
+	}
+
+	byVersion := make(map[string]*Migration)
+	numbers := make(map[string]uint64)
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		fileName := entry.Name()
+
+		var base string
+		var up bool
+		switch {
+		case strings.HasSuffix(fileName, upSuffix):
+			base, up = strings.TrimSuffix(fileName, upSuffix), true
+		case strings.HasSuffix(fileName, downSuffix):
+			base = strings.TrimSuffix(fileName, downSuffix)
+		default:
+			continue
+		}
+
+		version, name, _ := strings.Cut(base, "_")
+
+		number, err := strconv.ParseUint(version, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s has no numeric version", fileName)
+		}
+
+		content, err := os.ReadFile(filepath.Join(dir, fileName))
+		if err != nil {
+			return nil, fmt.Errorf("read migration %s failed: %w", fileName, err)
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			for other, n := range numbers {
+				if n == number {
+					return nil, fmt.Errorf("migrations %s and %s have the same version", other, version)
+				}
+			}
+
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+			numbers[version] = number
+		}
+
+		if up {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Up == "" {
+			return nil, fmt.Errorf("migration %s has no %s file", m.Version, upSuffix)
+		}
+
+		migrations = append(migrations, *m)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return numbers[migrations[i].Version] < numbers[migrations[j].Version]
+	})
+
+	return migrations, nil
+}
+
+// Up applies all migrations that have not been applied yet, in version order.
+//
+// Each migration runs in its own transaction together with its schema_migrations record.
+// Note that MySQL commits DDL statements implicitly, so a failing migration may leave
+// earlier statements of the same file applied.
+//
+// Parameters:
+//   - ctx: Context for the database operations.
+//
+// Returns:
+//   - []Migration: The migrations applied by this call.
+//   - error: An error if a migration fails; migrations before it stay applied.
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; ok {
+			continue
+		}
+
+		err = m.exec(ctx, migration.Up, func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, "INSERT INTO "+migrationTable+" (version) VALUES (?)", migration.Version)
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("apply migration %s failed: %w", migration.Version, err)
+		}
+
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// Rollback reverts the most recently applied migrations using their .down.sql files.
+//
+// Parameters:
+//   - ctx: Context for the database operations.
+//   - steps: The number of migrations to revert.
+//
+// Returns:
+//   - []Migration: The migrations reverted by this call, newest first.
+//   - error: An error if a migration fails or has no .down.sql file.
+func (m *Migrator) Rollback(ctx context.Context, steps int) ([]Migration, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.Version]; !ok {
+			continue
+		}
+
+		if migration.Down == "" {
+			return done, fmt.Errorf("rollback migration %s failed: no %s file", migration.Version, downSuffix)
+		}
+
+		err = m.exec(ctx, migration.Down, func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, "DELETE FROM "+migrationTable+" WHERE version = ?", migration.Version)
+			return err
+		})
+		if err != nil {
+			return done, fmt.Errorf("rollback migration %s failed: %w", migration.Version, err)
+		}
+
+		done = append(done, migration)
+	}
+
+	return done, nil
+}
+
+// applied creates the schema_migrations table if needed and returns the applied versions.
+func (m *Migrator) applied(ctx context.Context) (map[string]struct{}, error) {
+	_, err := m.db.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+migrationTable+
+		" (version varchar(64) NOT NULL PRIMARY KEY, applied_at timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP)")
+	if err != nil {
+		return nil, fmt.Errorf("create %s table failed: %w", migrationTable, err)
+	}
+
+	rows, err := m.db.QueryContext(ctx, "SELECT version FROM "+migrationTable)
+	if err != nil {
+		return nil, fmt.Errorf("query applied migrations failed: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[string]struct{})
+	for rows.Next() {
+		var version string
+		if err = rows.Scan(&version); err != nil {
+			return nil, fmt.Errorf("scan applied migration failed: %w", err)
+		}
+
+		applied[version] = struct{}{}
+	}
+
+	return applied, rows.Err()
+}
+
+// exec runs the statements of script and then record in one transaction.
+func (m *Migrator) exec(ctx context.Context, script string, record func(tx *sql.Tx) error) error {
+	tx, err := m.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	for _, stmt := range splitStatements(script) {
+		if _, err = tx.ExecContext(ctx, stmt); err != nil {
+			_ = tx.Rollback()
+			return err
+		}
+	}
+
+	if err = record(tx); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// splitStatements splits a SQL script into statements on semicolons outside quotes and comments.
+//
+// Line and block comments are dropped, except MySQL executable comments ("/*! ... */"), which
+// are kept whole in their statement.
+//
+// The MySQL driver runs one statement per Exec unless multiStatements is enabled,
+// so scripts with several statements are executed one by one.
+func splitStatements(script string) []string {
+	var (
+		statements []string
+		current    strings.Builder
+		quote      rune
+	)
+
+	flush := func() {
+		if stmt := strings.TrimSpace(current.String()); stmt != "" {
+			statements = append(statements, stmt)
+		}
+		current.Reset()
+	}
+
+	runes := []rune(script)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case quote != 0:
+			current.WriteRune(r)
+			if r == '\\' && quote != '`' && i+1 < len(runes) {
+				i++
+				current.WriteRune(runes[i])
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+			current.WriteRune(r)
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-', r == '#':
+			// Skip line comments
+			for i < len(runes) && runes[i] != '\n' {
+				i++
+			}
+			current.WriteRune('\n')
+		case r == '/' && i+1 < len(runes) && runes[i+1] == '*':
+			// Find the end of the comment, past its "*/"
+			end := i + 2
+			for end+1 < len(runes) && (runes[end] != '*' || runes[end+1] != '/') {
+				end++
+			}
+			end = min(end+2, len(runes))
+
+			if i+2 < len(runes) && runes[i+2] == '!' {
+				current.WriteString(string(runes[i:end]))
+			} else {
+				current.WriteRune(' ')
+			}
+
+			i = end - 1
+		case r == ';':
+			flush()
+		default:
+			current.WriteRune(r)
+		}
+	}
+
+	flush()
+
+	return statements
+}
@@ -0,0 +1,113 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+package migrate
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	script := `-- create table
+CREATE TABLE t (id int, name varchar(10) DEFAULT 'a;b');
+# seed
+INSERT INTO t VALUES (1, 'it\'s; fine'), (2, "x;y");
+UPDATE ` + "`t;`" + ` SET name = 'c';
+/* drop the index; it is unused */
+DROP INDEX i ON t;
+/*!40101 SET NAMES utf8mb4; */;
+SELECT /* inline; */ 1`
+
+	want := []string{
+		"CREATE TABLE t (id int, name varchar(10) DEFAULT 'a;b')",
+		`INSERT INTO t VALUES (1, 'it\'s; fine'), (2, "x;y")`,
+		"UPDATE `t;` SET name = 'c'",
+		"DROP INDEX i ON t",
+		"/*!40101 SET NAMES utf8mb4; */",
+		"SELECT   1",
+	}
+
+	if got := splitStatements(script); !reflect.DeepEqual(got, want) {
+		t.Errorf("splitStatements() = %q, want %q", got, want)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"20240802000000_add_index.up.sql":    "CREATE INDEX i ON t (name);",
+		"20240801000000_create_t.up.sql":     "CREATE TABLE t (id int);",
+		"20240801000000_create_t.down.sql":   "DROP TABLE t;",
+		"20240802000000_add_index.down.sql":  "DROP INDEX i ON t;",
+		"README.md":                          "ignored",
+		"20240803000000_missing_up.down.sql": "SELECT 1;",
+	}
+
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := Load(dir); err == nil {
+		t.Fatal("Load() accepted a migration without an up file")
+	}
+
+	if err := os.Remove(filepath.Join(dir, "20240803000000_missing_up.down.sql")); err != nil {
+		t.Fatal(err)
+	}
+
+	migrations, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	want := []Migration{
+		{Version: "20240801000000", Name: "create_t", Up: "CREATE TABLE t (id int);", Down: "DROP TABLE t;"},
+		{Version: "20240802000000", Name: "add_index", Up: "CREATE INDEX i ON t (name);", Down: "DROP INDEX i ON t;"},
+	}
+
+	if !reflect.DeepEqual(migrations, want) {
+		t.Errorf("Load() = %+v, want %+v", migrations, want)
+	}
+}
+
+func TestLoadNumericVersions(t *testing.T) {
+	write := func(dir string, names ...string) {
+		for _, name := range names {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte("SELECT 1;"), 0o644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	dir := t.TempDir()
+	write(dir, "10_c.up.sql", "9_b.up.sql", "1_a.up.sql")
+
+	migrations, err := Load(dir)
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	var versions []string
+	for _, m := range migrations {
+		versions = append(versions, m.Version)
+	}
+
+	if want := []string{"1", "9", "10"}; !reflect.DeepEqual(versions, want) {
+		t.Errorf("Load() versions = %v, want %v", versions, want)
+	}
+
+	for _, names := range [][]string{{"v1_a.up.sql"}, {"9_a.up.sql", "09_b.up.sql"}} {
+		dir = t.TempDir()
+		write(dir, names...)
+
+		if _, err = Load(dir); err == nil {
+			t.Errorf("Load() of %v accepted invalid versions", names)
+		}
+	}
+}
@@ -0,0 +1,23 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+// Package docs embeds the OpenAPI spec of the API, maintained by hand in openapi.yaml next to
+// the routes it describes, and optionally Swagger UI to browse it.
+package docs
+
+import (
+	_ "embed"
+	"io/fs"
+)
+
+// OpenAPI is the OpenAPI 3 spec of the API, in YAML.
+//
+//go:embed openapi.yaml
+var OpenAPI []byte
+
+// SwaggerUI holds the Swagger UI page (index.html) and its assets.
+//
+// It is nil unless the binary is built with the "swaggerui" tag, after "make swagger-ui"
+// fetched the assets, so regular builds don't carry them.
+var SwaggerUI fs.FS
@@ -0,0 +1,22 @@
+// Copyright 2024 Seakee.  All rights reserved.
+// Use of this source code is governed by a MIT style
+// license that can be found in the LICENSE file.
+
+//go:build swaggerui
+
+package docs
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// swaggerUIFiles are the Swagger UI page and the swagger-ui-dist assets fetched by
+// "make swagger-ui".
+//
+//go:embed swagger-ui/index.html swagger-ui/swagger-ui.css swagger-ui/swagger-ui-bundle.js
+var swaggerUIFiles embed.FS
+
+func init() {
+	SwaggerUI, _ = fs.Sub(swaggerUIFiles, "swagger-ui")
+}
@@ -5,15 +5,20 @@
 package main
 
 import (
+	"context"
 	"github.com/seakee/go-api/app/config"
 	"log"
 	"os"
 	"os/signal"
 	"runtime"
+	"time"
 
 	"github.com/seakee/go-api/bootstrap"
 )
 
+// shutdownTimeout bounds the wait for the in-flight requests and scheduled jobs on shutdown.
+const shutdownTimeout = 10 * time.Second
+
 func main() {
 	runtime.GOMAXPROCS(runtime.NumCPU())
 
@@ -30,6 +35,14 @@ func main() {
 	a.Start()
 
 	s := waitForSignal()
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err = a.Shutdown(ctx); err != nil {
+		log.Println("Shutdown error: ", err)
+	}
+
 	log.Println("Signal received, app closed.", s)
 }
 
@@ -7,6 +7,11 @@ CONFIG_DIR=${CONFIG_DIR:-$(pwd)/bin/configs}
 TZ=${TZ:-Asia/Shanghai}
 RUN_ENV=${RUN_ENV:-local}
 
+# Build metadata injected into app/pkg/buildinfo
+COMMIT=${COMMIT:-$(git rev-parse --short HEAD 2>/dev/null || echo unknown)}
+BUILD_TIME=${BUILD_TIME:-$(date -u +%Y-%m-%dT%H:%M:%SZ)}
+BUILD_INFO_PKG=github.com/seakee/go-api/app/pkg/buildinfo
+
 # Default target that includes formatting, linting, testing, and building
 all() {
   fmt
@@ -25,7 +30,7 @@ fmt() {
 build() {
   echo "Building binary..."
   mkdir -p ./bin  # Ensure the bin directory exists
-  go build -ldflags="-s -w" -o ./bin/$APP_NAME ./main.go  # Build the Go binary
+  go build -ldflags="-s -w -X $BUILD_INFO_PKG.Commit=$COMMIT -X $BUILD_INFO_PKG.BuildTime=$BUILD_TIME" -o ./bin/$APP_NAME ./main.go  # Build the Go binary
 }
 
 # Run the application
@@ -38,7 +43,7 @@ run() {
 docker_build() {
   echo "Building Docker image..."
   docker build -t $IMAGE_NAME .
-  docker build --build-arg TZ=$TZ -t $IMAGE_NAME .
+  docker build --build-arg TZ=$TZ --build-arg COMMIT=$COMMIT --build-arg BUILD_TIME=$BUILD_TIME -t $IMAGE_NAME .
 }
 
 # Run the Docker container